@@ -6,22 +6,37 @@ package main
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
 	"github.com/downloadablefox/twotto/modules/debug"
 	"github.com/downloadablefox/twotto/modules/e621"
 	"github.com/downloadablefox/twotto/modules/extra"
 	"github.com/downloadablefox/twotto/modules/ledger"
+	"github.com/downloadablefox/twotto/modules/remote"
 	"github.com/downloadablefox/twotto/modules/whitelist"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/wire"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
 )
 
 type Config struct {
-	Token       string `usage:"Discord bot token" required:"true" env:"TOKEN"`
-	Debug       bool   `usage:"Enable debug mode" default:"false" env:"DEBUG"`
-	DatabaseURL string `usage:"Postgres database URL" required:"true" env:"DATABASE_URL"`
+	Token       string   `usage:"Discord bot token" required:"true" env:"TOKEN"`
+	Debug       bool     `usage:"Enable debug mode" default:"false" env:"DEBUG"`
+	DatabaseURL string   `usage:"Postgres database URL" required:"true" env:"DATABASE_URL"`
+	DevGuilds   []string `usage:"Guilds commands get registered to in debug mode" env:"DEV_GUILDS"`
+	Modules     []string `usage:"Modules to load" default:"debug,extra,whitelist,ledger,e621" env:"MODULES"`
+	Owners      []string `usage:"IDs of the users who own the bot, the first one is shown as contact" env:"OWNERS"`
+	ShardCount  int      `usage:"Total amount of shards, 0 uses the amount recommended by Discord" default:"1" env:"SHARD_COUNT"`
+	ShardIDs    []int    `usage:"Shards run by this process, all of them when empty" env:"SHARD_IDS"`
+
+	LedgerRetentionDays int `usage:"Days logged messages are kept before being purged, forever when 0" default:"0" env:"LEDGER_RETENTION_DAYS"`
+
+	RecordEvents   string `usage:"File the gateway events get recorded to, recording is disabled when empty" env:"RECORD_EVENTS"`
+	RecordMaxSize  int    `usage:"Size in megabytes the recording is rotated at" default:"100" env:"RECORD_MAX_SIZE"`
+	RecordMaxFiles int    `usage:"Amount of rotated recordings kept" default:"5" env:"RECORD_MAX_FILES"`
 }
 
 func InitializeDatabasePool(config *Config) (*pgxpool.Pool, error) {
@@ -74,36 +89,176 @@ func InitializeFeatureService(pool *pgxpool.Pool) debug.FeatureService {
 	return nil
 }
 
-func bootstrap(client *discordgo.Session, config *Config) error {
+func InitializeJobStore(pool *pgxpool.Pool) core.JobStore {
+	wire.Build(core.NewPostgresJobStore)
+	return nil
+}
+
+// Application holds what has to be torn down when the bot stops.
+type Application struct {
+	Shards    *core.ShardManager
+	Pool      *pgxpool.Pool
+	Modules   *core.ModuleRegistry
+	Scheduler *core.Scheduler
+	Recorder  *core.EventRecorder
+}
+
+func bootstrap(shards *core.ShardManager, config *Config) (*Application, error) {
 	// Set intents
-	client.Identify.Intents = discordgo.IntentGuildMessages | discordgo.IntentGuildMessageReactions | discordgo.IntentGuildMembers | discordgo.IntentGuildBans
-	client.StateEnabled = true
-	client.State.TrackMembers = true
-	client.State.TrackPresences = true
+	shards.Configure(func(s *discordgo.Session) {
+		s.Identify.Intents = discordgo.IntentGuildMessages | discordgo.IntentGuildMessageReactions | discordgo.IntentGuildMembers | discordgo.IntentGuildBans
+		s.StateEnabled = true
+		s.State.TrackMembers = true
+		s.State.TrackPresences = true
+	})
+
+	// REST only operations go through the first shard
+	client := shards.Session()
 
 	pool, err := InitializeDatabasePool(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Register modules
+	// Create command stack, commands get synced once all modules declared theirs
+	commands := core.NewCommandStack()
+	if config.Debug && len(config.DevGuilds) > 0 {
+		commands.SetGuilds(config.DevGuilds...)
+	}
+
+	// Commands are global, syncing them once is enough. Only the first shard
+	// syncs them when it gets the ready event, the other shards don't
+	commandSyncIdent := core.NewIdentifier("core", "events/command-sync")
+	client.AddHandler(core.HandleEvent(core.ApplyMiddlewares(
+		commands.HandleSyncEvent,
+		debug.MidwarePerformance[discordgo.Ready](commandSyncIdent),
+		core.MidwareTimeout[discordgo.Ready](commandSyncIdent),
+	)))
+
+	// Create router
+	router := core.NewInteractionRouter()
+	routerIdent := core.NewIdentifier("core", "router")
+	shards.AddHandler(core.HandleEvent(core.ApplyMiddlewares(
+		router.HandleInteraction,
+		core.MidwareResponder(),
+		debug.MidwareErrorWrap(routerIdent),
+	)))
+
+	// Bot owners can run owner only commands and aren't slowed down by cooldowns
+	if len(config.Owners) == 0 {
+		log.Warn().Msg("[Bootstrap] No owners configured, owner only commands can't be run by anyone")
+	}
+	core.SetOwners(config.Owners...)
+	core.CooldownBypass = core.IsOwner
+
+	// Jobs are persisted so they survive restarts
+	scheduler := core.NewScheduler(InitializeJobStore(pool), client)
+
+	// Provide the services shared by the modules
+	core.Provide(core.SchedulerKey, scheduler)
 	featureService := InitializeFeatureService(pool)
-	debug.RegisterModule(client, featureService)
-	extra.RegisterModule(client, featureService)
+	core.Provide(debug.FeatureServiceKey, featureService)
+	core.Provide[core.FeatureGate](core.FeatureGateKey, featureService)
+	core.Provide(whitelist.WhitelistManagerKey, InitializeWhitelistManager(pool))
+	core.Provide(ledger.LedgerManagerKey, InitializeLedgerManager(client, pool))
+	core.Provide[e621.IE621Service](e621.E621ServiceKey, e621.NewE621Service("twotto/1.0 (DownloadableFox)"))
+
+	// Register modules, only the ones enabled in the config get loaded
+	modules := core.NewModuleRegistry()
+	if err := modules.Register(
+		debug.NewModule(),
+		extra.NewModule(),
+		whitelist.NewModule(),
+		ledger.NewModule(time.Duration(config.LedgerRetentionDays)*24*time.Hour),
+		e621.NewModule(),
+		remote.NewModule(InitializeFiberServer()),
+	); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	bot := &core.Bot{
+		Session:   client,
+		Shards:    shards,
+		Router:    router,
+		Commands:  commands,
+		Container: core.Services(),
+		Events:    core.Events(),
+		Scheduler: scheduler,
+		Handlers:  core.NewHandlerRegistry(),
+	}
 
-	whitelistManager := InitializeWhitelistManager(pool)
-	whitelist.RegisterModule(client, whitelistManager)
+	// Paginators and confirmation prompts are used across modules
+	if err := core.RegisterComponents(bot, core.PaginatorComponent, core.ConfirmComponent); err != nil {
+		pool.Close()
+		return nil, err
+	}
 
-	ledgerManager := InitializeLedgerManager(client, pool)
-	ledger.RegisterModule(client, ledgerManager)
+	if _, err := modules.Load(bot, config.Modules); err != nil {
+		pool.Close()
+		return nil, err
+	}
 
-	e621Client := e621.NewE621Service("twotto/1.0 (DownloadableFox)")
-	e621.RegisterModule(client, e621Client)
+	moduleReadyIdent := core.NewIdentifier("core", "events/module-ready")
+	shards.AddHandler(core.HandleEvent(core.ApplyMiddlewares(
+		modules.HandleReadyEvent,
+		debug.MidwarePerformance[discordgo.Ready](moduleReadyIdent),
+		core.MidwareTimeout[discordgo.Ready](moduleReadyIdent),
+	)))
 
-	/*
-		web := InitializeFiberServer()
-		remote.RegisterModule(client, web)
-	*/
+	// Record the raw gateway events when enabled, to replay them with the
+	// replay command
+	var recorder *core.EventRecorder
+	if config.RecordEvents != "" {
+		recorder, err = core.NewEventRecorder(config.RecordEvents, int64(config.RecordMaxSize)<<20, config.RecordMaxFiles)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
 
-	return nil
+		shards.AddHandler(recorder.HandleEvent)
+	}
+
+	return &Application{
+		Shards:    shards,
+		Pool:      pool,
+		Modules:   modules,
+		Scheduler: scheduler,
+		Recorder:  recorder,
+	}, nil
+}
+
+// Shutdown tears the bot down in order: stop taking events, wait for the
+// running handlers and jobs, stop the scheduler, shut the modules down, close
+// the database pool, the gateway connections and finally the event recording.
+func (a *Application) Shutdown(timeout time.Duration) {
+	core.BeginShutdown()
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), timeout)
+	defer cancelDrain()
+
+	if err := core.Drain(drainCtx); err != nil {
+		log.Warn().Err(err).Msg("[Shutdown] Cancelling the handlers still running")
+	}
+	core.CancelRootContext()
+	a.Scheduler.Stop()
+
+	modulesCtx, cancelModules := context.WithTimeout(context.Background(), timeout)
+	defer cancelModules()
+
+	if err := a.Modules.Shutdown(modulesCtx); err != nil {
+		log.Error().Err(err).Msg("[Shutdown] Failed to shut down modules!")
+	}
+
+	a.Pool.Close()
+
+	if err := a.Shards.Close(); err != nil {
+		log.Error().Err(err).Msg("[Shutdown] Failed to close the gateway connections!")
+	}
+
+	if a.Recorder != nil {
+		if err := a.Recorder.Close(); err != nil {
+			log.Error().Err(err).Msg("[Shutdown] Failed to close the event recording!")
+		}
+	}
 }
@@ -1,11 +1,12 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"github.com/bwmarrin/discordgo"
 	"github.com/cristalhq/aconfig"
 	"github.com/downloadablefox/twotto/core"
 	"github.com/rs/zerolog"
@@ -16,6 +17,9 @@ var (
 	BotConfig Config
 )
 
+// ShutdownTimeout bounds how long shutdown waits for handlers and modules.
+const ShutdownTimeout = 15 * time.Second
+
 func init() {
 	// Load config
 	loader := aconfig.LoaderFor(&BotConfig, aconfig.Config{})
@@ -33,29 +37,48 @@ func init() {
 }
 
 func main() {
-	client, err := discordgo.New("Bot " + BotConfig.Token)
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := replay(os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("Failed to replay events!")
+		}
+		return
+	}
+
+	shards, err := core.NewShardManager(BotConfig.Token, BotConfig.ShardCount, BotConfig.ShardIDs...)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create Discord client!")
 	}
 
 	// Bootstrap
-	if err := bootstrap(client, &BotConfig); err != nil {
+	app, err := bootstrap(shards, &BotConfig)
+	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to bootstrap bot!")
 	}
 
+	// Jobs only run in the bot, replays leave them alone
+	if err := app.Scheduler.Start(context.Background()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start the job scheduler!")
+	}
+
 	// Run til end
-	if err := client.Open(); err != nil {
+	if err := shards.Open(); err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to Discord!")
 	}
-	defer client.Close()
 
 	log.Info().Msg("[Main] Bot is set and running!")
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
 
-	log.Warn().Msg("[Main] Stop signal sent! Stopping bot now...")
+	exitCode := 0
+	select {
+	case <-sc:
+		log.Warn().Msg("[Main] Stop signal sent! Stopping bot now...")
+	case exitCode = <-core.ShutdownRequested():
+		log.Warn().Msgf("[Main] Shutdown requested with exit code %d! Stopping bot now...", exitCode)
+	}
+
+	app.Shutdown(ShutdownTimeout)
+	log.Info().Msg("[Main] Bot stopped")
 
-	// Remove all commands
-	core.UnregisterAllCommands(client)
+	os.Exit(exitCode)
 }
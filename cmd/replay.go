@@ -0,0 +1,226 @@
+//go:build replay
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
+	"github.com/downloadablefox/twotto/core/discordtest"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	errReplayUsage     = errors.New("usage: twotto replay -database-url url [-allow-writes] [-guild ids] [-type types] <file>...")
+	errReplayLiveWrite = errors.New("refusing to replay against the database of the bot, pass -allow-writes to do it anyway")
+)
+
+// replayDoneEvent is dispatched after the replayed events. Handlers run one
+// event after the other while replaying, so every replayed event has been
+// handled once it's received.
+const replayDoneEvent = "TWOTTO_REPLAY_DONE"
+
+type replayFilter struct {
+	guilds map[string]bool
+	types  map[string]bool
+}
+
+func splitSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+
+	return set
+}
+
+func (f *replayFilter) match(e *core.RecordedEvent) bool {
+	// The stand-in sends its own ready event
+	if e.Type == "READY" || e.Type == "RESUMED" {
+		return false
+	}
+
+	if len(f.guilds) > 0 && !f.guilds[e.GuildId] {
+		return false
+	}
+
+	// Guilds are always replayed, handlers rely on their state
+	return e.Type == "GUILD_CREATE" || len(f.types) == 0 || f.types[e.Type]
+}
+
+// replay feeds recorded gateway events back through the handlers of the bot.
+// Discord is replaced by a local stand-in, so the REST calls the handlers make
+// are logged instead of sent. Handlers still write to the database, so it has
+// to be given explicitly, e.g. a copy of the one the events were recorded
+// with; the one of the bot is refused unless writes are allowed.
+func replay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	databaseURL := flags.String("database-url", "", "Postgres database URL the handlers use, required")
+	allowWrites := flags.Bool("allow-writes", false, "Allow replaying against the database of the bot")
+	guilds := flags.String("guild", "", "Only replay the events of these guilds, comma separated")
+	types := flags.String("type", "", "Only replay these event types, comma separated (e.g. MESSAGE_DELETE)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 || *databaseURL == "" {
+		return errReplayUsage
+	}
+
+	if *databaseURL == BotConfig.DatabaseURL && !*allowWrites {
+		return errReplayLiveWrite
+	}
+
+	filter := &replayFilter{guilds: splitSet(*guilds), types: splitSet(strings.ToUpper(*types))}
+
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	shards, err := core.NewShardManager(BotConfig.Token, 1)
+	if err != nil {
+		return err
+	}
+
+	// Handle events in order, so they are replayed like they happened
+	shards.Configure(func(s *discordgo.Session) {
+		s.SyncEvents = true
+	})
+
+	config := BotConfig
+	config.DatabaseURL = *databaseURL
+	config.RecordEvents = ""
+
+	// The remote API would clash with the one of the running bot
+	config.Modules = slices.DeleteFunc(slices.Clone(config.Modules), func(name string) bool {
+		return name == "remote"
+	})
+
+	app, err := bootstrap(shards, &config)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	shards.AddHandler(func(s *discordgo.Session, e *discordgo.Event) {
+		if e.Type == replayDoneEvent {
+			close(done)
+		}
+	})
+
+	if err := shards.Open(); err != nil {
+		app.Shutdown(ShutdownTimeout)
+		return err
+	}
+
+	replayed := 0
+	for _, path := range flags.Args() {
+		count, err := replayFile(server, path, filter)
+		replayed += count
+
+		if err != nil {
+			app.Shutdown(ShutdownTimeout)
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := server.DispatchRaw(replayDoneEvent, json.RawMessage("{}")); err != nil {
+		app.Shutdown(ShutdownTimeout)
+		return err
+	}
+	<-done
+
+	app.Shutdown(ShutdownTimeout)
+
+	calls := server.Calls()
+	for _, call := range calls {
+		entry := log.Info()
+		if len(call.Body) > 0 {
+			entry = entry.RawJSON("body", call.Body)
+		}
+		entry.Msgf("[Replay] %s %s", call.Method, call.Path)
+	}
+
+	log.Info().Msgf("[Replay] Replayed %d events, handlers made %d REST calls", replayed, len(calls))
+	return nil
+}
+
+func replayFile(server *discordtest.Server, path string, filter *replayFilter) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	replayed := 0
+	err = core.ReadEvents(file, func(e *core.RecordedEvent) error {
+		if !filter.match(e) {
+			return nil
+		}
+
+		if err := seedServer(server, e); err != nil {
+			return fmt.Errorf("invalid %s event: %w", e.Type, err)
+		}
+
+		log.Debug().Msgf("[Replay] Replaying %s event recorded at %s", e.Type, e.Time)
+		if err := server.DispatchRaw(e.Type, e.Data); err != nil {
+			return err
+		}
+
+		replayed++
+		return nil
+	})
+
+	return replayed, err
+}
+
+// seedServer mirrors what the event tells about guilds in the stand-in, so
+// handlers fetching it through REST find it.
+func seedServer(server *discordtest.Server, e *core.RecordedEvent) error {
+	switch e.Type {
+	case "GUILD_CREATE":
+		var guild discordgo.Guild
+		if err := json.Unmarshal(e.Data, &guild); err != nil {
+			return err
+		}
+
+		members := guild.Members
+		guild.Members = nil
+		server.AddGuild(&guild)
+
+		for _, member := range members {
+			server.AddMember(guild.ID, member)
+		}
+	case "CHANNEL_CREATE", "THREAD_CREATE":
+		var channel discordgo.Channel
+		if err := json.Unmarshal(e.Data, &channel); err != nil {
+			return err
+		}
+
+		server.AddChannel(&channel)
+	case "GUILD_MEMBER_ADD":
+		var member discordgo.Member
+		if err := json.Unmarshal(e.Data, &member); err != nil {
+			return err
+		}
+
+		server.AddMember(member.GuildID, &member)
+	case "MESSAGE_CREATE":
+		var message discordgo.Message
+		if err := json.Unmarshal(e.Data, &message); err != nil {
+			return err
+		}
+
+		server.AddMessage(&message)
+	}
+
+	return nil
+}
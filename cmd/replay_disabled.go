@@ -0,0 +1,13 @@
+//go:build !replay
+
+package main
+
+import "errors"
+
+var errReplayUnavailable = errors.New("replay isn't available in this build, rebuild with -tags replay")
+
+// replay needs the stand-in for Discord from core/discordtest, which is test
+// code, so it's only built into the bot with the replay tag.
+func replay(_ []string) error {
+	return errReplayUnavailable
+}
@@ -0,0 +1,868 @@
+package core
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+type CommandBuilder struct {
+	discordgo.ApplicationCommand
+}
+
+func NewCommandBuilder() *CommandBuilder {
+	return &CommandBuilder{}
+}
+
+func (c *CommandBuilder) SetType(commandType discordgo.ApplicationCommandType) *CommandBuilder {
+	c.Type = commandType
+	return c
+}
+
+func (c *CommandBuilder) SetName(name string) *CommandBuilder {
+	c.Name = name
+	return c
+}
+
+func (c *CommandBuilder) SetDescription(description string) *CommandBuilder {
+	c.Description = description
+	return c
+}
+
+func (c *CommandBuilder) SetDMPermission(permission bool) *CommandBuilder {
+	c.DMPermission = &permission
+	return c
+}
+
+func (c *CommandBuilder) SetDefaultMemberPermissions(permissions int64) *CommandBuilder {
+	c.DefaultMemberPermissions = &permissions
+	return c
+}
+
+func (c *CommandBuilder) SetNSFW(nsfw bool) *CommandBuilder {
+	c.NSFW = &nsfw
+	return c
+}
+
+func (c *CommandBuilder) AddNameLocale(locale discordgo.Locale, name string) *CommandBuilder {
+	if c.NameLocalizations == nil {
+		temp := make(map[discordgo.Locale]string)
+		c.NameLocalizations = &temp
+	}
+
+	(*c.NameLocalizations)[locale] = name
+
+	return c
+}
+
+func (c *CommandBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *CommandBuilder {
+	if c.DescriptionLocalizations == nil {
+		temp := make(map[discordgo.Locale]string)
+		c.DescriptionLocalizations = &temp
+	}
+
+	(*c.DescriptionLocalizations)[locale] = description
+
+	return c
+}
+
+// LocalizeDescription sets the description and its translations from the
+// catalog message with the given key.
+func (c *CommandBuilder) LocalizeDescription(catalog *Catalog, key string) *CommandBuilder {
+	description, localizations := catalog.Localize(key)
+	c.Description = description
+	c.DescriptionLocalizations = &localizations
+	return c
+}
+
+type StringOptionSupplier func(*StringOptionBuilder)
+type IntegerOptionSupplier func(*IntegerOptionBuilder)
+type BooleanOptionSupplier func(*BooleanOptionBuilder)
+type UserOptionSupplier func(*UserOptionBuilder)
+type ChannelOptionSupplier func(*ChannelOptionBuilder)
+type RoleOptionSupplier func(*RoleOptionBuilder)
+type MentionableOptionSupplier func(*MentionableOptionBuilder)
+type NumberOptionSupplier func(*NumberOptionBuilder)
+type AttachmentOptionSupplier func(*AttachmentOptionBuilder)
+type SubCommandSupplier func(*SubCommandBuilder)
+type SubCommandGroupSupplier func(*SubCommandGroupBuilder)
+
+type CommandOption interface {
+	Build() *discordgo.ApplicationCommandOption
+}
+
+func (c *CommandBuilder) AddOption(option CommandOption) *CommandBuilder {
+	if c.Options == nil {
+		temp := make([]*discordgo.ApplicationCommandOption, 0)
+		c.Options = temp
+	}
+
+	c.Options = append(c.Options, option.Build())
+	return c
+}
+
+func (c *CommandBuilder) AddStringOption(supplier StringOptionSupplier) *CommandBuilder {
+	builder := NewStringOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddIntegerOption(supplier IntegerOptionSupplier) *CommandBuilder {
+	builder := NewIntegerOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddBooleanOption(supplier BooleanOptionSupplier) *CommandBuilder {
+	builder := NewBooleanOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddUserOption(supplier UserOptionSupplier) *CommandBuilder {
+	builder := NewUserOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddChannelOption(supplier ChannelOptionSupplier) *CommandBuilder {
+	builder := NewChannelOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddRoleOption(supplier RoleOptionSupplier) *CommandBuilder {
+	builder := NewRoleOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddMentionableOption(supplier MentionableOptionSupplier) *CommandBuilder {
+	builder := NewMentionableOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddNumberOption(supplier NumberOptionSupplier) *CommandBuilder {
+	builder := NewNumberOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddAttachmentOption(supplier AttachmentOptionSupplier) *CommandBuilder {
+	builder := NewAttachmentOptionBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddSubCommand(supplier SubCommandSupplier) *CommandBuilder {
+	builder := NewSubCommandBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+func (c *CommandBuilder) AddSubCommandGroup(supplier SubCommandGroupSupplier) *CommandBuilder {
+	builder := NewSubCommandGroupBuilder()
+	supplier(builder)
+	c.AddOption(builder)
+	return c
+}
+
+// Build validates the command and returns it, the returned error joins every
+// problem found so they can all be fixed at once.
+func (c *CommandBuilder) Build() (*discordgo.ApplicationCommand, error) {
+	command := c.ApplicationCommand
+	if err := ValidateCommand(&command); err != nil {
+		return nil, err
+	}
+
+	return &command, nil
+}
+
+// MustBuild is like Build but panics if the command is invalid, it's meant to
+// be used for commands declared as package variables.
+func (c *CommandBuilder) MustBuild() *discordgo.ApplicationCommand {
+	command, err := c.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return command
+}
+
+func addOptionLocale(localizations map[discordgo.Locale]string, locale discordgo.Locale, value string) map[discordgo.Locale]string {
+	if localizations == nil {
+		localizations = make(map[discordgo.Locale]string)
+	}
+
+	localizations[locale] = value
+	return localizations
+}
+
+type StringOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewStringOptionBuilder() *StringOptionBuilder {
+	return &StringOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionString,
+		},
+	}
+}
+
+func (s *StringOptionBuilder) SetName(name string) *StringOptionBuilder {
+	s.Name = name
+	return s
+}
+
+func (s *StringOptionBuilder) SetDescription(description string) *StringOptionBuilder {
+	s.Description = description
+	return s
+}
+
+func (s *StringOptionBuilder) SetRequired(required bool) *StringOptionBuilder {
+	s.Required = required
+	return s
+}
+
+func (s *StringOptionBuilder) SetAutocomplete(autocomplete bool) *StringOptionBuilder {
+	s.Autocomplete = autocomplete
+	return s
+}
+
+func (s *StringOptionBuilder) SetMinLength(min int) *StringOptionBuilder {
+	s.MinLength = &min
+	return s
+}
+
+func (s *StringOptionBuilder) SetMaxLength(max int) *StringOptionBuilder {
+	s.MaxLength = max
+	return s
+}
+
+func (s *StringOptionBuilder) AddChoice(name string, value string) *StringOptionBuilder {
+	s.Choices = append(s.Choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: value})
+	return s
+}
+
+// AddChoices adds pre-built choices, use it for choices with localized names.
+func (s *StringOptionBuilder) AddChoices(choices ...*discordgo.ApplicationCommandOptionChoice) *StringOptionBuilder {
+	s.Choices = append(s.Choices, choices...)
+	return s
+}
+
+func (s *StringOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *StringOptionBuilder {
+	s.NameLocalizations = addOptionLocale(s.NameLocalizations, locale, name)
+	return s
+}
+
+func (s *StringOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *StringOptionBuilder {
+	s.DescriptionLocalizations = addOptionLocale(s.DescriptionLocalizations, locale, description)
+	return s
+}
+
+func (s *StringOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *StringOptionBuilder {
+	s.Description, s.DescriptionLocalizations = catalog.Localize(key)
+	return s
+}
+
+func (s *StringOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &s.ApplicationCommandOption
+}
+
+type IntegerOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewIntegerOptionBuilder() *IntegerOptionBuilder {
+	return &IntegerOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionInteger,
+		},
+	}
+}
+
+func (i *IntegerOptionBuilder) SetName(name string) *IntegerOptionBuilder {
+	i.Name = name
+	return i
+}
+
+func (i *IntegerOptionBuilder) SetDescription(description string) *IntegerOptionBuilder {
+	i.Description = description
+	return i
+}
+
+func (i *IntegerOptionBuilder) SetRequired(required bool) *IntegerOptionBuilder {
+	i.Required = required
+	return i
+}
+
+func (i *IntegerOptionBuilder) SetAutocomplete(autocomplete bool) *IntegerOptionBuilder {
+	i.Autocomplete = autocomplete
+	return i
+}
+
+func (i *IntegerOptionBuilder) SetMin(min int) *IntegerOptionBuilder {
+	value := float64(min)
+	i.MinValue = &value
+	return i
+}
+
+func (i *IntegerOptionBuilder) SetMax(max int) *IntegerOptionBuilder {
+	i.MaxValue = float64(max)
+	return i
+}
+
+func (i *IntegerOptionBuilder) AddChoice(name string, value int) *IntegerOptionBuilder {
+	i.Choices = append(i.Choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: value})
+	return i
+}
+
+func (i *IntegerOptionBuilder) AddChoices(choices ...*discordgo.ApplicationCommandOptionChoice) *IntegerOptionBuilder {
+	i.Choices = append(i.Choices, choices...)
+	return i
+}
+
+func (i *IntegerOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *IntegerOptionBuilder {
+	i.NameLocalizations = addOptionLocale(i.NameLocalizations, locale, name)
+	return i
+}
+
+func (i *IntegerOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *IntegerOptionBuilder {
+	i.DescriptionLocalizations = addOptionLocale(i.DescriptionLocalizations, locale, description)
+	return i
+}
+
+func (i *IntegerOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *IntegerOptionBuilder {
+	i.Description, i.DescriptionLocalizations = catalog.Localize(key)
+	return i
+}
+
+func (i *IntegerOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &i.ApplicationCommandOption
+}
+
+type BooleanOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewBooleanOptionBuilder() *BooleanOptionBuilder {
+	return &BooleanOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionBoolean,
+		},
+	}
+}
+
+func (b *BooleanOptionBuilder) SetName(name string) *BooleanOptionBuilder {
+	b.Name = name
+	return b
+}
+
+func (b *BooleanOptionBuilder) SetDescription(description string) *BooleanOptionBuilder {
+	b.Description = description
+	return b
+}
+
+func (b *BooleanOptionBuilder) SetRequired(required bool) *BooleanOptionBuilder {
+	b.Required = required
+	return b
+}
+
+func (b *BooleanOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *BooleanOptionBuilder {
+	b.NameLocalizations = addOptionLocale(b.NameLocalizations, locale, name)
+	return b
+}
+
+func (b *BooleanOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *BooleanOptionBuilder {
+	b.DescriptionLocalizations = addOptionLocale(b.DescriptionLocalizations, locale, description)
+	return b
+}
+
+func (b *BooleanOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *BooleanOptionBuilder {
+	b.Description, b.DescriptionLocalizations = catalog.Localize(key)
+	return b
+}
+
+func (b *BooleanOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &b.ApplicationCommandOption
+}
+
+type UserOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewUserOptionBuilder() *UserOptionBuilder {
+	return &UserOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionUser,
+		},
+	}
+}
+
+func (u *UserOptionBuilder) SetName(name string) *UserOptionBuilder {
+	u.Name = name
+	return u
+}
+
+func (u *UserOptionBuilder) SetDescription(description string) *UserOptionBuilder {
+	u.Description = description
+	return u
+}
+
+func (u *UserOptionBuilder) SetRequired(required bool) *UserOptionBuilder {
+	u.Required = required
+	return u
+}
+
+func (u *UserOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *UserOptionBuilder {
+	u.NameLocalizations = addOptionLocale(u.NameLocalizations, locale, name)
+	return u
+}
+
+func (u *UserOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *UserOptionBuilder {
+	u.DescriptionLocalizations = addOptionLocale(u.DescriptionLocalizations, locale, description)
+	return u
+}
+
+func (u *UserOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *UserOptionBuilder {
+	u.Description, u.DescriptionLocalizations = catalog.Localize(key)
+	return u
+}
+
+func (u *UserOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &u.ApplicationCommandOption
+}
+
+type ChannelOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewChannelOptionBuilder() *ChannelOptionBuilder {
+	return &ChannelOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionChannel,
+		},
+	}
+}
+
+func (c *ChannelOptionBuilder) SetName(name string) *ChannelOptionBuilder {
+	c.Name = name
+	return c
+}
+
+func (c *ChannelOptionBuilder) SetDescription(description string) *ChannelOptionBuilder {
+	c.Description = description
+	return c
+}
+
+func (c *ChannelOptionBuilder) SetRequired(required bool) *ChannelOptionBuilder {
+	c.Required = required
+	return c
+}
+
+// AddChannelTypes restricts the channels that can be picked to the given
+// types, every type is allowed when none is added.
+func (c *ChannelOptionBuilder) AddChannelTypes(types ...discordgo.ChannelType) *ChannelOptionBuilder {
+	c.ChannelTypes = append(c.ChannelTypes, types...)
+	return c
+}
+
+func (c *ChannelOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *ChannelOptionBuilder {
+	c.NameLocalizations = addOptionLocale(c.NameLocalizations, locale, name)
+	return c
+}
+
+func (c *ChannelOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *ChannelOptionBuilder {
+	c.DescriptionLocalizations = addOptionLocale(c.DescriptionLocalizations, locale, description)
+	return c
+}
+
+func (c *ChannelOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *ChannelOptionBuilder {
+	c.Description, c.DescriptionLocalizations = catalog.Localize(key)
+	return c
+}
+
+func (c *ChannelOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &c.ApplicationCommandOption
+}
+
+type RoleOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewRoleOptionBuilder() *RoleOptionBuilder {
+	return &RoleOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionRole,
+		},
+	}
+}
+
+func (r *RoleOptionBuilder) SetName(name string) *RoleOptionBuilder {
+	r.Name = name
+	return r
+}
+
+func (r *RoleOptionBuilder) SetDescription(description string) *RoleOptionBuilder {
+	r.Description = description
+	return r
+}
+
+func (r *RoleOptionBuilder) SetRequired(required bool) *RoleOptionBuilder {
+	r.Required = required
+	return r
+}
+
+func (r *RoleOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *RoleOptionBuilder {
+	r.NameLocalizations = addOptionLocale(r.NameLocalizations, locale, name)
+	return r
+}
+
+func (r *RoleOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *RoleOptionBuilder {
+	r.DescriptionLocalizations = addOptionLocale(r.DescriptionLocalizations, locale, description)
+	return r
+}
+
+func (r *RoleOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *RoleOptionBuilder {
+	r.Description, r.DescriptionLocalizations = catalog.Localize(key)
+	return r
+}
+
+func (r *RoleOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &r.ApplicationCommandOption
+}
+
+type MentionableOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewMentionableOptionBuilder() *MentionableOptionBuilder {
+	return &MentionableOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionMentionable,
+		},
+	}
+}
+
+func (m *MentionableOptionBuilder) SetName(name string) *MentionableOptionBuilder {
+	m.Name = name
+	return m
+}
+
+func (m *MentionableOptionBuilder) SetDescription(description string) *MentionableOptionBuilder {
+	m.Description = description
+	return m
+}
+
+func (m *MentionableOptionBuilder) SetRequired(required bool) *MentionableOptionBuilder {
+	m.Required = required
+	return m
+}
+
+func (m *MentionableOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *MentionableOptionBuilder {
+	m.NameLocalizations = addOptionLocale(m.NameLocalizations, locale, name)
+	return m
+}
+
+func (m *MentionableOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *MentionableOptionBuilder {
+	m.DescriptionLocalizations = addOptionLocale(m.DescriptionLocalizations, locale, description)
+	return m
+}
+
+func (m *MentionableOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *MentionableOptionBuilder {
+	m.Description, m.DescriptionLocalizations = catalog.Localize(key)
+	return m
+}
+
+func (m *MentionableOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &m.ApplicationCommandOption
+}
+
+type NumberOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewNumberOptionBuilder() *NumberOptionBuilder {
+	return &NumberOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionNumber,
+		},
+	}
+}
+
+func (n *NumberOptionBuilder) SetName(name string) *NumberOptionBuilder {
+	n.Name = name
+	return n
+}
+
+func (n *NumberOptionBuilder) SetDescription(description string) *NumberOptionBuilder {
+	n.Description = description
+	return n
+}
+
+func (n *NumberOptionBuilder) SetRequired(required bool) *NumberOptionBuilder {
+	n.Required = required
+	return n
+}
+
+func (n *NumberOptionBuilder) SetAutocomplete(autocomplete bool) *NumberOptionBuilder {
+	n.Autocomplete = autocomplete
+	return n
+}
+
+func (n *NumberOptionBuilder) SetMin(min float64) *NumberOptionBuilder {
+	n.MinValue = &min
+	return n
+}
+
+func (n *NumberOptionBuilder) SetMax(max float64) *NumberOptionBuilder {
+	n.MaxValue = max
+	return n
+}
+
+func (n *NumberOptionBuilder) AddChoice(name string, value float64) *NumberOptionBuilder {
+	n.Choices = append(n.Choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: value})
+	return n
+}
+
+func (n *NumberOptionBuilder) AddChoices(choices ...*discordgo.ApplicationCommandOptionChoice) *NumberOptionBuilder {
+	n.Choices = append(n.Choices, choices...)
+	return n
+}
+
+func (n *NumberOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *NumberOptionBuilder {
+	n.NameLocalizations = addOptionLocale(n.NameLocalizations, locale, name)
+	return n
+}
+
+func (n *NumberOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *NumberOptionBuilder {
+	n.DescriptionLocalizations = addOptionLocale(n.DescriptionLocalizations, locale, description)
+	return n
+}
+
+func (n *NumberOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *NumberOptionBuilder {
+	n.Description, n.DescriptionLocalizations = catalog.Localize(key)
+	return n
+}
+
+func (n *NumberOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &n.ApplicationCommandOption
+}
+
+type AttachmentOptionBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewAttachmentOptionBuilder() *AttachmentOptionBuilder {
+	return &AttachmentOptionBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionAttachment,
+		},
+	}
+}
+
+func (a *AttachmentOptionBuilder) SetName(name string) *AttachmentOptionBuilder {
+	a.Name = name
+	return a
+}
+
+func (a *AttachmentOptionBuilder) SetDescription(description string) *AttachmentOptionBuilder {
+	a.Description = description
+	return a
+}
+
+func (a *AttachmentOptionBuilder) SetRequired(required bool) *AttachmentOptionBuilder {
+	a.Required = required
+	return a
+}
+
+func (a *AttachmentOptionBuilder) AddNameLocale(locale discordgo.Locale, name string) *AttachmentOptionBuilder {
+	a.NameLocalizations = addOptionLocale(a.NameLocalizations, locale, name)
+	return a
+}
+
+func (a *AttachmentOptionBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *AttachmentOptionBuilder {
+	a.DescriptionLocalizations = addOptionLocale(a.DescriptionLocalizations, locale, description)
+	return a
+}
+
+func (a *AttachmentOptionBuilder) LocalizeDescription(catalog *Catalog, key string) *AttachmentOptionBuilder {
+	a.Description, a.DescriptionLocalizations = catalog.Localize(key)
+	return a
+}
+
+func (a *AttachmentOptionBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &a.ApplicationCommandOption
+}
+
+type SubCommandBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewSubCommandBuilder() *SubCommandBuilder {
+	return &SubCommandBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionSubCommand,
+		},
+	}
+}
+
+func (s *SubCommandBuilder) SetName(name string) *SubCommandBuilder {
+	s.Name = name
+	return s
+}
+
+func (s *SubCommandBuilder) SetDescription(description string) *SubCommandBuilder {
+	s.Description = description
+	return s
+}
+
+func (s *SubCommandBuilder) AddNameLocale(locale discordgo.Locale, name string) *SubCommandBuilder {
+	s.NameLocalizations = addOptionLocale(s.NameLocalizations, locale, name)
+	return s
+}
+
+func (s *SubCommandBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *SubCommandBuilder {
+	s.DescriptionLocalizations = addOptionLocale(s.DescriptionLocalizations, locale, description)
+	return s
+}
+
+func (s *SubCommandBuilder) LocalizeDescription(catalog *Catalog, key string) *SubCommandBuilder {
+	s.Description, s.DescriptionLocalizations = catalog.Localize(key)
+	return s
+}
+
+func (s *SubCommandBuilder) AddOption(option CommandOption) *SubCommandBuilder {
+	if s.Options == nil {
+		temp := make([]*discordgo.ApplicationCommandOption, 0)
+		s.Options = temp
+	}
+
+	s.Options = append(s.Options, option.Build())
+	return s
+}
+
+func (s *SubCommandBuilder) AddStringOption(supplier StringOptionSupplier) *SubCommandBuilder {
+	builder := NewStringOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddIntegerOption(supplier IntegerOptionSupplier) *SubCommandBuilder {
+	builder := NewIntegerOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddBooleanOption(supplier BooleanOptionSupplier) *SubCommandBuilder {
+	builder := NewBooleanOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddUserOption(supplier UserOptionSupplier) *SubCommandBuilder {
+	builder := NewUserOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddChannelOption(supplier ChannelOptionSupplier) *SubCommandBuilder {
+	builder := NewChannelOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddRoleOption(supplier RoleOptionSupplier) *SubCommandBuilder {
+	builder := NewRoleOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddMentionableOption(supplier MentionableOptionSupplier) *SubCommandBuilder {
+	builder := NewMentionableOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddNumberOption(supplier NumberOptionSupplier) *SubCommandBuilder {
+	builder := NewNumberOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) AddAttachmentOption(supplier AttachmentOptionSupplier) *SubCommandBuilder {
+	builder := NewAttachmentOptionBuilder()
+	supplier(builder)
+	s.AddOption(builder)
+	return s
+}
+
+func (s *SubCommandBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &s.ApplicationCommandOption
+}
+
+type SubCommandGroupBuilder struct {
+	discordgo.ApplicationCommandOption
+}
+
+func NewSubCommandGroupBuilder() *SubCommandGroupBuilder {
+	return &SubCommandGroupBuilder{
+		ApplicationCommandOption: discordgo.ApplicationCommandOption{
+			Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+		},
+	}
+}
+
+func (g *SubCommandGroupBuilder) SetName(name string) *SubCommandGroupBuilder {
+	g.Name = name
+	return g
+}
+
+func (g *SubCommandGroupBuilder) SetDescription(description string) *SubCommandGroupBuilder {
+	g.Description = description
+	return g
+}
+
+func (g *SubCommandGroupBuilder) AddNameLocale(locale discordgo.Locale, name string) *SubCommandGroupBuilder {
+	g.NameLocalizations = addOptionLocale(g.NameLocalizations, locale, name)
+	return g
+}
+
+func (g *SubCommandGroupBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *SubCommandGroupBuilder {
+	g.DescriptionLocalizations = addOptionLocale(g.DescriptionLocalizations, locale, description)
+	return g
+}
+
+func (g *SubCommandGroupBuilder) LocalizeDescription(catalog *Catalog, key string) *SubCommandGroupBuilder {
+	g.Description, g.DescriptionLocalizations = catalog.Localize(key)
+	return g
+}
+
+func (g *SubCommandGroupBuilder) AddSubCommand(supplier SubCommandSupplier) *SubCommandGroupBuilder {
+	builder := NewSubCommandBuilder()
+	supplier(builder)
+	g.Options = append(g.Options, builder.Build())
+	return g
+}
+
+func (g *SubCommandGroupBuilder) Build() *discordgo.ApplicationCommandOption {
+	return &g.ApplicationCommandOption
+}
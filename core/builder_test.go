@@ -0,0 +1,109 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCommandBuilderBuild(t *testing.T) {
+	command, err := NewCommandBuilder().
+		SetName("whitelist").
+		SetDescription("Manage the whitelist").
+		AddSubCommand(func(s *SubCommandBuilder) {
+			s.SetName("add").
+				SetDescription("Whitelist a user").
+				AddStringOption(func(o *StringOptionBuilder) {
+					o.SetName("user-id").SetDescription("The user to whitelist").SetRequired(true).SetMaxLength(20)
+				}).
+				AddBooleanOption(func(o *BooleanOptionBuilder) {
+					o.SetName("silent").SetDescription("Don't announce it")
+				})
+		}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(command.Options) != 1 || command.Options[0].Type != discordgo.ApplicationCommandOptionSubCommand {
+		t.Fatalf("got options %+v, want the add subcommand", command.Options)
+	}
+	if options := command.Options[0].Options; len(options) != 2 || options[0].Name != "user-id" || !options[0].Required || options[0].MaxLength != 20 {
+		t.Errorf("got subcommand options %+v", options)
+	}
+}
+
+func TestCommandBuilderBuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *CommandBuilder
+		want    []string
+	}{
+		{
+			name:    "name and description",
+			builder: NewCommandBuilder().SetName("Ping"),
+			want:    []string{"must be lowercase", "description must be between"},
+		},
+		{
+			name: "optional before required",
+			builder: NewCommandBuilder().SetName("ban").SetDescription("Ban a user").
+				AddStringOption(func(o *StringOptionBuilder) { o.SetName("reason").SetDescription("Why") }).
+				AddUserOption(func(o *UserOptionBuilder) { o.SetName("user").SetDescription("Who").SetRequired(true) }),
+			want: []string{"/ban user: required options must be declared before optional ones"},
+		},
+		{
+			name: "subcommands mixed with options",
+			builder: NewCommandBuilder().SetName("config").SetDescription("Configure").
+				AddSubCommand(func(s *SubCommandBuilder) { s.SetName("show").SetDescription("Show") }).
+				AddBooleanOption(func(o *BooleanOptionBuilder) { o.SetName("all").SetDescription("All") }),
+			want: []string{"subcommands and groups can't be mixed with other options"},
+		},
+		{
+			name: "duplicated option",
+			builder: NewCommandBuilder().SetName("say").SetDescription("Say").
+				AddStringOption(func(o *StringOptionBuilder) { o.SetName("text").SetDescription("Text") }).
+				AddStringOption(func(o *StringOptionBuilder) { o.SetName("text").SetDescription("Text") }),
+			want: []string{"/say text: duplicated option name"},
+		},
+		{
+			name: "string limits",
+			builder: NewCommandBuilder().SetName("say").SetDescription("Say").
+				AddStringOption(func(o *StringOptionBuilder) {
+					o.SetName("text").SetDescription("Text").SetMinLength(10).SetMaxLength(5).SetAutocomplete(true).AddChoice("hi", "hi")
+				}),
+			want: []string{"min length can't be greater than max length", "autocomplete can't be enabled on an option with choices"},
+		},
+		{
+			name:    "context menu description",
+			builder: NewCommandBuilder().SetType(discordgo.UserApplicationCommand).SetName("Report").SetDescription("Report a user"),
+			want:    []string{"context menu commands can't have a description"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			command, err := test.builder.Build()
+			if command != nil || !errors.Is(err, ErrInvalidCommand) {
+				t.Fatalf("got %+v and %v, want %v", command, err, ErrInvalidCommand)
+			}
+
+			for _, want := range test.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("got %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCommandBuilderMustBuildPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild didn't panic on an invalid command")
+		}
+	}()
+
+	NewCommandBuilder().SetName("ping").MustBuild()
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// EventBus delivers the domain events modules publish, e.g. a member kicked
+// by the whitelist, to the modules subscribed to them, so they can react
+// without importing each other. Events are routed by their Go type.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[reflect.Type][]*subscriber
+}
+
+type subscriber struct {
+	identifier *Identifier
+	deliver    func(ctx context.Context, event any)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[reflect.Type][]*subscriber),
+	}
+}
+
+var botEvents = NewEventBus()
+
+// Events returns the event bus of the bot.
+func Events() *EventBus {
+	return botEvents
+}
+
+type eventBusContextKey struct{}
+
+func WithEventBus(ctx context.Context, bus *EventBus) context.Context {
+	return context.WithValue(ctx, eventBusContextKey{}, bus)
+}
+
+// EventBusFrom returns the event bus attached to the context, falling back to
+// the bot event bus.
+func EventBusFrom(ctx context.Context) *EventBus {
+	if bus, ok := ctx.Value(eventBusContextKey{}).(*EventBus); ok {
+		return bus
+	}
+
+	return botEvents
+}
+
+func eventType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Subscribe checks the dependencies of the handler and subscribes it to the
+// events of type T published on the bot event bus, wrapped with its
+// middlewares and its timeout.
+func Subscribe[T any](bot *Bot, handler *EventHandler[T]) error {
+	if handler.Identifier == nil || handler.Handler == nil {
+		return fmt.Errorf("%w: event subscribers need an identifier and a handler", ErrInvalidCommandDeclaration)
+	}
+
+	if err := bot.Container.Validate(requirements(handler.Requires, handler.Feature)...); err != nil {
+		return fmt.Errorf("event subscriber %s: %w", handler.Identifier, err)
+	}
+
+	if handler.Timeout != 0 {
+		SetHandlerTimeout(handler.Identifier, handler.Timeout)
+	}
+
+	middlewares := handler.middlewares()
+	fn := bindBot(bot, ApplyMiddlewares(handler.Handler, middlewares...))
+
+	bus := bot.Events
+	if bus == nil {
+		bus = botEvents
+	}
+
+	bot.Handlers.add(handlerInfo(HandlerSubscriber, handler.Identifier, handler.Feature, handler.Requires, middlewares))
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	typ := eventType[T]()
+	sub := &subscriber{
+		identifier: handler.Identifier,
+		deliver: func(ctx context.Context, event any) {
+			// Events of a guild get the session of the shard serving it
+			if err := fn(ctx, bot.SessionFor(eventGuild(event)), event.(*T)); err != nil {
+				log.Error().Err(err).Msgf("[EventBus] Subscriber \"%s\" failed to handle %T event!", handler.Identifier, event)
+			}
+		},
+	}
+	bus.subscribers[typ] = append(bus.subscribers[typ], sub)
+
+	bot.onRollback(func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+
+		bus.subscribers[typ] = slices.DeleteFunc(bus.subscribers[typ], func(other *subscriber) bool {
+			return other == sub
+		})
+	})
+
+	return nil
+}
+
+// Publish delivers the event to every subscriber of its type on the context's
+// event bus. Subscribers run in the background so publishers aren't slowed
+// down, they keep the values of the context but not its cancellation, and
+// shutdown waits for them like for any other handler.
+func Publish[T any](ctx context.Context, event *T) {
+	bus := EventBusFrom(ctx)
+
+	bus.mu.RLock()
+	subscribers := bus.subscribers[eventType[T]()]
+	bus.mu.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, sub := range subscribers {
+		if !botLifecycle.acquire() {
+			log.Debug().Msgf("[EventBus] Dropped %T event, shutting down", event)
+			return
+		}
+
+		go func(sub *subscriber) {
+			defer botLifecycle.release()
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					// Get stacktrace
+					stacktrace := make([]byte, 4096)
+					count := runtime.Stack(stacktrace, false)
+
+					log.Error().Any("panic", rec).Msgf("[EventBus] Recovered from fatal error in subscriber \"%s\"!", sub.identifier)
+					log.Debug().Msg("[EventBus] Stack trace: \n" + string(stacktrace[:count]))
+				}
+			}()
+
+			sub.deliver(ctx, event)
+		}(sub)
+	}
+}
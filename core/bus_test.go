@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type memberKickedEvent struct {
+	GuildId string
+	UserId  string
+}
+
+type memberBannedEvent struct {
+	GuildId string
+}
+
+func subscribeChannel[T any](t *testing.T, bot *Bot, name string) chan *T {
+	t.Helper()
+
+	received := make(chan *T, 1)
+	err := Subscribe(bot, &EventHandler[T]{
+		Identifier: NewIdentifier("test", "events/"+name),
+		Handler: func(_ context.Context, _ *discordgo.Session, e *T) error {
+			received <- e
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return received
+}
+
+func TestPublishDeliversByType(t *testing.T) {
+	bot := newTestBot(t)
+	kicked := subscribeChannel[memberKickedEvent](t, bot, "kicked")
+	banned := subscribeChannel[memberBannedEvent](t, bot, "banned")
+
+	ctx := WithEventBus(context.Background(), bot.Events)
+	Publish(ctx, &memberKickedEvent{GuildId: "guild", UserId: "user"})
+
+	select {
+	case e := <-kicked:
+		if e.GuildId != "guild" || e.UserId != "user" {
+			t.Errorf("got event %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the kicked event wasn't delivered")
+	}
+
+	select {
+	case e := <-banned:
+		t.Errorf("got banned event %+v for a kick", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPublishKeepsBusesApart(t *testing.T) {
+	bot := newTestBot(t)
+	kicked := subscribeChannel[memberKickedEvent](t, bot, "kicked")
+
+	Publish(WithEventBus(context.Background(), NewEventBus()), &memberKickedEvent{GuildId: "guild"})
+
+	select {
+	case e := <-kicked:
+		t.Errorf("got event %+v published on another bus", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPublishRecoversPanics(t *testing.T) {
+	bot := newTestBot(t)
+	err := Subscribe(bot, &EventHandler[memberKickedEvent]{
+		Identifier: NewIdentifier("test", "events/panics"),
+		Handler: func(_ context.Context, _ *discordgo.Session, _ *memberKickedEvent) error {
+			panic("boom")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kicked := subscribeChannel[memberKickedEvent](t, bot, "kicked")
+
+	Publish(WithEventBus(context.Background(), bot.Events), &memberKickedEvent{GuildId: "guild"})
+
+	select {
+	case <-kicked:
+	case <-time.After(time.Second):
+		t.Fatal("a panicking subscriber kept the others from getting the event")
+	}
+}
+
+func TestPublishWhileShuttingDown(t *testing.T) {
+	useLifecycle(t)
+	bot := newTestBot(t)
+	kicked := subscribeChannel[memberKickedEvent](t, bot, "kicked")
+
+	BeginShutdown()
+	Publish(WithEventBus(context.Background(), bot.Events), &memberKickedEvent{GuildId: "guild"})
+
+	select {
+	case e := <-kicked:
+		t.Errorf("got event %+v while shutting down", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPublishOutlivesPublisherContext(t *testing.T) {
+	bot := newTestBot(t)
+
+	type contextKey struct{}
+	published := make(chan struct{})
+	received := make(chan error, 1)
+	err := Subscribe(bot, &EventHandler[memberKickedEvent]{
+		Identifier: NewIdentifier("test", "events/context"),
+		Handler: func(ctx context.Context, _ *discordgo.Session, _ *memberKickedEvent) error {
+			<-published
+			if ctx.Value(contextKey{}) != "value" {
+				received <- errors.New("the subscriber lost the values of the context")
+			} else {
+				received <- ctx.Err()
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(WithEventBus(context.Background(), bot.Events), contextKey{}, "value"))
+	Publish(ctx, &memberKickedEvent{GuildId: "guild"})
+	cancel()
+	close(published)
+
+	select {
+	case err := <-received:
+		if err != nil {
+			t.Errorf("got %v after the publisher was done", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the event wasn't delivered")
+	}
+}
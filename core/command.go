@@ -0,0 +1,255 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	ErrInvalidCommandDeclaration = errors.New("invalid command declaration")
+	ErrMissingCommandHandler     = errors.New("missing command handler")
+	ErrUnknownSubcommand         = errors.New("handler registered for unknown subcommand")
+)
+
+// Command binds the definition of an application command to the handlers
+// serving it, so declaring, syncing and routing it can't get out of step.
+type Command struct {
+	Identifier *Identifier
+	Definition *discordgo.ApplicationCommand
+
+	// Handler receives every invocation of the command that doesn't have a
+	// more specific handler in Subcommands.
+	Handler EventFunc[discordgo.InteractionCreate]
+
+	// Subcommands maps subcommand paths relative to the command (e.g. "add"
+	// or "config enable") to their handlers.
+	Subcommands map[string]EventFunc[discordgo.InteractionCreate]
+
+	// Autocomplete is required when any option of the command has
+	// autocomplete enabled.
+	Autocomplete EventFunc[discordgo.InteractionCreate]
+
+	// Middlewares wrap every handler of the command, the first one being the
+	// outermost.
+	Middlewares []MiddlewareFunc[discordgo.InteractionCreate]
+
+	// Requires lists the dependencies the handlers resolve with Use, the
+	// command isn't registered unless all of them were provided.
+	Requires []Dependency
+
+	// Feature guards the command, its handlers only run in the guilds the
+	// feature is enabled in.
+	Feature *Identifier
+
+	// Timeout bounds how long the handlers can run, DefaultHandlerTimeout is
+	// used when zero.
+	Timeout time.Duration
+
+	// DeferFlags are the flags of the deferred response sent when a handler
+	// doesn't answer in time.
+	DeferFlags discordgo.MessageFlags
+}
+
+// interactionMiddlewares wraps the middlewares of an interaction handler with
+// the feature gate and the timeout and auto defer ones, commands, components
+// and modals all assemble theirs here.
+func interactionMiddlewares(identifier, feature *Identifier, middlewares []MiddlewareFunc[discordgo.InteractionCreate], deferFlags discordgo.MessageFlags) []MiddlewareFunc[discordgo.InteractionCreate] {
+	assembled := make([]MiddlewareFunc[discordgo.InteractionCreate], 0, len(middlewares)+3)
+	assembled = append(assembled, middlewares...)
+
+	// The gate runs after the middlewares so they can report its errors
+	if feature != nil {
+		assembled = append(assembled, MidwareFeatureGate[discordgo.InteractionCreate](feature))
+	}
+
+	return append(assembled,
+		MidwareTimeout[discordgo.InteractionCreate](identifier),
+		MidwareAutoDefer(deferFlags),
+	)
+}
+
+// Paths returns the path of every invocable leaf of the command, which is the
+// command itself when it has no subcommands.
+func (c *Command) Paths() []string {
+	paths := make([]string, 0)
+	for _, option := range c.Definition.Options {
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionSubCommand:
+			paths = append(paths, CommandPath(c.Definition.Name, option.Name))
+		case discordgo.ApplicationCommandOptionSubCommandGroup:
+			for _, subcommand := range option.Options {
+				paths = append(paths, CommandPath(c.Definition.Name, option.Name, subcommand.Name))
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		paths = append(paths, c.Definition.Name)
+	}
+
+	return paths
+}
+
+func (c *Command) hasAutocomplete() bool {
+	var walk func(options []*discordgo.ApplicationCommandOption) bool
+	walk = func(options []*discordgo.ApplicationCommandOption) bool {
+		for _, option := range options {
+			if option.Autocomplete || walk(option.Options) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return walk(c.Definition.Options)
+}
+
+func (c *Command) handlerFor(path string) EventFunc[discordgo.InteractionCreate] {
+	segments := strings.Split(path, " ")[1:]
+	for i := len(segments); i > 0; i-- {
+		if fn, ok := c.Subcommands[strings.Join(segments[:i], " ")]; ok {
+			return fn
+		}
+	}
+
+	return c.Handler
+}
+
+// Validate checks that every subcommand of the definition has a handler, that
+// no handler points to a subcommand that doesn't exist and that the container
+// holds every dependency of the command.
+func (c *Command) Validate(container *Container) error {
+	if c.Identifier == nil || c.Definition == nil {
+		return fmt.Errorf("%w: commands need an identifier and a definition", ErrInvalidCommandDeclaration)
+	}
+
+	errs := make([]error, 0)
+
+	paths := c.Paths()
+	for _, path := range paths {
+		if c.handlerFor(path) == nil {
+			errs = append(errs, fmt.Errorf("%w: /%s (%s)", ErrMissingCommandHandler, path, c.Identifier))
+		}
+	}
+
+	for subcommand := range c.Subcommands {
+		prefix := CommandPath(c.Definition.Name, subcommand)
+
+		known := false
+		for _, path := range paths {
+			if path == prefix || strings.HasPrefix(path, prefix+" ") {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			errs = append(errs, fmt.Errorf("%w: /%s (%s)", ErrUnknownSubcommand, prefix, c.Identifier))
+		}
+	}
+
+	if c.hasAutocomplete() && c.Autocomplete == nil {
+		errs = append(errs, fmt.Errorf("%w: autocomplete for /%s (%s)", ErrMissingCommandHandler, c.Definition.Name, c.Identifier))
+	}
+
+	if err := container.Validate(requirements(c.Requires, c.Feature)...); err != nil {
+		errs = append(errs, fmt.Errorf("command %s: %w", c.Identifier, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateRoutes checks that no two commands share a name, neither between
+// themselves nor with the commands already routed.
+func validateRoutes(router *InteractionRouter, commands []*Command) error {
+	errs := make([]error, 0)
+
+	names := make(map[string]*Identifier, len(commands))
+	for _, command := range commands {
+		if command.Identifier == nil || command.Definition == nil {
+			continue
+		}
+
+		name := command.Definition.Name
+		if other, ok := names[name]; ok {
+			errs = append(errs, fmt.Errorf("%w: /%s (%s and %s)", ErrDuplicateRoute, name, other, command.Identifier))
+		} else if router.HasCommand(name) {
+			errs = append(errs, fmt.Errorf("%w: /%s (%s)", ErrDuplicateRoute, name, command.Identifier))
+		}
+
+		names[name] = command.Identifier
+	}
+
+	return errors.Join(errs...)
+}
+
+// RegisterCommands validates the commands, adds their definitions to the
+// stack and routes their handlers, wrapped with their middlewares and the
+// timeout and auto defer ones. Nothing is registered if any of them is
+// invalid.
+func RegisterCommands(bot *Bot, commands ...*Command) error {
+	errs := make([]error, 0)
+	for _, command := range commands {
+		if err := command.Validate(bot.Container); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := validateRoutes(bot.Router, commands); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	route := func(table map[string]EventFunc[discordgo.InteractionCreate], path string, fn EventFunc[discordgo.InteractionCreate]) {
+		if err := bot.Router.register(table, path, fn); err != nil {
+			errs = append(errs, err)
+			return
+		}
+
+		bot.onRollback(func() {
+			bot.Router.unregister(table, path)
+		})
+	}
+
+	for _, command := range commands {
+		definition := command.Definition
+		bot.Commands.AddCommand(definition)
+		bot.onRollback(func() {
+			bot.Commands.removeCommand(definition)
+		})
+
+		if command.Timeout != 0 {
+			SetHandlerTimeout(command.Identifier, command.Timeout)
+		}
+		middlewares := interactionMiddlewares(command.Identifier, command.Feature, command.Middlewares, command.DeferFlags)
+
+		if command.Handler != nil {
+			route(bot.Router.commands, definition.Name, bindBot(bot, ApplyMiddlewares(command.Handler, middlewares...)))
+		}
+
+		for subcommand, fn := range command.Subcommands {
+			route(bot.Router.commands, CommandPath(definition.Name, subcommand), bindBot(bot, ApplyMiddlewares(fn, middlewares...)))
+		}
+
+		if command.Autocomplete != nil {
+			route(bot.Router.autocompletes, definition.Name, bindBot(bot, ApplyMiddlewares(command.Autocomplete, middlewares...)))
+		}
+
+		info := handlerInfo(HandlerCommand, command.Identifier, command.Feature, command.Requires, middlewares)
+		for _, path := range command.Paths() {
+			info.Routes = append(info.Routes, "/"+path)
+		}
+		bot.Handlers.add(info)
+	}
+
+	// Only fails if the same routes were registered concurrently
+	return errors.Join(errs...)
+}
@@ -1,9 +1,27 @@
 package core
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrNoGuilds = errors.New("no guilds given")
+
+// CommandStack holds the commands of the bot and the scope they're synced to.
+// Promote and Demote change the scope while shards sync, so it's guarded.
 type CommandStack struct {
+	mu       sync.RWMutex
 	commands []*discordgo.ApplicationCommand
+	guildIds []string
 }
 
 func NewCommandStack() *CommandStack {
@@ -13,23 +31,325 @@ func NewCommandStack() *CommandStack {
 }
 
 func (c *CommandStack) AddCommand(command *discordgo.ApplicationCommand) {
-	c.commands = append(c.commands, command)
+	c.AddCommands(command)
+}
+
+func (c *CommandStack) AddCommands(commands ...*discordgo.ApplicationCommand) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.commands = append(c.commands, commands...)
+}
+
+func (c *CommandStack) removeCommand(command *discordgo.ApplicationCommand) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.commands = slices.DeleteFunc(c.commands, func(other *discordgo.ApplicationCommand) bool {
+		return other == command
+	})
+}
+
+func (c *CommandStack) Commands() []*discordgo.ApplicationCommand {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.commands)
+}
+
+// SetGuilds scopes the stack to the given guilds, commands will be registered
+// to each of them instead of globally. Calling it without guilds makes the
+// stack global again.
+func (c *CommandStack) SetGuilds(guildIds ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.guildIds = slices.Clone(guildIds)
+}
+
+func (c *CommandStack) Guilds() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.guildIds)
+}
+
+func (c *CommandStack) IsGlobal() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.guildIds) == 0
+}
+
+// Sync compares the declared commands with the ones Discord already has for
+// the given guild ("" for global commands) and bulk overwrites them only when
+// something changed. Commands that are no longer declared get pruned.
+func (c *CommandStack) Sync(session *discordgo.Session, guildId string) error {
+	return syncCommands(session, guildId, c.Commands())
+}
+
+// SyncAll syncs the commands to the scope the stack is set to.
+func (c *CommandStack) SyncAll(session *discordgo.Session) error {
+	guildIds := c.Guilds()
+	if len(guildIds) == 0 {
+		return c.Sync(session, "")
+	}
+
+	for _, guildId := range guildIds {
+		if err := c.Sync(session, guildId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Clear removes every command registered for the given guild ("" for global
+// commands).
+func (c *CommandStack) Clear(session *discordgo.Session, guildId string) error {
+	return syncCommands(session, guildId, []*discordgo.ApplicationCommand{})
 }
 
-func (c *CommandStack) For(session *discordgo.Session, guildId string) error {
-	for _, command := range c.commands {
-		if _, err := session.ApplicationCommandCreate(session.State.User.ID, guildId, command); err != nil {
+// Promote moves the commands from the guilds the stack is scoped to into the
+// global scope.
+func (c *CommandStack) Promote(session *discordgo.Session) error {
+	if err := c.Sync(session, ""); err != nil {
+		return err
+	}
+
+	for _, guildId := range c.Guilds() {
+		if err := c.Clear(session, guildId); err != nil {
+			return err
+		}
+	}
+
+	c.SetGuilds()
+	return nil
+}
+
+// Demote moves the commands from the global scope into the given guilds.
+func (c *CommandStack) Demote(session *discordgo.Session, guildIds ...string) error {
+	if len(guildIds) == 0 {
+		return ErrNoGuilds
+	}
+
+	for _, guildId := range guildIds {
+		if err := c.Sync(session, guildId); err != nil {
 			return err
 		}
 	}
 
+	if err := c.Clear(session, ""); err != nil {
+		return err
+	}
+
+	c.SetGuilds(guildIds...)
+	return nil
+}
+
+// HandleSyncEvent syncs the commands when the session gets the ready event.
+// Commands aren't tied to a shard, add it to a single one.
+func (c *CommandStack) HandleSyncEvent(_ context.Context, s *discordgo.Session, e *discordgo.Ready) error {
+	return c.SyncAll(s)
+}
+
+func syncCommands(session *discordgo.Session, guildId string, commands []*discordgo.ApplicationCommand) error {
+	current, err := session.ApplicationCommands(session.State.User.ID, guildId)
+	if err != nil {
+		return err
+	}
+
+	diff := DiffCommands(current, commands)
+	if diff.Empty() {
+		log.Debug().Str("guild", guildId).Str("hash", HashCommands(commands)).Msg("[CommandStack] Commands are up to date, skipping sync")
+		return nil
+	}
+
+	log.Info().
+		Str("guild", guildId).
+		Strs("added", diff.Added).
+		Strs("changed", diff.Changed).
+		Strs("removed", diff.Removed).
+		Msg("[CommandStack] Commands changed, overwriting")
+
+	if _, err := session.ApplicationCommandBulkOverwrite(session.State.User.ID, guildId, commands); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func ApplyCommands(commands ...*discordgo.ApplicationCommand) *CommandStack {
-	stack := NewCommandStack()
+type CommandDiff struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+func (d *CommandDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+func DiffCommands(current, declared []*discordgo.ApplicationCommand) *CommandDiff {
+	diff := &CommandDiff{}
+
+	currentHashes := make(map[string]string, len(current))
+	for _, command := range current {
+		currentHashes[commandKey(command)] = HashCommand(command)
+	}
+
+	declaredKeys := make(map[string]bool, len(declared))
+	for _, command := range declared {
+		key := commandKey(command)
+		declaredKeys[key] = true
+
+		hash, ok := currentHashes[key]
+		if !ok {
+			diff.Added = append(diff.Added, command.Name)
+		} else if hash != HashCommand(command) {
+			diff.Changed = append(diff.Changed, command.Name)
+		}
+	}
+
+	for _, command := range current {
+		if !declaredKeys[commandKey(command)] {
+			diff.Removed = append(diff.Removed, command.Name)
+		}
+	}
+
+	return diff
+}
+
+// HashCommand hashes the fields of a command that Discord stores, ignoring
+// server assigned ones (id, version...) and normalizing the defaults Discord
+// fills in so a declared command hashes the same as the one it returns.
+func HashCommand(command *discordgo.ApplicationCommand) string {
+	data, err := json.Marshal(newCommandSignature(command))
+	if err != nil {
+		panic(err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func HashCommands(commands []*discordgo.ApplicationCommand) string {
+	hashes := make([]string, 0, len(commands))
 	for _, command := range commands {
-		stack.AddCommand(command)
+		hashes = append(hashes, HashCommand(command))
+	}
+	slices.Sort(hashes)
+
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		panic(err)
 	}
-	return stack
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func commandKey(command *discordgo.ApplicationCommand) string {
+	commandType := command.Type
+	if commandType == 0 {
+		commandType = discordgo.ChatApplicationCommand
+	}
+
+	return fmt.Sprintf("%d:%s", commandType, command.Name)
+}
+
+type commandSignature struct {
+	Type                     discordgo.ApplicationCommandType `json:"type"`
+	Name                     string                           `json:"name"`
+	NameLocalizations        map[discordgo.Locale]string      `json:"name_localizations"`
+	Description              string                           `json:"description"`
+	DescriptionLocalizations map[discordgo.Locale]string      `json:"description_localizations"`
+	DefaultMemberPermissions *int64                           `json:"default_member_permissions"`
+	DMPermission             bool                             `json:"dm_permission"`
+	NSFW                     bool                             `json:"nsfw"`
+	Options                  []*optionSignature               `json:"options"`
+}
+
+type optionSignature struct {
+	Type                     discordgo.ApplicationCommandOptionType      `json:"type"`
+	Name                     string                                      `json:"name"`
+	NameLocalizations        map[discordgo.Locale]string                 `json:"name_localizations"`
+	Description              string                                      `json:"description"`
+	DescriptionLocalizations map[discordgo.Locale]string                 `json:"description_localizations"`
+	ChannelTypes             []discordgo.ChannelType                     `json:"channel_types"`
+	Required                 bool                                        `json:"required"`
+	Options                  []*optionSignature                          `json:"options"`
+	Autocomplete             bool                                        `json:"autocomplete"`
+	Choices                  []*discordgo.ApplicationCommandOptionChoice `json:"choices"`
+	MinValue                 *float64                                    `json:"min_value"`
+	MaxValue                 float64                                     `json:"max_value"`
+	MinLength                *int                                        `json:"min_length"`
+	MaxLength                int                                         `json:"max_length"`
+}
+
+func newCommandSignature(command *discordgo.ApplicationCommand) *commandSignature {
+	signature := &commandSignature{
+		Type:                     command.Type,
+		Name:                     command.Name,
+		Description:              command.Description,
+		DefaultMemberPermissions: command.DefaultMemberPermissions,
+		DMPermission:             command.DMPermission == nil || *command.DMPermission,
+		NSFW:                     command.NSFW != nil && *command.NSFW,
+		Options:                  newOptionSignatures(command.Options),
+	}
+
+	if signature.Type == 0 {
+		signature.Type = discordgo.ChatApplicationCommand
+	}
+
+	if command.NameLocalizations != nil && len(*command.NameLocalizations) > 0 {
+		signature.NameLocalizations = *command.NameLocalizations
+	}
+
+	if command.DescriptionLocalizations != nil && len(*command.DescriptionLocalizations) > 0 {
+		signature.DescriptionLocalizations = *command.DescriptionLocalizations
+	}
+
+	return signature
+}
+
+func newOptionSignatures(options []*discordgo.ApplicationCommandOption) []*optionSignature {
+	if len(options) == 0 {
+		return nil
+	}
+
+	signatures := make([]*optionSignature, 0, len(options))
+	for _, option := range options {
+		signature := &optionSignature{
+			Type:         option.Type,
+			Name:         option.Name,
+			Description:  option.Description,
+			Required:     option.Required,
+			Options:      newOptionSignatures(option.Options),
+			Autocomplete: option.Autocomplete,
+			MinValue:     option.MinValue,
+			MaxValue:     option.MaxValue,
+			MinLength:    option.MinLength,
+			MaxLength:    option.MaxLength,
+		}
+
+		if len(option.NameLocalizations) > 0 {
+			signature.NameLocalizations = option.NameLocalizations
+		}
+
+		if len(option.DescriptionLocalizations) > 0 {
+			signature.DescriptionLocalizations = option.DescriptionLocalizations
+		}
+
+		if len(option.ChannelTypes) > 0 {
+			signature.ChannelTypes = option.ChannelTypes
+		}
+
+		if len(option.Choices) > 0 {
+			signature.Choices = option.Choices
+		}
+
+		signatures = append(signatures, signature)
+	}
+
+	return signatures
 }
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func declaredCommand(name, description string) *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: description,
+		Options: []*discordgo.ApplicationCommandOption{{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "query",
+			Description: "What to look for",
+		}},
+	}
+}
+
+// returnedCommand is the command like Discord returns it, with the fields it
+// assigns and the defaults it fills in.
+func returnedCommand(name, description string) *discordgo.ApplicationCommand {
+	dmPermission := true
+	nsfw := false
+	localizations := map[discordgo.Locale]string{}
+
+	command := declaredCommand(name, description)
+	command.ID = "1"
+	command.ApplicationID = "2"
+	command.Version = "3"
+	command.Type = discordgo.ChatApplicationCommand
+	command.DMPermission = &dmPermission
+	command.NSFW = &nsfw
+	command.NameLocalizations = &localizations
+	command.DescriptionLocalizations = &localizations
+	return command
+}
+
+func TestHashCommandIgnoresDiscordDefaults(t *testing.T) {
+	declared := declaredCommand("search", "Search something")
+	returned := returnedCommand("search", "Search something")
+
+	if HashCommand(declared) != HashCommand(returned) {
+		t.Error("a declared command hashes differently than the one Discord returns for it")
+	}
+
+	returned.Options[0].Required = true
+	if HashCommand(declared) == HashCommand(returned) {
+		t.Error("changing an option doesn't change the hash")
+	}
+}
+
+func TestHashCommandsIgnoresOrder(t *testing.T) {
+	a := declaredCommand("a", "A")
+	b := declaredCommand("b", "B")
+
+	if HashCommands([]*discordgo.ApplicationCommand{a, b}) != HashCommands([]*discordgo.ApplicationCommand{b, a}) {
+		t.Error("the hash of the commands depends on their order")
+	}
+}
+
+func TestDiffCommands(t *testing.T) {
+	current := []*discordgo.ApplicationCommand{
+		returnedCommand("kept", "Kept"),
+		returnedCommand("changed", "Old description"),
+		returnedCommand("removed", "Removed"),
+	}
+	declared := []*discordgo.ApplicationCommand{
+		declaredCommand("kept", "Kept"),
+		declaredCommand("changed", "New description"),
+		declaredCommand("added", "Added"),
+	}
+
+	diff := DiffCommands(current, declared)
+	if diff.Empty() {
+		t.Fatal("diff is empty")
+	}
+
+	if !slices.Equal(diff.Added, []string{"added"}) {
+		t.Errorf("added %q, want [added]", diff.Added)
+	}
+	if !slices.Equal(diff.Changed, []string{"changed"}) {
+		t.Errorf("changed %q, want [changed]", diff.Changed)
+	}
+	if !slices.Equal(diff.Removed, []string{"removed"}) {
+		t.Errorf("removed %q, want [removed]", diff.Removed)
+	}
+}
+
+func TestDiffCommandsUpToDate(t *testing.T) {
+	current := []*discordgo.ApplicationCommand{returnedCommand("search", "Search something")}
+	declared := []*discordgo.ApplicationCommand{declaredCommand("search", "Search something")}
+
+	if diff := DiffCommands(current, declared); !diff.Empty() {
+		t.Errorf("got %+v, want an empty diff", diff)
+	}
+}
+
+func TestDiffCommandsKeysByType(t *testing.T) {
+	current := []*discordgo.ApplicationCommand{returnedCommand("report", "")}
+	declared := []*discordgo.ApplicationCommand{{Name: "report", Type: discordgo.MessageApplicationCommand}}
+
+	diff := DiffCommands(current, declared)
+	if !slices.Equal(diff.Added, []string{"report"}) || !slices.Equal(diff.Removed, []string{"report"}) {
+		t.Errorf("got %+v, want the message command added and the chat one removed", diff)
+	}
+}
@@ -0,0 +1,410 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var ErrInvalidComponent = errors.New("invalid component")
+
+// Limits enforced by Discord on message components.
+const (
+	MaxActionRows            = 5
+	MaxActionRowComponents   = 5
+	MaxButtonLabelLength     = 80
+	MaxSelectOptions         = 25
+	MaxSelectOptionLength    = 100
+	MaxSelectPlaceholderSize = 150
+)
+
+// Component binds the handler of a message component to its middlewares and
+// dependencies. The handler receives every interaction whose custom ID was
+// built from the identifier with CustomId, the arguments are available
+// through CustomIdArgs.
+type Component struct {
+	Identifier *Identifier
+	Handler    EventFunc[discordgo.InteractionCreate]
+
+	// Middlewares wrap the handler, the first one being the outermost.
+	Middlewares []MiddlewareFunc[discordgo.InteractionCreate]
+
+	// Requires lists the dependencies the handler resolves with Use.
+	Requires []Dependency
+
+	// Feature guards the component, its handler only runs in the guilds the
+	// feature is enabled in.
+	Feature *Identifier
+
+	// Timeout bounds how long the handler can run, DefaultHandlerTimeout is
+	// used when zero.
+	Timeout time.Duration
+}
+
+// RegisterComponents validates the components and routes their handlers,
+// wrapped with their middlewares and the timeout and auto defer ones. Nothing
+// is registered if any of them is invalid.
+func RegisterComponents(bot *Bot, components ...*Component) error {
+	errs := make([]error, 0)
+	seen := make(map[string]bool, len(components))
+	for _, component := range components {
+		if component.Identifier == nil || component.Handler == nil {
+			errs = append(errs, fmt.Errorf("%w: components need an identifier and a handler", ErrInvalidCommandDeclaration))
+			continue
+		}
+
+		if err := bot.Container.Validate(requirements(component.Requires, component.Feature)...); err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", component.Identifier, err))
+		}
+
+		customId := component.Identifier.String()
+		if seen[customId] || bot.Router.HasComponent(customId) {
+			errs = append(errs, fmt.Errorf("%w: component %s", ErrDuplicateRoute, customId))
+		}
+		seen[customId] = true
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, component := range components {
+		if component.Timeout != 0 {
+			SetHandlerTimeout(component.Identifier, component.Timeout)
+		}
+
+		middlewares := interactionMiddlewares(component.Identifier, component.Feature, component.Middlewares, 0)
+
+		customId := component.Identifier.String()
+		if err := bot.Router.HandleComponent(customId, bindBot(bot, ApplyMiddlewares(component.Handler, middlewares...))); err != nil {
+			return err
+		}
+		bot.onRollback(func() {
+			bot.Router.unregister(bot.Router.components, customId)
+		})
+
+		info := handlerInfo(HandlerComponent, component.Identifier, component.Feature, component.Requires, middlewares)
+		info.Routes = []string{component.Identifier.String()}
+		bot.Handlers.add(info)
+	}
+
+	return nil
+}
+
+// ComponentBuilder is implemented by the builders that can be added to an
+// action row.
+type ComponentBuilder interface {
+	BuildComponent() (discordgo.MessageComponent, error)
+}
+
+type ActionRowBuilder struct {
+	builders []ComponentBuilder
+}
+
+func NewActionRowBuilder() *ActionRowBuilder {
+	return &ActionRowBuilder{}
+}
+
+func (a *ActionRowBuilder) AddComponent(builders ...ComponentBuilder) *ActionRowBuilder {
+	a.builders = append(a.builders, builders...)
+	return a
+}
+
+func (a *ActionRowBuilder) Build() (discordgo.ActionsRow, error) {
+	row := discordgo.ActionsRow{}
+	errs := make([]error, 0)
+
+	for _, builder := range a.builders {
+		component, err := builder.BuildComponent()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		row.Components = append(row.Components, component)
+	}
+
+	switch {
+	case len(a.builders) == 0:
+		errs = append(errs, fmt.Errorf("%w: action rows need at least one component", ErrInvalidComponent))
+	case len(a.builders) > MaxActionRowComponents:
+		errs = append(errs, fmt.Errorf("%w: action rows can't hold more than %d components", ErrInvalidComponent, MaxActionRowComponents))
+	}
+
+	for _, component := range row.Components {
+		if component.Type() != discordgo.ButtonComponent && len(row.Components) > 1 {
+			errs = append(errs, fmt.Errorf("%w: select menus must be alone in their action row", ErrInvalidComponent))
+			break
+		}
+	}
+
+	return row, errors.Join(errs...)
+}
+
+func (a *ActionRowBuilder) MustBuild() discordgo.ActionsRow {
+	row, err := a.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return row
+}
+
+// BuildActionRows builds every row, failing if there are more rows than a
+// message can hold.
+func BuildActionRows(rows ...*ActionRowBuilder) ([]discordgo.MessageComponent, error) {
+	if len(rows) > MaxActionRows {
+		return nil, fmt.Errorf("%w: messages can't hold more than %d action rows", ErrInvalidComponent, MaxActionRows)
+	}
+
+	components := make([]discordgo.MessageComponent, 0, len(rows))
+	errs := make([]error, 0)
+	for _, row := range rows {
+		built, err := row.Build()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		components = append(components, built)
+	}
+
+	return components, errors.Join(errs...)
+}
+
+type ButtonBuilder struct {
+	discordgo.Button
+	customIdErr error
+}
+
+func NewButtonBuilder() *ButtonBuilder {
+	return &ButtonBuilder{
+		Button: discordgo.Button{
+			Style: discordgo.PrimaryButton,
+		},
+	}
+}
+
+func (b *ButtonBuilder) SetStyle(style discordgo.ButtonStyle) *ButtonBuilder {
+	b.Style = style
+	return b
+}
+
+func (b *ButtonBuilder) SetLabel(label string) *ButtonBuilder {
+	b.Label = label
+	return b
+}
+
+func (b *ButtonBuilder) SetEmoji(emoji discordgo.ComponentEmoji) *ButtonBuilder {
+	b.Emoji = &emoji
+	return b
+}
+
+// SetCustomId routes the button to the component with the given identifier.
+func (b *ButtonBuilder) SetCustomId(identifier *Identifier, args ...string) *ButtonBuilder {
+	b.CustomID, b.customIdErr = CustomId(identifier, args...)
+	return b
+}
+
+// SetURL turns the button into a link button.
+func (b *ButtonBuilder) SetURL(url string) *ButtonBuilder {
+	b.Style = discordgo.LinkButton
+	b.URL = url
+	return b
+}
+
+func (b *ButtonBuilder) SetDisabled(disabled bool) *ButtonBuilder {
+	b.Disabled = disabled
+	return b
+}
+
+func (b *ButtonBuilder) Build() (discordgo.Button, error) {
+	errs := []error{b.customIdErr}
+
+	if b.Label == "" && b.Emoji == nil {
+		errs = append(errs, fmt.Errorf("%w: buttons need a label or an emoji", ErrInvalidComponent))
+	}
+
+	if utf8.RuneCountInString(b.Label) > MaxButtonLabelLength {
+		errs = append(errs, fmt.Errorf("%w: button label %q is longer than %d characters", ErrInvalidComponent, b.Label, MaxButtonLabelLength))
+	}
+
+	if b.Style == discordgo.LinkButton {
+		if b.URL == "" || b.CustomID != "" {
+			errs = append(errs, fmt.Errorf("%w: link buttons need a URL and no custom id", ErrInvalidComponent))
+		}
+	} else if b.CustomID == "" || b.URL != "" {
+		errs = append(errs, fmt.Errorf("%w: buttons need a custom id and no URL", ErrInvalidComponent))
+	}
+
+	return b.Button, errors.Join(errs...)
+}
+
+func (b *ButtonBuilder) BuildComponent() (discordgo.MessageComponent, error) {
+	return b.Build()
+}
+
+type SelectMenuBuilder struct {
+	discordgo.SelectMenu
+	customIdErr error
+}
+
+func newSelectMenuBuilder(menuType discordgo.SelectMenuType) *SelectMenuBuilder {
+	return &SelectMenuBuilder{
+		SelectMenu: discordgo.SelectMenu{
+			MenuType: menuType,
+		},
+	}
+}
+
+func NewStringSelectBuilder() *SelectMenuBuilder {
+	return newSelectMenuBuilder(discordgo.StringSelectMenu)
+}
+
+func NewUserSelectBuilder() *SelectMenuBuilder {
+	return newSelectMenuBuilder(discordgo.UserSelectMenu)
+}
+
+func NewRoleSelectBuilder() *SelectMenuBuilder {
+	return newSelectMenuBuilder(discordgo.RoleSelectMenu)
+}
+
+func NewMentionableSelectBuilder() *SelectMenuBuilder {
+	return newSelectMenuBuilder(discordgo.MentionableSelectMenu)
+}
+
+func NewChannelSelectBuilder() *SelectMenuBuilder {
+	return newSelectMenuBuilder(discordgo.ChannelSelectMenu)
+}
+
+// SetCustomId routes the select menu to the component with the given
+// identifier.
+func (m *SelectMenuBuilder) SetCustomId(identifier *Identifier, args ...string) *SelectMenuBuilder {
+	m.CustomID, m.customIdErr = CustomId(identifier, args...)
+	return m
+}
+
+func (m *SelectMenuBuilder) SetPlaceholder(placeholder string) *SelectMenuBuilder {
+	m.Placeholder = placeholder
+	return m
+}
+
+func (m *SelectMenuBuilder) SetMinValues(min int) *SelectMenuBuilder {
+	m.MinValues = &min
+	return m
+}
+
+func (m *SelectMenuBuilder) SetMaxValues(max int) *SelectMenuBuilder {
+	m.MaxValues = max
+	return m
+}
+
+func (m *SelectMenuBuilder) SetDisabled(disabled bool) *SelectMenuBuilder {
+	m.Disabled = disabled
+	return m
+}
+
+func (m *SelectMenuBuilder) AddOption(label, value, description string) *SelectMenuBuilder {
+	m.Options = append(m.Options, discordgo.SelectMenuOption{
+		Label:       label,
+		Value:       value,
+		Description: description,
+	})
+	return m
+}
+
+func (m *SelectMenuBuilder) AddOptions(options ...discordgo.SelectMenuOption) *SelectMenuBuilder {
+	m.Options = append(m.Options, options...)
+	return m
+}
+
+func (m *SelectMenuBuilder) AddDefaultValue(id string, valueType discordgo.SelectMenuDefaultValueType) *SelectMenuBuilder {
+	m.DefaultValues = append(m.DefaultValues, discordgo.SelectMenuDefaultValue{
+		ID:   id,
+		Type: valueType,
+	})
+	return m
+}
+
+func (m *SelectMenuBuilder) AddChannelTypes(channelTypes ...discordgo.ChannelType) *SelectMenuBuilder {
+	m.ChannelTypes = append(m.ChannelTypes, channelTypes...)
+	return m
+}
+
+func (m *SelectMenuBuilder) Build() (discordgo.SelectMenu, error) {
+	errs := []error{m.customIdErr}
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: select menu %s: %s", ErrInvalidComponent, m.CustomID, fmt.Sprintf(format, args...)))
+	}
+
+	if m.CustomID == "" {
+		fail("missing custom id")
+	}
+
+	if utf8.RuneCountInString(m.Placeholder) > MaxSelectPlaceholderSize {
+		fail("placeholder is longer than %d characters", MaxSelectPlaceholderSize)
+	}
+
+	if m.MenuType == discordgo.StringSelectMenu {
+		if len(m.Options) == 0 || len(m.Options) > MaxSelectOptions {
+			fail("string select menus need between 1 and %d options", MaxSelectOptions)
+		}
+
+		values := make(map[string]bool, len(m.Options))
+		for _, option := range m.Options {
+			if option.Label == "" || utf8.RuneCountInString(option.Label) > MaxSelectOptionLength {
+				fail("option label %q must be between 1 and %d characters", option.Label, MaxSelectOptionLength)
+			}
+
+			if option.Value == "" || utf8.RuneCountInString(option.Value) > MaxSelectOptionLength {
+				fail("option value %q must be between 1 and %d characters", option.Value, MaxSelectOptionLength)
+			}
+
+			if utf8.RuneCountInString(option.Description) > MaxSelectOptionLength {
+				fail("option description of %q is longer than %d characters", option.Value, MaxSelectOptionLength)
+			}
+
+			if values[option.Value] {
+				fail("duplicated option value %q", option.Value)
+			}
+			values[option.Value] = true
+		}
+	} else if len(m.Options) > 0 {
+		fail("only string select menus can have options")
+	}
+
+	if len(m.ChannelTypes) > 0 && m.MenuType != discordgo.ChannelSelectMenu {
+		fail("only channel select menus can filter channel types")
+	}
+
+	min := 1
+	if m.MinValues != nil {
+		min = *m.MinValues
+	}
+
+	max := 1
+	if m.MaxValues != 0 {
+		max = m.MaxValues
+	}
+
+	if min < 0 || min > MaxSelectOptions {
+		fail("min values must be between 0 and %d", MaxSelectOptions)
+	}
+
+	if max < 1 || max > MaxSelectOptions || max < min {
+		fail("max values must be between %d and %d", min, MaxSelectOptions)
+	}
+
+	if m.MenuType == discordgo.StringSelectMenu && max > len(m.Options) && len(m.Options) > 0 {
+		fail("max values can't exceed the number of options")
+	}
+
+	return m.SelectMenu, errors.Join(errs...)
+}
+
+func (m *SelectMenuBuilder) BuildComponent() (discordgo.MessageComponent, error) {
+	return m.Build()
+}
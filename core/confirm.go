@@ -0,0 +1,258 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultConfirmTimeout is how long confirmation prompts wait for an answer.
+// The prompt is disabled through the token of the interaction, which lasts 15
+// minutes, so the timeout can't go past that.
+var DefaultConfirmTimeout = time.Minute
+
+const maxConfirmTimeout = 14 * time.Minute
+
+type confirmAction string
+
+const (
+	confirmAccept confirmAction = "confirm"
+	confirmCancel confirmAction = "cancel"
+)
+
+var ConfirmIdent = NewIdentifier("core", "components/confirm")
+
+// ConfirmPrompt describes the question shown by Confirm, only the title is
+// required.
+type ConfirmPrompt struct {
+	Title        string
+	Description  string
+	ConfirmLabel string
+	CancelLabel  string
+	Timeout      time.Duration
+}
+
+type pendingConfirm struct {
+	prompt      *ConfirmPrompt
+	action      EventFunc[discordgo.InteractionCreate]
+	session     *discordgo.Session
+	interaction *discordgo.Interaction
+	userId      string
+	timer       *time.Timer
+}
+
+var confirms = struct {
+	sync.Mutex
+	pending map[string]*pendingConfirm
+}{
+	pending: make(map[string]*pendingConfirm),
+}
+
+// takeConfirm removes the pending confirmation so it's only answered once.
+func takeConfirm(id string) (*pendingConfirm, bool) {
+	confirms.Lock()
+	defer confirms.Unlock()
+
+	pending, ok := confirms.pending[id]
+	if ok {
+		delete(confirms.pending, id)
+	}
+
+	return pending, ok
+}
+
+// InteractionUserId returns the ID of the user who triggered the interaction,
+// both in guilds and in direct messages.
+func InteractionUserId(i *discordgo.Interaction) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+
+	if i.User != nil {
+		return i.User.ID
+	}
+
+	return ""
+}
+
+// Confirm shows a confirm and cancel prompt and runs the action once the
+// invoking user confirms, the action answers through Respond(...).Edit.
+// Nothing runs if the prompt is cancelled or times out.
+func Confirm(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction, prompt *ConfirmPrompt, action EventFunc[discordgo.InteractionCreate]) error {
+	id := xid.New().String()
+
+	row, err := confirmControls(id, prompt, InteractionLocale(i), false)
+	if err != nil {
+		return err
+	}
+
+	if err := Respond(ctx, s, i).Edit(&discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{confirmEmbed(prompt)},
+		Components: &[]discordgo.MessageComponent{row},
+	}); err != nil {
+		return err
+	}
+
+	pending := &pendingConfirm{
+		prompt:      prompt,
+		action:      action,
+		session:     s,
+		interaction: i,
+		userId:      InteractionUserId(i),
+	}
+
+	confirms.Lock()
+	defer confirms.Unlock()
+
+	pending.timer = time.AfterFunc(prompt.timeout(), func() {
+		if _, ok := takeConfirm(id); !ok {
+			return
+		}
+
+		if err := pending.expire(id); err != nil {
+			log.Warn().Err(err).Msgf("[Confirm] Failed to expire confirmation prompt %s", id)
+		}
+	})
+	confirms.pending[id] = pending
+
+	return nil
+}
+
+// timeout is how long the prompt waits for an answer, capped to how long the
+// interaction token lasts.
+func (p *ConfirmPrompt) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultConfirmTimeout
+	}
+
+	return min(p.Timeout, maxConfirmTimeout)
+}
+
+func confirmEmbed(prompt *ConfirmPrompt) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       prompt.Title,
+		Description: prompt.Description,
+		Color:       ColorWarning,
+	}
+}
+
+func confirmControls(id string, prompt *ConfirmPrompt, locale discordgo.Locale, disabled bool) (discordgo.ActionsRow, error) {
+	t := coreMessages.For(locale)
+
+	confirmLabel, cancelLabel := prompt.ConfirmLabel, prompt.CancelLabel
+	if confirmLabel == "" {
+		confirmLabel = t("confirm.confirm")
+	}
+
+	if cancelLabel == "" {
+		cancelLabel = t("confirm.cancel")
+	}
+
+	return NewActionRowBuilder().
+		AddComponent(
+			NewButtonBuilder().
+				SetStyle(discordgo.DangerButton).
+				SetLabel(confirmLabel).
+				SetCustomId(ConfirmIdent, id, string(confirmAccept)).
+				SetDisabled(disabled),
+			NewButtonBuilder().
+				SetStyle(discordgo.SecondaryButton).
+				SetLabel(cancelLabel).
+				SetCustomId(ConfirmIdent, id, string(confirmCancel)).
+				SetDisabled(disabled),
+		).
+		Build()
+}
+
+func (p *pendingConfirm) expire(id string) error {
+	locale := InteractionLocale(p.interaction)
+	row, err := confirmControls(id, p.prompt, locale, true)
+	if err != nil {
+		return err
+	}
+
+	embed := confirmEmbed(p.prompt)
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: coreMessages.Translate(locale, "confirm.timed-out", nil),
+	}
+
+	_, err = p.session.InteractionResponseEdit(p.interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &[]discordgo.MessageComponent{row},
+	})
+	return err
+}
+
+// HandleConfirmComponent handles the buttons of every confirmation prompt.
+func HandleConfirmComponent(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	args := CustomIdArgs(ctx)
+	if len(args) != 2 {
+		return fmt.Errorf("%w: %s", ErrInvalidCustomId, e.MessageComponentData().CustomID)
+	}
+
+	action := confirmAction(args[1])
+	if action != confirmAccept && action != confirmCancel {
+		return fmt.Errorf("%w: unknown confirm action %q", ErrInvalidCustomId, args[1])
+	}
+
+	t := coreMessages.For(InteractionLocale(e.Interaction))
+
+	confirms.Lock()
+	pending, ok := confirms.pending[args[0]]
+	confirms.Unlock()
+
+	if ok && InteractionUserId(e.Interaction) != pending.userId {
+		return respondEphemeral(ctx, s, e.Interaction, &discordgo.MessageEmbed{
+			Title:       t("confirm.not-yours.title"),
+			Description: t("confirm.not-yours.description"),
+			Color:       ColorWarning,
+		})
+	}
+
+	// Another click or the timeout may have answered it in the meantime
+	if pending, ok = takeConfirm(args[0]); !ok {
+		return respondEphemeral(ctx, s, e.Interaction, &discordgo.MessageEmbed{
+			Title:       t("confirm.expired.title"),
+			Description: t("confirm.expired.description"),
+			Color:       ColorWarning,
+		})
+	}
+	pending.timer.Stop()
+
+	if action == confirmCancel {
+		return Respond(ctx, s, e.Interaction).Update(&discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{{
+				Title:       t("confirm.cancelled.title"),
+				Description: t("confirm.cancelled.description"),
+				Color:       ColorInfo,
+			}},
+			Components: []discordgo.MessageComponent{},
+		})
+	}
+
+	if err := Respond(ctx, s, e.Interaction).Update(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       pending.prompt.Title,
+			Description: t("confirm.working"),
+			Color:       ColorInfo,
+		}},
+		Components: []discordgo.MessageComponent{},
+	}); err != nil {
+		return err
+	}
+
+	return pending.action(ctx, s, e)
+}
+
+// ConfirmComponent routes the buttons of every confirmation prompt, it has to
+// be registered for Confirm to work. Confirmed actions run within its timeout.
+var ConfirmComponent = &Component{
+	Identifier: ConfirmIdent,
+	Handler:    HandleConfirmComponent,
+	Timeout:    5 * time.Minute,
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// sendConfirm shows a prompt to alice and returns its ID, the action marks
+// the returned channel once it runs.
+func sendConfirm(t *testing.T, session *discordgo.Session, rest *restRecorder, prompt *ConfirmPrompt) (string, chan struct{}) {
+	t.Helper()
+
+	ran := make(chan struct{}, 1)
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "alice")
+	err := Confirm(context.Background(), session, e.Interaction, prompt, func(_ context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+		ran <- struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response sentResponse
+	if err := rest.sent("POST", "/interactions/1/"+e.Token+"/callback")[0].decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data.Components) != 1 || len(response.Data.Components[0].Components) != 2 {
+		t.Fatalf("got components %+v, want the confirm and cancel buttons", response.Data.Components)
+	}
+
+	// The custom ID is core:components/confirm/{id}/{action}
+	segments := strings.Split(response.Data.Components[0].Components[0].CustomID, "/")
+	id := segments[len(segments)-2]
+
+	t.Cleanup(func() {
+		if pending, ok := takeConfirm(id); ok {
+			pending.timer.Stop()
+		}
+	})
+
+	return id, ran
+}
+
+func answerConfirm(t *testing.T, session *discordgo.Session, rest *restRecorder, interactionId, userId, id string, action confirmAction) sentResponse {
+	t.Helper()
+
+	e := userEvent(interactionId, discordgo.InteractionMessageComponent, userId)
+	ctx := withCustomIdArgs(context.Background(), []string{id, string(action)})
+	if err := HandleConfirmComponent(ctx, session, e); err != nil {
+		t.Fatal(err)
+	}
+
+	var response sentResponse
+	if err := rest.sent("POST", "/interactions/"+interactionId+"/"+e.Token+"/callback")[0].decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+func TestConfirmAccept(t *testing.T) {
+	session, rest := newRestSession(t)
+	id, ran := sendConfirm(t, session, rest, &ConfirmPrompt{Title: "Clear the whitelist?"})
+
+	// Only the user who ran the command can answer
+	response := answerConfirm(t, session, rest, "2", "bob", id, confirmAccept)
+	if len(response.Data.Embeds) != 1 || response.Data.Embeds[0].Title != "Not your prompt" || response.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("got response %+v, want an ephemeral not your prompt", response)
+	}
+	if len(ran) != 0 {
+		t.Fatal("the action ran for another user")
+	}
+
+	response = answerConfirm(t, session, rest, "3", "alice", id, confirmAccept)
+	if response.Type != discordgo.InteractionResponseUpdateMessage || len(response.Data.Components) != 0 {
+		t.Errorf("got response %+v, want the prompt updated without its buttons", response)
+	}
+	if len(ran) != 1 {
+		t.Fatal("the action didn't run")
+	}
+
+	// Prompts are only answered once
+	response = answerConfirm(t, session, rest, "4", "alice", id, confirmAccept)
+	if len(response.Data.Embeds) != 1 || response.Data.Embeds[0].Title != "Prompt expired" {
+		t.Errorf("got response %+v, want the expired prompt", response)
+	}
+}
+
+func TestConfirmCancel(t *testing.T) {
+	session, rest := newRestSession(t)
+	id, ran := sendConfirm(t, session, rest, &ConfirmPrompt{Title: "Clear the whitelist?"})
+
+	response := answerConfirm(t, session, rest, "2", "alice", id, confirmCancel)
+	if len(response.Data.Embeds) != 1 || response.Data.Embeds[0].Title != "Cancelled" {
+		t.Errorf("got response %+v, want the cancelled prompt", response)
+	}
+	if len(ran) != 0 {
+		t.Error("the action ran after cancelling")
+	}
+}
+
+func TestConfirmTimesOut(t *testing.T) {
+	session, rest := newRestSession(t)
+	_, ran := sendConfirm(t, session, rest, &ConfirmPrompt{Title: "Clear the whitelist?", Timeout: 10 * time.Millisecond})
+
+	deadline := time.Now().Add(time.Second)
+	for len(rest.sent("PATCH", "/messages/@original")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	edits := rest.sent("PATCH", "/messages/@original")
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits, want the prompt disabled", len(edits))
+	}
+
+	var edit sentMessage
+	if err := edits[0].decode(&edit); err != nil {
+		t.Fatal(err)
+	}
+	if len(edit.Embeds) != 1 || edit.Embeds[0].Footer == nil {
+		t.Errorf("got edit %+v, want the timed out footer", edit)
+	}
+	for _, button := range edit.Components[0].Components {
+		if !button.Disabled {
+			t.Errorf("button %s is still enabled", button.CustomID)
+		}
+	}
+	if len(ran) != 0 {
+		t.Error("the action ran after timing out")
+	}
+}
+
+func TestConfirmPromptTimeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{0, DefaultConfirmTimeout},
+		{5 * time.Minute, 5 * time.Minute},
+		{time.Hour, maxConfirmTimeout},
+	}
+
+	for _, test := range tests {
+		prompt := &ConfirmPrompt{Title: "Sure?", Timeout: test.timeout}
+		if got := prompt.timeout(); got != test.want {
+			t.Errorf("timeout %s: got %s, want %s", test.timeout, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrDependencyNotProvided = errors.New("dependency not provided")
+
+// Key identifies a dependency of type T in a container.
+type Key[T any] struct {
+	*Identifier
+}
+
+func NewKey[T any](namespace, id string) Key[T] {
+	return Key[T]{Identifier: NewIdentifier(namespace, id)}
+}
+
+func (k Key[T]) dependencyIdentifier() *Identifier {
+	return k.Identifier
+}
+
+// Dependency is implemented by every Key, it lets handlers list what they
+// need without caring about the types.
+type Dependency interface {
+	dependencyIdentifier() *Identifier
+	String() string
+}
+
+// Container holds the services shared between handlers. Handlers registered on
+// a bot get its container attached to their context, so they reach it through
+// Use.
+type Container struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+func NewContainer() *Container {
+	return &Container{
+		values: make(map[string]any),
+	}
+}
+
+var botContainer = NewContainer()
+
+// Services returns the container of the bot, the one the services of the
+// process are provided to.
+func Services() *Container {
+	return botContainer
+}
+
+func (c *Container) set(identifier *Identifier, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[identifier.String()] = value
+}
+
+func (c *Container) get(identifier *Identifier) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, ok := c.values[identifier.String()]
+	return value, ok
+}
+
+func (c *Container) Has(dependency Dependency) bool {
+	_, ok := c.get(dependency.dependencyIdentifier())
+	return ok
+}
+
+// Validate returns an error listing every dependency that wasn't provided.
+func (c *Container) Validate(dependencies ...Dependency) error {
+	errs := make([]error, 0)
+	for _, dependency := range dependencies {
+		if !c.Has(dependency) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrDependencyNotProvided, dependency))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ProvideTo stores the value for the key in the given container.
+func ProvideTo[T any](c *Container, key Key[T], value T) {
+	c.set(key.Identifier, value)
+}
+
+// Provide stores the value for the key in the bot container.
+func Provide[T any](key Key[T], value T) {
+	ProvideTo(botContainer, key, value)
+}
+
+type containerContextKey struct{}
+
+// WithContainer returns a context whose handlers resolve their dependencies
+// from the given container.
+func WithContainer(ctx context.Context, c *Container) context.Context {
+	return context.WithValue(ctx, containerContextKey{}, c)
+}
+
+// ContainerFrom returns the container attached to the context, nil if there
+// is none.
+func ContainerFrom(ctx context.Context) *Container {
+	c, _ := ctx.Value(containerContextKey{}).(*Container)
+	return c
+}
+
+// Resolve returns the value stored for the key in the given container.
+func Resolve[T any](c *Container, key Key[T]) (T, error) {
+	var zero T
+
+	value, ok := c.get(key.Identifier)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrDependencyNotProvided, key)
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s holds a %T", ErrDependencyNotProvided, key, value)
+	}
+
+	return typed, nil
+}
+
+// Lookup resolves the dependency for the key from the context's container.
+func Lookup[T any](ctx context.Context, key Key[T]) (T, error) {
+	c := ContainerFrom(ctx)
+	if c == nil {
+		var zero T
+		return zero, fmt.Errorf("%w: %s, no container attached to the context", ErrDependencyNotProvided, key)
+	}
+
+	return Resolve(c, key)
+}
+
+// Use resolves the dependency for the key from the context's container. It
+// panics if the dependency is missing, handlers declaring it in their
+// requirements are only registered once it was provided.
+func Use[T any](ctx context.Context, key Key[T]) T {
+	value, err := Lookup(ctx, key)
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
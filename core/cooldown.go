@@ -0,0 +1,216 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+type CooldownScope int
+
+const (
+	CooldownUser CooldownScope = iota
+	CooldownChannel
+	CooldownGuild
+)
+
+func (s CooldownScope) String() string {
+	switch s {
+	case CooldownUser:
+		return "user"
+	case CooldownChannel:
+		return "channel"
+	case CooldownGuild:
+		return "guild"
+	default:
+		return "unknown"
+	}
+}
+
+// CooldownLimit allows Uses invocations every Per within its scope, as a token
+// bucket: all the uses can be spent at once and they come back steadily.
+type CooldownLimit struct {
+	Scope CooldownScope
+	Uses  int
+	Per   time.Duration
+}
+
+func PerUser(uses int, per time.Duration) CooldownLimit {
+	return CooldownLimit{Scope: CooldownUser, Uses: uses, Per: per}
+}
+
+func PerChannel(uses int, per time.Duration) CooldownLimit {
+	return CooldownLimit{Scope: CooldownChannel, Uses: uses, Per: per}
+}
+
+func PerGuild(uses int, per time.Duration) CooldownLimit {
+	return CooldownLimit{Scope: CooldownGuild, Uses: uses, Per: per}
+}
+
+// CooldownBypass lets some users skip every cooldown, e.g. the bot owners.
+var CooldownBypass func(userId string) bool
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	per     time.Duration
+}
+
+// refill adds the tokens earned since the last update, capped at the limit.
+func (b *bucket) refill(limit CooldownLimit, now time.Time) {
+	rate := float64(limit.Uses) / float64(limit.Per)
+	b.tokens = math.Min(float64(limit.Uses), b.tokens+float64(now.Sub(b.updated))*rate)
+	b.updated = now
+}
+
+// wait returns how long until the bucket holds a whole token.
+func (b *bucket) wait(limit CooldownLimit) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	rate := float64(limit.Uses) / float64(limit.Per)
+	return time.Duration(math.Ceil((1 - b.tokens) / rate))
+}
+
+const cooldownSweepInterval = 10 * time.Minute
+
+var cooldowns = struct {
+	sync.Mutex
+	limits    map[string][]CooldownLimit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}{
+	limits:  make(map[string][]CooldownLimit),
+	buckets: make(map[string]*bucket),
+}
+
+// SetCooldown overrides the limits of the handler with the given identifier,
+// no limits disable its cooldown.
+func SetCooldown(identifier *Identifier, limits ...CooldownLimit) {
+	cooldowns.Lock()
+	defer cooldowns.Unlock()
+
+	cooldowns.limits[identifier.String()] = limits
+}
+
+func cooldownKey(identifier *Identifier, limit CooldownLimit, i *discordgo.Interaction) (string, bool) {
+	var id string
+	switch limit.Scope {
+	case CooldownUser:
+		id = InteractionUserId(i)
+	case CooldownChannel:
+		id = i.ChannelID
+	case CooldownGuild:
+		id = i.GuildID
+	}
+
+	// Guild limits don't apply to direct messages
+	if id == "" {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s", identifier, limit.Scope, limit.Per, id), true
+}
+
+// takeCooldown spends a use of every limit, or none of them if any is
+// exhausted, in which case it returns how long until all of them allow it.
+func takeCooldown(identifier *Identifier, limits []CooldownLimit, i *discordgo.Interaction) time.Duration {
+	cooldowns.Lock()
+	defer cooldowns.Unlock()
+
+	now := time.Now()
+	if now.Sub(cooldowns.lastSweep) > cooldownSweepInterval {
+		sweepCooldowns(now)
+	}
+
+	if override, ok := cooldowns.limits[identifier.String()]; ok {
+		limits = override
+	}
+
+	buckets := make([]*bucket, 0, len(limits))
+	var wait time.Duration
+	for _, limit := range limits {
+		if limit.Uses <= 0 || limit.Per <= 0 {
+			continue
+		}
+
+		key, ok := cooldownKey(identifier, limit, i)
+		if !ok {
+			continue
+		}
+
+		b, ok := cooldowns.buckets[key]
+		if !ok {
+			b = &bucket{tokens: float64(limit.Uses), updated: now, per: limit.Per}
+			cooldowns.buckets[key] = b
+		}
+
+		b.refill(limit, now)
+		wait = max(wait, b.wait(limit))
+		buckets = append(buckets, b)
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+
+	return 0
+}
+
+// sweepCooldowns drops the buckets that had time to fill up again, they
+// behave the same as new ones.
+func sweepCooldowns(now time.Time) {
+	cooldowns.lastSweep = now
+
+	for key, b := range cooldowns.buckets {
+		if now.Sub(b.updated) > b.per {
+			delete(cooldowns.buckets, key)
+		}
+	}
+}
+
+// MidwareCooldown limits how often the handler with the given identifier can
+// be invoked, unless overridden through SetCooldown. Limited users get an
+// ephemeral reply telling them when to try again and the handler doesn't run.
+// It has to run before anything answers the interaction, autocompletes aren't
+// limited.
+func MidwareCooldown(identifier *Identifier, limits ...CooldownLimit) MiddlewareFunc[discordgo.InteractionCreate] {
+	return func(next EventFunc[discordgo.InteractionCreate]) EventFunc[discordgo.InteractionCreate] {
+		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			if e.Type == discordgo.InteractionApplicationCommandAutocomplete {
+				return next(c, s, e)
+			}
+
+			if CooldownBypass != nil && CooldownBypass(InteractionUserId(e.Interaction)) {
+				return next(c, s, e)
+			}
+
+			wait := takeCooldown(identifier, limits, e.Interaction)
+			if wait <= 0 {
+				return next(c, s, e)
+			}
+
+			log.Debug().Msgf("[CooldownMidware] Interaction %s for \"%s\" is on cooldown for %s", e.ID, identifier, wait)
+
+			t := coreMessages.For(InteractionLocale(e.Interaction))
+			return Respond(c, s, e.Interaction).Reply(&discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{{
+					Title:       t("cooldown.title"),
+					Description: t("cooldown.description", Vars{"wait": max(wait.Round(time.Second), time.Second)}),
+					Color:       ColorWarning,
+				}},
+				Flags: discordgo.MessageFlagsEphemeral,
+			})
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func cooldownInteraction(guildId, channelId, userId string) *discordgo.Interaction {
+	i := &discordgo.Interaction{ID: "1", GuildID: guildId, ChannelID: channelId}
+	if guildId == "" {
+		i.User = &discordgo.User{ID: userId}
+	} else {
+		i.Member = &discordgo.Member{User: &discordgo.User{ID: userId}}
+	}
+
+	return i
+}
+
+// resetCooldowns forgets the buckets and overrides once the test is done, so
+// running it again starts afresh.
+func resetCooldowns(t *testing.T) {
+	t.Cleanup(func() {
+		cooldowns.Lock()
+		defer cooldowns.Unlock()
+
+		cooldowns.limits = make(map[string][]CooldownLimit)
+		cooldowns.buckets = make(map[string]*bucket)
+	})
+}
+
+func TestTakeCooldownPerUser(t *testing.T) {
+	resetCooldowns(t)
+	identifier := NewIdentifier("test", "commands/cooldown-user")
+	limits := []CooldownLimit{PerUser(2, time.Minute)}
+
+	alice := cooldownInteraction("guild", "channel", "alice")
+	bob := cooldownInteraction("guild", "channel", "bob")
+
+	for use := 1; use <= 2; use++ {
+		if wait := takeCooldown(identifier, limits, alice); wait != 0 {
+			t.Fatalf("use %d limited for %s", use, wait)
+		}
+	}
+
+	// Uses come back one every 30 seconds
+	wait := takeCooldown(identifier, limits, alice)
+	if wait <= 0 || wait > 30*time.Second {
+		t.Errorf("third use waits %s, want up to 30s", wait)
+	}
+
+	if wait := takeCooldown(identifier, limits, bob); wait != 0 {
+		t.Errorf("another user is limited for %s", wait)
+	}
+}
+
+func TestTakeCooldownSpendsAllOrNothing(t *testing.T) {
+	resetCooldowns(t)
+	identifier := NewIdentifier("test", "commands/cooldown-all")
+	limits := []CooldownLimit{PerUser(5, time.Minute), PerChannel(1, time.Minute)}
+
+	if wait := takeCooldown(identifier, limits, cooldownInteraction("guild", "channel", "alice")); wait != 0 {
+		t.Fatalf("first use limited for %s", wait)
+	}
+
+	// The channel is exhausted, so the use of the user isn't spent
+	if wait := takeCooldown(identifier, limits, cooldownInteraction("guild", "channel", "alice")); wait == 0 {
+		t.Fatal("the channel limit didn't apply")
+	}
+
+	for use := 2; use <= 5; use++ {
+		if wait := takeCooldown(identifier, limits, cooldownInteraction("guild", fmt.Sprintf("channel-%d", use), "alice")); wait != 0 {
+			t.Fatalf("use %d limited for %s", use, wait)
+		}
+	}
+
+	if wait := takeCooldown(identifier, limits, cooldownInteraction("guild", "other", "alice")); wait == 0 {
+		t.Error("the user limit didn't apply")
+	}
+}
+
+func TestTakeCooldownSkipsGuildLimitsInDMs(t *testing.T) {
+	resetCooldowns(t)
+	identifier := NewIdentifier("test", "commands/cooldown-dm")
+	limits := []CooldownLimit{PerGuild(1, time.Hour)}
+
+	for use := 1; use <= 3; use++ {
+		if wait := takeCooldown(identifier, limits, cooldownInteraction("", "dm", "alice")); wait != 0 {
+			t.Fatalf("use %d limited for %s", use, wait)
+		}
+	}
+}
+
+func TestSetCooldownOverridesLimits(t *testing.T) {
+	resetCooldowns(t)
+	identifier := NewIdentifier("test", "commands/cooldown-override")
+	limits := []CooldownLimit{PerUser(1, time.Hour)}
+
+	SetCooldown(identifier)
+
+	for use := 1; use <= 3; use++ {
+		if wait := takeCooldown(identifier, limits, cooldownInteraction("guild", "channel", "alice")); wait != 0 {
+			t.Fatalf("use %d limited for %s with the cooldown disabled", use, wait)
+		}
+	}
+}
+
+func TestBucketRefill(t *testing.T) {
+	limit := PerUser(4, time.Minute)
+	now := time.Now()
+
+	b := &bucket{tokens: 0, updated: now, per: limit.Per}
+	if wait := b.wait(limit); wait != 15*time.Second {
+		t.Errorf("empty bucket waits %s, want 15s", wait)
+	}
+
+	b.refill(limit, now.Add(30*time.Second))
+	if b.tokens != 2 {
+		t.Errorf("got %v tokens after 30s, want 2", b.tokens)
+	}
+
+	b.refill(limit, now.Add(time.Hour))
+	if b.tokens != 4 {
+		t.Errorf("got %v tokens after an hour, want them capped at 4", b.tokens)
+	}
+}
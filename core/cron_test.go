@@ -0,0 +1,82 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr  string
+		after string
+		want  string
+	}{
+		{"*/15 * * * *", "2024-05-10T10:07:30Z", "2024-05-10T10:15:00Z"},
+		{"*/15 * * * *", "2024-05-10T10:45:00Z", "2024-05-10T11:00:00Z"},
+		{"0 4 * * *", "2024-05-10T03:59:00Z", "2024-05-10T04:00:00Z"},
+		{"0 4 * * *", "2024-05-10T04:00:00Z", "2024-05-11T04:00:00Z"},
+		{"@hourly", "2024-12-31T23:30:00Z", "2025-01-01T00:00:00Z"},
+		{"@monthly", "2024-01-31T12:00:00Z", "2024-02-01T00:00:00Z"},
+		// Friday evening to Monday morning
+		{"0 9 * * 1-5", "2024-05-10T18:00:00Z", "2024-05-13T09:00:00Z"},
+		// Sunday as 7
+		{"0 0 * * 7", "2024-05-10T00:00:00Z", "2024-05-12T00:00:00Z"},
+		// Either the day of month or the day of week
+		{"0 0 13 * 5", "2024-05-10T12:00:00Z", "2024-05-13T00:00:00Z"},
+		{"0 0 29 2 *", "2023-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"30 8,20 1-3 * *", "2024-05-03T20:30:00Z", "2024-06-01T08:30:00Z"},
+		// Times are matched in UTC
+		{"0 0 * * *", "2024-05-10T23:30:00-02:00", "2024-05-12T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		cron, err := ParseCron(test.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", test.expr, err)
+			continue
+		}
+
+		after, _ := time.Parse(time.RFC3339, test.after)
+		want, _ := time.Parse(time.RFC3339, test.want)
+
+		got, err := cron.Next(after)
+		if err != nil {
+			t.Errorf("%q after %s: %v", test.expr, test.after, err)
+			continue
+		}
+
+		if !got.Equal(want) {
+			t.Errorf("%q after %s = %s, want %s", test.expr, test.after, got.Format(time.RFC3339), test.want)
+		}
+	}
+}
+
+func TestCronNextNeverMatches(t *testing.T) {
+	cron := MustParseCron("0 0 30 2 *")
+
+	if _, err := cron.Next(time.Now()); !errors.Is(err, ErrCronNoMatch) {
+		t.Errorf("got %v, want %v", err, ErrCronNoMatch)
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("ParseCron(%q) = %v, want %v", expr, err, ErrInvalidCron)
+		}
+	}
+}
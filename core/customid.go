@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrInvalidCustomId = errors.New("invalid custom id")
+	ErrMissingArgument = errors.New("missing custom id argument")
+)
+
+// MaxCustomIdLength is the longest custom ID Discord accepts on components
+// and modals.
+const MaxCustomIdLength = 100
+
+// CustomId builds the custom ID of a component routed to the handler with the
+// given identifier. Arguments are appended as escaped "/" separated segments,
+// so "whitelist:confirm-clear" with the argument "123" gives
+// "whitelist:confirm-clear/123".
+func CustomId(identifier *Identifier, args ...string) (string, error) {
+	segments := make([]string, 0, len(args)+1)
+	segments = append(segments, identifier.String())
+	for _, arg := range args {
+		segments = append(segments, url.PathEscape(arg))
+	}
+
+	customId := strings.Join(segments, "/")
+	if len(customId) > MaxCustomIdLength {
+		return "", fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidCustomId, customId, MaxCustomIdLength)
+	}
+
+	return customId, nil
+}
+
+func decodeCustomIdArgs(segments []string) ([]string, error) {
+	args := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		arg, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCustomId, err)
+		}
+
+		args = append(args, arg)
+	}
+
+	return args, nil
+}
+
+type customIdArgsKey struct{}
+
+func withCustomIdArgs(ctx context.Context, args []string) context.Context {
+	return context.WithValue(ctx, customIdArgsKey{}, args)
+}
+
+// CustomIdArgs returns the arguments encoded in the custom ID of the
+// component or modal being handled.
+func CustomIdArgs(ctx context.Context) []string {
+	args, _ := ctx.Value(customIdArgsKey{}).([]string)
+	return args
+}
+
+// CustomIdArg returns the argument at the given position of the custom ID of
+// the component or modal being handled.
+func CustomIdArg(ctx context.Context, index int) (string, error) {
+	args := CustomIdArgs(ctx)
+	if index < 0 || index >= len(args) {
+		return "", fmt.Errorf("%w: %d", ErrMissingArgument, index)
+	}
+
+	return args[index], nil
+}
@@ -0,0 +1,65 @@
+package discordtest
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
+)
+
+// NewBot returns a bot on the session with its own router and command stack,
+// wired like the real one so modules and handlers registered on it can be
+// driven through the gateway. The bot gets its own container and event bus,
+// provide the services its handlers use with core.ProvideTo(bot.Container, ...).
+func NewBot(session *discordgo.Session) *core.Bot {
+	bot := &core.Bot{
+		Session:   session,
+		Router:    core.NewInteractionRouter(),
+		Commands:  core.NewCommandStack(),
+		Container: core.NewContainer(),
+		Events:    core.NewEventBus(),
+		Handlers:  core.NewHandlerRegistry(),
+	}
+
+	session.AddHandler(core.HandleEvent(core.ApplyMiddlewares(
+		bot.Router.HandleInteraction,
+		core.MidwareResponder(),
+	)))
+
+	return bot
+}
+
+// LoadModules loads the modules on a new bot on the session, failing the test
+// unless all of them load. Provide the services the modules use in provide,
+// it runs before they're initialized.
+func LoadModules(t testing.TB, session *discordgo.Session, provide func(bot *core.Bot), modules ...core.Module) *core.Bot {
+	t.Helper()
+
+	bot := NewBot(session)
+	if provide != nil {
+		provide(bot)
+	}
+
+	registry := core.NewModuleRegistry()
+	if err := registry.Register(modules...); err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		names = append(names, module.Name())
+	}
+
+	reports, err := registry.Load(bot, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, report := range reports {
+		if report.Status != core.ModuleLoaded {
+			t.Fatalf("%s module %s: %v", report.Name, report.Status, report.Err)
+		}
+	}
+
+	return bot
+}
@@ -0,0 +1,241 @@
+package discordtest
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordEpoch is the first millisecond of 2015, snowflakes count from it.
+const discordEpoch = 1420070400000
+
+var snowflakeCounter atomic.Uint64
+
+// Snowflake returns a new unique ID, ordered by creation like Discord's.
+func Snowflake() string {
+	timestamp := uint64(time.Now().UnixMilli() - discordEpoch)
+	increment := snowflakeCounter.Add(1) & (1<<22 - 1)
+
+	return strconv.FormatUint(timestamp<<22|increment, 10)
+}
+
+type InteractionOption func(i *discordgo.Interaction)
+
+// InGuild sends the interaction from the guild as the member. Its permissions
+// are the ones checked by the permission middlewares.
+func InGuild(guildId string, member *discordgo.Member) InteractionOption {
+	return func(i *discordgo.Interaction) {
+		i.GuildID = guildId
+		i.Member = clone(member)
+		i.Member.GuildID = guildId
+		i.User = nil
+	}
+}
+
+// InDM sends the interaction from the direct messages of the user.
+func InDM(user *discordgo.User) InteractionOption {
+	return func(i *discordgo.Interaction) {
+		i.GuildID = ""
+		i.Member = nil
+		i.User = clone(user)
+	}
+}
+
+// InChannel sends the interaction from the channel, its responses are stored
+// in it.
+func InChannel(channelId string) InteractionOption {
+	return func(i *discordgo.Interaction) {
+		i.ChannelID = channelId
+	}
+}
+
+// WithLocale sets the locale of the user sending the interaction.
+func WithLocale(locale discordgo.Locale) InteractionOption {
+	return func(i *discordgo.Interaction) {
+		i.Locale = locale
+	}
+}
+
+// OnMessage sends the component interaction from the message, it is the
+// message updated by update responses.
+func OnMessage(message *discordgo.Message) InteractionOption {
+	return func(i *discordgo.Interaction) {
+		i.Message = clone(message)
+		if message.ChannelID != "" {
+			i.ChannelID = message.ChannelID
+		}
+	}
+}
+
+// Interaction synthesizes an interaction, sent by a new user from an unknown
+// channel unless options say otherwise. Dispatch it to drive the
+// router, or pass it to a handler directly.
+func (s *Server) Interaction(typ discordgo.InteractionType, data discordgo.InteractionData, opts ...InteractionOption) *discordgo.InteractionCreate {
+	i := &discordgo.Interaction{
+		ID:      Snowflake(),
+		AppID:   s.BotUser.ID,
+		Type:    typ,
+		Data:    data,
+		Token:   "interaction-" + Snowflake(),
+		Locale:  discordgo.EnglishUS,
+		Version: 1,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	if i.Member == nil && i.User == nil {
+		i.User = s.AddUser(&discordgo.User{Username: "tester"})
+	}
+	if i.ChannelID == "" {
+		i.ChannelID = Snowflake()
+	}
+
+	s.mu.Lock()
+	s.trackInteraction(i)
+	s.mu.Unlock()
+
+	return &discordgo.InteractionCreate{Interaction: i}
+}
+
+// Command synthesizes the use of a chat input command, subcommands are
+// passed as options made by Subcommand.
+func (s *Server) Command(name string, options []*discordgo.ApplicationCommandInteractionDataOption, opts ...InteractionOption) *discordgo.InteractionCreate {
+	return s.Interaction(discordgo.InteractionApplicationCommand, discordgo.ApplicationCommandInteractionData{
+		ID:          Snowflake(),
+		Name:        name,
+		CommandType: discordgo.ChatApplicationCommand,
+		Options:     options,
+	}, opts...)
+}
+
+// Autocomplete synthesizes an autocomplete request of a command, the focused
+// option is the one being completed.
+func (s *Server) Autocomplete(name string, options []*discordgo.ApplicationCommandInteractionDataOption, opts ...InteractionOption) *discordgo.InteractionCreate {
+	return s.Interaction(discordgo.InteractionApplicationCommandAutocomplete, discordgo.ApplicationCommandInteractionData{
+		ID:          Snowflake(),
+		Name:        name,
+		CommandType: discordgo.ChatApplicationCommand,
+		Options:     options,
+	}, opts...)
+}
+
+// Component synthesizes a button click, or a selection when values are given.
+// Use OnMessage to send it from the message holding the component.
+func (s *Server) Component(customId string, values []string, opts ...InteractionOption) *discordgo.InteractionCreate {
+	componentType := discordgo.ButtonComponent
+	if values != nil {
+		componentType = discordgo.SelectMenuComponent
+	}
+
+	return s.Interaction(discordgo.InteractionMessageComponent, discordgo.MessageComponentInteractionData{
+		CustomID:      customId,
+		ComponentType: componentType,
+		Values:        values,
+	}, opts...)
+}
+
+// ModalSubmit synthesizes the submission of a modal, values are keyed by the
+// custom ID of their text input.
+func (s *Server) ModalSubmit(customId string, values map[string]string, opts ...InteractionOption) *discordgo.InteractionCreate {
+	rows := make([]discordgo.MessageComponent, 0, len(values))
+	for id, value := range values {
+		rows = append(rows, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.TextInput{CustomID: id, Value: value},
+			},
+		})
+	}
+
+	return s.Interaction(discordgo.InteractionModalSubmit, discordgo.ModalSubmitInteractionData{
+		CustomID:   customId,
+		Components: rows,
+	}, opts...)
+}
+
+func option(name string, typ discordgo.ApplicationCommandOptionType, value any) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: typ, Value: value}
+}
+
+func StringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionString, value)
+}
+
+// IntegerOption is sent as a float like Discord's JSON is decoded.
+func IntegerOption(name string, value int64) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionInteger, float64(value))
+}
+
+func NumberOption(name string, value float64) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionNumber, value)
+}
+
+func BooleanOption(name string, value bool) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionBoolean, value)
+}
+
+func UserOption(name, userId string) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionUser, userId)
+}
+
+func ChannelOption(name, channelId string) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionChannel, channelId)
+}
+
+func RoleOption(name, roleId string) *discordgo.ApplicationCommandInteractionDataOption {
+	return option(name, discordgo.ApplicationCommandOptionRole, roleId)
+}
+
+// Focused marks the option as the one being autocompleted.
+func Focused(option *discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	option.Focused = true
+	return option
+}
+
+func Subcommand(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:    name,
+		Type:    discordgo.ApplicationCommandOptionSubCommand,
+		Options: options,
+	}
+}
+
+func SubcommandGroup(name string, subcommands ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:    name,
+		Type:    discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: subcommands,
+	}
+}
+
+// MessageCreate synthesizes the creation of the message, which is added to
+// its channel. Its author is a new user unless it has one.
+func (s *Server) MessageCreate(message *discordgo.Message) *discordgo.MessageCreate {
+	if message.Author == nil {
+		message = clone(message)
+		message.Author = s.AddUser(&discordgo.User{Username: "tester"})
+	}
+
+	return &discordgo.MessageCreate{Message: s.AddMessage(message)}
+}
+
+// MessageDelete synthesizes the deletion of the message, which is removed
+// from its channel. Discord only sends its IDs, like this event does.
+func (s *Server) MessageDelete(channelId, messageId string) *discordgo.MessageDelete {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := &discordgo.MessageDelete{Message: &discordgo.Message{ID: messageId, ChannelID: channelId}}
+	if channel, ok := s.channels[channelId]; ok {
+		event.GuildID = channel.GuildID
+	}
+
+	if index, fields := s.findMessage(channelId, messageId); fields != nil {
+		s.messages[channelId] = append(s.messages[channelId][:index], s.messages[channelId][index+1:]...)
+	}
+
+	return event
+}
@@ -0,0 +1,287 @@
+package discordtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+var ErrNoGateway = errors.New("no session connected to the gateway")
+
+// heartbeatInterval is long enough for heartbeats not to get in the way of
+// tests, they are acknowledged anyway.
+const heartbeatInterval = 45000
+
+var upgrader = websocket.Upgrader{}
+
+type gatewayConn struct {
+	ws    *websocket.Conn
+	mu    sync.Mutex
+	shard [2]int
+}
+
+func (c *gatewayConn) send(payload any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ws.WriteJSON(payload)
+}
+
+func (c *gatewayConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.ws.Close()
+}
+
+// owns returns whether the guild is on the shard of the connection.
+func (c *gatewayConn) owns(guildId string) bool {
+	if guildId == "" || c.shard[1] <= 1 {
+		return true
+	}
+
+	id, err := strconv.ParseUint(guildId, 10, 64)
+	if err != nil {
+		return true
+	}
+
+	return int((id>>22)%uint64(c.shard[1])) == c.shard[0]
+}
+
+type gatewayPayload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d,omitempty"`
+	Sequence int64           `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+// serveGateway says hello, answers identifies with the ready event followed
+// by the guilds of the shard, and acknowledges heartbeats. Resuming isn't
+// supported, sessions are told to identify again.
+func (s *Server) serveGateway(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	conn := &gatewayConn{ws: ws}
+	defer s.disconnect(conn)
+
+	if err := conn.send(map[string]any{"op": 10, "d": map[string]any{"heartbeat_interval": heartbeatInterval}}); err != nil {
+		return
+	}
+
+	for {
+		var payload gatewayPayload
+		if err := ws.ReadJSON(&payload); err != nil {
+			return
+		}
+
+		switch payload.Op {
+		case 1:
+			conn.send(map[string]any{"op": 11})
+		case 2:
+			if err := s.identify(conn, payload.Data); err != nil {
+				return
+			}
+		case 6:
+			conn.send(map[string]any{"op": 9, "d": false})
+		}
+	}
+}
+
+func (s *Server) identify(conn *gatewayConn, data json.RawMessage) error {
+	var identify struct {
+		Shard *[2]int `json:"shard"`
+	}
+	if err := json.Unmarshal(data, &identify); err != nil {
+		return err
+	}
+
+	conn.shard = [2]int{0, 1}
+	if identify.Shard != nil {
+		conn.shard = *identify.Shard
+	}
+
+	// The connection is registered before the ready event is sent, so events
+	// dispatched once the session is open reach it. Holding its lock meanwhile
+	// queues them after the guilds.
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrNoGateway
+	}
+
+	guilds := make([]*discordgo.Guild, 0)
+	unavailable := make([]map[string]any, 0)
+	for _, guild := range s.guilds {
+		if !conn.owns(guild.ID) {
+			continue
+		}
+
+		full := clone(guild)
+		full.Channels = s.guildChannels(guild.ID)
+		for _, member := range s.members[guild.ID] {
+			full.Members = append(full.Members, member)
+		}
+		full.MemberCount = len(full.Members)
+
+		guilds = append(guilds, clone(full))
+		unavailable = append(unavailable, map[string]any{"id": guild.ID, "unavailable": true})
+	}
+
+	ready := map[string]any{
+		"v":           9,
+		"user":        s.BotUser,
+		"session_id":  Snowflake(),
+		"shard":       conn.shard,
+		"application": map[string]any{"id": s.BotUser.ID},
+		"guilds":      unavailable,
+	}
+	s.gateways = append(s.gateways, conn)
+	s.mu.Unlock()
+
+	if err := s.writeEvent(conn, "READY", ready); err != nil {
+		return err
+	}
+
+	for _, guild := range guilds {
+		if err := s.writeEvent(conn, "GUILD_CREATE", guild); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) disconnect(conn *gatewayConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, c := range s.gateways {
+		if c == conn {
+			s.gateways = append(s.gateways[:i], s.gateways[i+1:]...)
+			break
+		}
+	}
+
+	conn.ws.Close()
+}
+
+func (s *Server) dispatchTo(conn *gatewayConn, name string, event any) error {
+	payload, err := s.eventPayload(name, event)
+	if err != nil {
+		return err
+	}
+
+	return conn.send(payload)
+}
+
+// writeEvent sends the event to the connection, the caller holds its lock.
+func (s *Server) writeEvent(conn *gatewayConn, name string, event any) error {
+	payload, err := s.eventPayload(name, event)
+	if err != nil {
+		return err
+	}
+
+	return conn.ws.WriteJSON(payload)
+}
+
+func (s *Server) eventPayload(name string, event any) (*gatewayPayload, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.sequence++
+	sequence := s.sequence
+	s.mu.Unlock()
+
+	return &gatewayPayload{Op: 0, Data: data, Sequence: sequence, Type: name}, nil
+}
+
+// EventName returns the gateway name of the discordgo event, e.g.
+// "MESSAGE_DELETE" for *discordgo.MessageDelete.
+func EventName(event any) string {
+	typ := reflect.TypeOf(event)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var name strings.Builder
+	for i, r := range typ.Name() {
+		if i > 0 && unicode.IsUpper(r) {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+	}
+
+	return name.String()
+}
+
+// Dispatch sends the event to the sessions connected to the gateway, only to
+// the shard of its guild when they are sharded. Sessions handle it like one
+// coming from Discord: their state gets updated and their handlers run in the
+// background, use WaitFor to wait for what they do.
+func (s *Server) Dispatch(event any) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.DispatchRaw(EventName(event), data)
+}
+
+// DispatchRaw sends the event with the name and JSON payload as is, e.g. one
+// recorded from Discord.
+func (s *Server) DispatchRaw(name string, data json.RawMessage) error {
+	var target struct {
+		GuildID string `json:"guild_id"`
+	}
+	json.Unmarshal(data, &target)
+
+	if name == "INTERACTION_CREATE" {
+		var i discordgo.Interaction
+		if err := json.Unmarshal(data, &i); err == nil {
+			s.mu.Lock()
+			s.trackInteraction(&i)
+			s.mu.Unlock()
+		}
+	}
+
+	s.mu.Lock()
+	gateways := append([]*gatewayConn(nil), s.gateways...)
+	s.mu.Unlock()
+
+	sent := false
+	for _, conn := range gateways {
+		if !conn.owns(target.GuildID) {
+			continue
+		}
+
+		if err := s.dispatchTo(conn, name, data); err != nil {
+			return fmt.Errorf("failed to dispatch %s: %w", name, err)
+		}
+		sent = true
+	}
+
+	if !sent {
+		return ErrNoGateway
+	}
+
+	return nil
+}
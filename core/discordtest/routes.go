@@ -0,0 +1,690 @@
+package discordtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (s *Server) restRoutes() []route {
+	routes := []struct {
+		method  string
+		pattern string
+		handle  func(r *request) (int, any)
+	}{
+		{http.MethodGet, "/gateway", s.getGateway},
+		{http.MethodGet, "/gateway/bot", s.getGatewayBot},
+
+		{http.MethodGet, "/users/@me", s.getCurrentUser},
+		{http.MethodPost, "/users/@me/channels", s.createDM},
+		{http.MethodGet, "/users/{user}", s.getUser},
+
+		{http.MethodGet, "/guilds/{guild}", s.getGuild},
+		{http.MethodGet, "/guilds/{guild}/channels", s.getGuildChannels},
+		{http.MethodGet, "/guilds/{guild}/roles", s.getRoles},
+		{http.MethodPost, "/guilds/{guild}/roles", s.createRole},
+		{http.MethodGet, "/guilds/{guild}/members", s.getMembers},
+		{http.MethodGet, "/guilds/{guild}/members/{user}", s.getMember},
+		{http.MethodPatch, "/guilds/{guild}/members/{user}", s.editMember},
+		{http.MethodDelete, "/guilds/{guild}/members/{user}", s.removeMember},
+		{http.MethodPut, "/guilds/{guild}/members/{user}/roles/{role}", s.addMemberRole},
+		{http.MethodDelete, "/guilds/{guild}/members/{user}/roles/{role}", s.removeMemberRole},
+		{http.MethodPut, "/guilds/{guild}/bans/{user}", s.removeMember},
+
+		{http.MethodGet, "/channels/{channel}", s.getChannel},
+		{http.MethodPost, "/channels/{channel}/threads", s.startThread},
+		{http.MethodGet, "/channels/{channel}/messages", s.getMessages},
+		{http.MethodPost, "/channels/{channel}/messages", s.createMessage},
+		{http.MethodGet, "/channels/{channel}/messages/{message}", s.getMessage},
+		{http.MethodPatch, "/channels/{channel}/messages/{message}", s.editMessage},
+		{http.MethodDelete, "/channels/{channel}/messages/{message}", s.deleteMessage},
+		{http.MethodPost, "/channels/{channel}/messages/{message}/threads", s.startThread},
+
+		{http.MethodPost, "/interactions/{interaction}/{token}/callback", s.interactionCallback},
+		{http.MethodPost, "/webhooks/{app}/{token}", s.createFollowup},
+		{http.MethodGet, "/webhooks/{app}/{token}/messages/{message}", s.getWebhookMessage},
+		{http.MethodPatch, "/webhooks/{app}/{token}/messages/{message}", s.editWebhookMessage},
+		{http.MethodDelete, "/webhooks/{app}/{token}/messages/{message}", s.deleteWebhookMessage},
+
+		{http.MethodGet, "/applications/{app}/commands", s.getCommands},
+		{http.MethodPut, "/applications/{app}/commands", s.overwriteCommands},
+		{http.MethodGet, "/applications/{app}/guilds/{guild}/commands", s.getCommands},
+		{http.MethodPut, "/applications/{app}/guilds/{guild}/commands", s.overwriteCommands},
+	}
+
+	table := make([]route, 0, len(routes))
+	for _, r := range routes {
+		table = append(table, route{method: r.method, segments: splitPath(r.pattern), handle: r.handle})
+	}
+
+	return table
+}
+
+func badRequest(err error) (int, any) {
+	return http.StatusBadRequest, restError(0, err.Error())
+}
+
+func notFound(code int, message string) (int, any) {
+	return http.StatusNotFound, restError(code, message)
+}
+
+func (s *Server) gatewayURL() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http") + "/gateway/"
+}
+
+func (s *Server) getGateway(_ *request) (int, any) {
+	return http.StatusOK, map[string]any{"url": s.gatewayURL()}
+}
+
+func (s *Server) getGatewayBot(_ *request) (int, any) {
+	return http.StatusOK, map[string]any{
+		"url":    s.gatewayURL(),
+		"shards": 1,
+		"session_start_limit": map[string]any{
+			"total":           1000,
+			"remaining":       1000,
+			"reset_after":     0,
+			"max_concurrency": 1,
+		},
+	}
+}
+
+func (s *Server) getCurrentUser(_ *request) (int, any) {
+	return http.StatusOK, s.BotUser
+}
+
+func (s *Server) getUser(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[r.params["user"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownUser, "Unknown User")
+	}
+
+	return http.StatusOK, user
+}
+
+func (s *Server) createDM(r *request) (int, any) {
+	var body struct {
+		RecipientID string `json:"recipient_id"`
+	}
+	if err := r.decode(&body); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[body.RecipientID]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownUser, "Unknown User")
+	}
+
+	for _, channel := range s.channels {
+		if channel.Type == discordgo.ChannelTypeDM && len(channel.Recipients) > 0 && channel.Recipients[0].ID == user.ID {
+			return http.StatusOK, channel
+		}
+	}
+
+	channel := &discordgo.Channel{
+		ID:         Snowflake(),
+		Type:       discordgo.ChannelTypeDM,
+		Recipients: []*discordgo.User{user},
+	}
+	s.channels[channel.ID] = channel
+
+	return http.StatusOK, channel
+}
+
+func (s *Server) getGuild(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	guild, ok := s.guilds[r.params["guild"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+
+	return http.StatusOK, guild
+}
+
+func (s *Server) getGuildChannels(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.guilds[r.params["guild"]]; !ok {
+		return notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+
+	return http.StatusOK, s.guildChannels(r.params["guild"])
+}
+
+// guildChannels returns the channels of the guild. The caller holds the lock.
+func (s *Server) guildChannels(guildId string) []*discordgo.Channel {
+	channels := make([]*discordgo.Channel, 0)
+	for _, channel := range s.channels {
+		if channel.GuildID == guildId {
+			channels = append(channels, channel)
+		}
+	}
+
+	slices.SortFunc(channels, func(a, b *discordgo.Channel) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return channels
+}
+
+func (s *Server) getRoles(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	guild, ok := s.guilds[r.params["guild"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+
+	return http.StatusOK, guild.Roles
+}
+
+func (s *Server) createRole(r *request) (int, any) {
+	var role discordgo.Role
+	if err := r.decode(&role); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	guild, ok := s.guilds[r.params["guild"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+
+	role.ID = Snowflake()
+	guild.Roles = append(guild.Roles, &role)
+
+	return http.StatusOK, &role
+}
+
+func (s *Server) getMembers(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]*discordgo.Member, 0, len(s.members[r.params["guild"]]))
+	for _, member := range s.members[r.params["guild"]] {
+		members = append(members, member)
+	}
+
+	slices.SortFunc(members, func(a, b *discordgo.Member) int {
+		return strings.Compare(a.User.ID, b.User.ID)
+	})
+
+	return http.StatusOK, members
+}
+
+func (s *Server) getMember(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, ok := s.members[r.params["guild"]][r.params["user"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownMember, "Unknown Member")
+	}
+
+	return http.StatusOK, member
+}
+
+func (s *Server) editMember(r *request) (int, any) {
+	var params struct {
+		Nick  *string   `json:"nick"`
+		Roles *[]string `json:"roles"`
+	}
+	if err := r.decode(&params); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, ok := s.members[r.params["guild"]][r.params["user"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownMember, "Unknown Member")
+	}
+
+	if params.Nick != nil {
+		member.Nick = *params.Nick
+	}
+	if params.Roles != nil {
+		member.Roles = *params.Roles
+	}
+
+	return http.StatusOK, member
+}
+
+// removeMember kicks or bans the member.
+func (s *Server) removeMember(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.members[r.params["guild"]][r.params["user"]]; !ok {
+		return notFound(discordgo.ErrCodeUnknownMember, "Unknown Member")
+	}
+
+	delete(s.members[r.params["guild"]], r.params["user"])
+	return http.StatusNoContent, nil
+}
+
+// guildRole returns whether the role exists in the guild. The caller holds
+// the lock.
+func (s *Server) guildRole(guildId, roleId string) bool {
+	guild, ok := s.guilds[guildId]
+	if !ok {
+		return false
+	}
+
+	return slices.ContainsFunc(guild.Roles, func(role *discordgo.Role) bool {
+		return role.ID == roleId
+	})
+}
+
+func (s *Server) addMemberRole(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, ok := s.members[r.params["guild"]][r.params["user"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownMember, "Unknown Member")
+	}
+
+	if !s.guildRole(r.params["guild"], r.params["role"]) {
+		return notFound(discordgo.ErrCodeUnknownRole, "Unknown Role")
+	}
+
+	if !slices.Contains(member.Roles, r.params["role"]) {
+		member.Roles = append(member.Roles, r.params["role"])
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) removeMemberRole(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, ok := s.members[r.params["guild"]][r.params["user"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownMember, "Unknown Member")
+	}
+
+	member.Roles = slices.DeleteFunc(member.Roles, func(roleId string) bool {
+		return roleId == r.params["role"]
+	})
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) getChannel(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, ok := s.channels[r.params["channel"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+	}
+
+	return http.StatusOK, channel
+}
+
+// startThread starts a thread in the channel, from a message when the route
+// has one. Threads started in forums get their first message from the body.
+func (s *Server) startThread(r *request) (int, any) {
+	var body struct {
+		Name                string                `json:"name"`
+		Type                discordgo.ChannelType `json:"type"`
+		AutoArchiveDuration int                   `json:"auto_archive_duration"`
+		AppliedTags         []string              `json:"applied_tags"`
+		Message             map[string]any        `json:"message"`
+	}
+	if err := r.decode(&body); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parent, ok := s.channels[r.params["channel"]]
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+	}
+
+	thread := &discordgo.Channel{
+		ID:          Snowflake(),
+		GuildID:     parent.GuildID,
+		ParentID:    parent.ID,
+		Name:        body.Name,
+		Type:        body.Type,
+		AppliedTags: body.AppliedTags,
+		OwnerID:     s.BotUser.ID,
+		ThreadMetadata: &discordgo.ThreadMetadata{
+			AutoArchiveDuration: body.AutoArchiveDuration,
+		},
+	}
+
+	// Threads started from a message share its ID
+	if messageId, ok := r.params["message"]; ok {
+		if _, message := s.findMessage(parent.ID, messageId); message == nil {
+			return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+		}
+		thread.ID = messageId
+	}
+
+	if thread.Type == 0 {
+		thread.Type = discordgo.ChannelTypeGuildPublicThread
+	}
+
+	s.channels[thread.ID] = thread
+
+	if body.Message != nil {
+		message := s.storeMessage(thread.ID, body.Message)
+		thread.LastMessageID, _ = message["id"].(string)
+	}
+
+	return http.StatusCreated, thread
+}
+
+func (s *Server) getMessages(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.channels[r.params["channel"]]; !ok {
+		return notFound(discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+	}
+
+	// Newest first, like Discord
+	stored := s.messages[r.params["channel"]]
+	messages := make([]map[string]any, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		messages = append(messages, stored[i])
+	}
+
+	return http.StatusOK, messages
+}
+
+func (s *Server) createMessage(r *request) (int, any) {
+	var fields map[string]any
+	if err := r.decode(&fields); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.channels[r.params["channel"]]; !ok {
+		return notFound(discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+	}
+
+	delete(fields, "id")
+	return http.StatusOK, s.storeMessage(r.params["channel"], fields)
+}
+
+func (s *Server) getMessage(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, message := s.findMessage(r.params["channel"], r.params["message"])
+	if message == nil {
+		return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	}
+
+	return http.StatusOK, message
+}
+
+func (s *Server) editMessage(r *request) (int, any) {
+	var fields map[string]any
+	if err := r.decode(&fields); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, message := s.findMessage(r.params["channel"], r.params["message"])
+	if message == nil {
+		return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	}
+
+	return http.StatusOK, mergeMessage(message, fields)
+}
+
+func (s *Server) deleteMessage(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, message := s.findMessage(r.params["channel"], r.params["message"])
+	if message == nil {
+		return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	}
+
+	s.messages[r.params["channel"]] = slices.Delete(s.messages[r.params["channel"]], index, index+1)
+	return http.StatusNoContent, nil
+}
+
+// mergeMessage applies the edited fields to the message, IDs and authors
+// don't change.
+func mergeMessage(message, fields map[string]any) map[string]any {
+	for key, value := range fields {
+		switch key {
+		case "id", "channel_id", "guild_id", "author", "timestamp":
+		default:
+			message[key] = value
+		}
+	}
+
+	message["edited_timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+	return message
+}
+
+func (s *Server) interactionCallback(r *request) (int, any) {
+	var response struct {
+		Type discordgo.InteractionResponseType `json:"type"`
+		Data map[string]any                    `json:"data"`
+	}
+	if err := r.decode(&response); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.interactions[r.params["token"]]
+	if !ok {
+		state = s.trackInteraction(&discordgo.Interaction{ID: r.params["interaction"], Token: r.params["token"]})
+	}
+
+	if len(state.callbacks) > 0 {
+		return http.StatusBadRequest, restError(discordgo.ErrCodeInteractionHasAlreadyBeenAcknowledged, "Interaction has already been acknowledged.")
+	}
+	state.callbacks = append(state.callbacks, response.Type)
+
+	switch response.Type {
+	case discordgo.InteractionResponseChannelMessageWithSource:
+		state.original = s.storeMessage(state.interaction.ChannelID, response.Data)
+	case discordgo.InteractionResponseDeferredChannelMessageWithSource:
+		fields := map[string]any{"content": ""}
+		if flags, ok := response.Data["flags"]; ok {
+			fields["flags"] = flags
+		}
+		state.original = s.storeMessage(state.interaction.ChannelID, fields)
+	case discordgo.InteractionResponseUpdateMessage, discordgo.InteractionResponseDeferredMessageUpdate:
+		// The original response is the message of the component
+		if message := state.interaction.Message; message != nil {
+			if _, fields := s.findMessage(message.ChannelID, message.ID); fields != nil {
+				state.original = fields
+			} else {
+				state.original = s.storeMessage(message.ChannelID, toMap(message))
+			}
+
+			if response.Type == discordgo.InteractionResponseUpdateMessage {
+				mergeMessage(state.original, response.Data)
+			}
+		}
+	}
+
+	return http.StatusNoContent, nil
+}
+
+// answeredInteraction returns the interaction the webhook token belongs to
+// once it has been answered. The caller holds the lock.
+func (s *Server) answeredInteraction(token string) (*interactionState, bool) {
+	state, ok := s.interactions[token]
+	if !ok || len(state.callbacks) == 0 {
+		return nil, false
+	}
+
+	return state, true
+}
+
+// webhookMessage returns the fields of the message of the interaction, the
+// original response for "@original". The caller holds the lock.
+func (s *Server) webhookMessage(state *interactionState, messageId string) map[string]any {
+	if messageId == "@original" {
+		return state.original
+	}
+
+	if !slices.Contains(state.followups, messageId) {
+		return nil
+	}
+
+	_, message := s.findMessage(state.interaction.ChannelID, messageId)
+	return message
+}
+
+func (s *Server) createFollowup(r *request) (int, any) {
+	var fields map[string]any
+	if err := r.decode(&fields); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.answeredInteraction(r.params["token"])
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownWebhook, "Unknown Webhook")
+	}
+
+	delete(fields, "id")
+	message := s.storeMessage(state.interaction.ChannelID, fields)
+	state.followups = append(state.followups, message["id"].(string))
+
+	return http.StatusOK, message
+}
+
+func (s *Server) getWebhookMessage(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.answeredInteraction(r.params["token"])
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownWebhook, "Unknown Webhook")
+	}
+
+	message := s.webhookMessage(state, r.params["message"])
+	if message == nil {
+		return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	}
+
+	return http.StatusOK, message
+}
+
+func (s *Server) editWebhookMessage(r *request) (int, any) {
+	var fields map[string]any
+	if err := r.decode(&fields); err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.answeredInteraction(r.params["token"])
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownWebhook, "Unknown Webhook")
+	}
+
+	message := s.webhookMessage(state, r.params["message"])
+	if message == nil {
+		return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	}
+
+	return http.StatusOK, mergeMessage(message, fields)
+}
+
+func (s *Server) deleteWebhookMessage(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.answeredInteraction(r.params["token"])
+	if !ok {
+		return notFound(discordgo.ErrCodeUnknownWebhook, "Unknown Webhook")
+	}
+
+	message := s.webhookMessage(state, r.params["message"])
+	if message == nil {
+		return notFound(discordgo.ErrCodeUnknownMessage, "Unknown Message")
+	}
+
+	channelId, _ := message["channel_id"].(string)
+	if index, _ := s.findMessage(channelId, message["id"].(string)); index >= 0 {
+		s.messages[channelId] = slices.Delete(s.messages[channelId], index, index+1)
+	}
+
+	if r.params["message"] == "@original" {
+		state.original = nil
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) getCommands(r *request) (int, any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if raw, ok := s.commands[r.params["guild"]]; ok {
+		return http.StatusOK, raw
+	}
+
+	return http.StatusOK, []any{}
+}
+
+func (s *Server) overwriteCommands(r *request) (int, any) {
+	var commands []map[string]any
+	if err := r.decode(&commands); err != nil {
+		return badRequest(err)
+	}
+
+	for _, command := range commands {
+		command["id"] = Snowflake()
+		command["application_id"] = r.params["app"]
+		command["version"] = Snowflake()
+		if guildId := r.params["guild"]; guildId != "" {
+			command["guild_id"] = guildId
+		}
+	}
+
+	raw, err := json.Marshal(commands)
+	if err != nil {
+		return badRequest(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands[r.params["guild"]] = raw
+	return http.StatusOK, raw
+}
@@ -0,0 +1,375 @@
+// Package discordtest runs handlers against a local stand-in of Discord so
+// they can be tested offline. The server emulates the REST endpoints the bot
+// uses and the gateway, it records every REST call and lets tests dispatch
+// synthesized gateway events to the sessions connected to it.
+//
+// Servers point the endpoint variables of discordgo at themselves until
+// closed, so only one of them runs at a time: starting another one waits for
+// the previous one to be closed. Tests using a server can't call t.Parallel.
+package discordtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var ErrCallTimeout = errors.New("timed out waiting for the call")
+
+// endpointsMu is held by the running server, discordgo endpoints are global.
+var endpointsMu sync.Mutex
+
+// Call is a REST request received by the server, its path is relative to the
+// API root, e.g. "/channels/123/messages".
+type Call struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   json.RawMessage
+}
+
+// Decode decodes the JSON body of the call into v, multipart bodies are
+// decoded from their payload_json part.
+func (c Call) Decode(v any) error {
+	return json.Unmarshal(c.Body, v)
+}
+
+type Server struct {
+	*httptest.Server
+
+	// BotUser is the user of the bot, its ID is also the application ID.
+	BotUser *discordgo.User
+
+	mu       sync.Mutex
+	calls    []Call
+	notify   chan struct{}
+	routes   []route
+	restore  func()
+	closed   bool
+	gateways []*gatewayConn
+	sequence int64
+
+	guilds       map[string]*discordgo.Guild
+	channels     map[string]*discordgo.Channel
+	users        map[string]*discordgo.User
+	members      map[string]map[string]*discordgo.Member
+	messages     map[string][]map[string]any
+	interactions map[string]*interactionState
+	commands     map[string]json.RawMessage
+}
+
+// NewServer starts a server and points discordgo at it, blocking until any
+// other server is closed since discordgo endpoints are global.
+func NewServer() *Server {
+	endpointsMu.Lock()
+
+	bot := &discordgo.User{
+		ID:            Snowflake(),
+		Username:      "twotto",
+		Discriminator: "0",
+		Bot:           true,
+	}
+
+	s := &Server{
+		BotUser:      bot,
+		notify:       make(chan struct{}),
+		guilds:       make(map[string]*discordgo.Guild),
+		channels:     make(map[string]*discordgo.Channel),
+		users:        map[string]*discordgo.User{bot.ID: bot},
+		members:      make(map[string]map[string]*discordgo.Member),
+		messages:     make(map[string][]map[string]any),
+		interactions: make(map[string]*interactionState),
+		commands:     make(map[string]json.RawMessage),
+	}
+	s.routes = s.restRoutes()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/", s.serveREST)
+	mux.HandleFunc("/gateway/", s.serveGateway)
+
+	s.Server = httptest.NewServer(mux)
+	s.restore = pointEndpoints(s.URL + "/")
+
+	return s
+}
+
+// Close disconnects the gateway sessions, stops the server and points
+// discordgo back at Discord.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	gateways := s.gateways
+	s.gateways = nil
+	s.mu.Unlock()
+
+	for _, conn := range gateways {
+		conn.close()
+	}
+
+	s.Server.Close()
+	s.restore()
+	endpointsMu.Unlock()
+}
+
+// Session returns a new session authenticated as the bot. Call Open on it to
+// connect it to the emulated gateway.
+func (s *Server) Session() *discordgo.Session {
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		panic(err)
+	}
+
+	session.Client = s.Client()
+	session.ShouldRetryOnRateLimit = false
+	session.ShouldReconnectOnError = false
+	return session
+}
+
+// pointEndpoints points the base endpoints of discordgo at the URL, the other
+// ones are built from them. It returns a function restoring them.
+func pointEndpoints(base string) func() {
+	targets := []*string{
+		&discordgo.EndpointDiscord,
+		&discordgo.EndpointAPI,
+		&discordgo.EndpointGuilds,
+		&discordgo.EndpointChannels,
+		&discordgo.EndpointUsers,
+		&discordgo.EndpointGateway,
+		&discordgo.EndpointGatewayBot,
+		&discordgo.EndpointWebhooks,
+		&discordgo.EndpointStickers,
+		&discordgo.EndpointStageInstances,
+		&discordgo.EndpointVoice,
+		&discordgo.EndpointVoiceRegions,
+		&discordgo.EndpointNitroStickersPacks,
+		&discordgo.EndpointGuildCreate,
+		&discordgo.EndpointApplications,
+		&discordgo.EndpointOAuth2,
+		&discordgo.EndpointOAuth2Applications,
+	}
+
+	previous := make([]string, len(targets))
+	for i, target := range targets {
+		previous[i] = *target
+	}
+
+	api := base + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointDiscord = base
+	discordgo.EndpointAPI = api
+	discordgo.EndpointGuilds = api + "guilds/"
+	discordgo.EndpointChannels = api + "channels/"
+	discordgo.EndpointUsers = api + "users/"
+	discordgo.EndpointGateway = api + "gateway"
+	discordgo.EndpointGatewayBot = api + "gateway/bot"
+	discordgo.EndpointWebhooks = api + "webhooks/"
+	discordgo.EndpointStickers = api + "stickers/"
+	discordgo.EndpointStageInstances = api + "stage-instances"
+	discordgo.EndpointVoice = api + "/voice/"
+	discordgo.EndpointVoiceRegions = api + "/voice/regions"
+	discordgo.EndpointNitroStickersPacks = api + "/sticker-packs"
+	discordgo.EndpointGuildCreate = api + "guilds"
+	discordgo.EndpointApplications = api + "applications"
+	discordgo.EndpointOAuth2 = api + "oauth2/"
+	discordgo.EndpointOAuth2Applications = api + "oauth2/applications"
+
+	return func() {
+		for i, target := range targets {
+			*target = previous[i]
+		}
+	}
+}
+
+// Calls returns the REST calls received so far.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the calls matching the method and the path pattern, where
+// segments in braces match anything, e.g. "/channels/{id}/messages".
+func (s *Server) CallsTo(method, pattern string) []Call {
+	segments := splitPath(pattern)
+
+	var calls []Call
+	for _, call := range s.Calls() {
+		if call.Method == method && matchPath(segments, splitPath(call.Path)) != nil {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// WaitFor returns the first call matching the method and the path pattern,
+// waiting for it if it wasn't received yet. Handlers run in the background
+// when driven through the gateway, wait for their last call before checking
+// what they did.
+func (s *Server) WaitFor(method, pattern string, timeout time.Duration) (Call, error) {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		notify := s.notify
+		s.mu.Unlock()
+
+		if calls := s.CallsTo(method, pattern); len(calls) > 0 {
+			return calls[0], nil
+		}
+
+		select {
+		case <-notify:
+		case <-deadline:
+			return Call{}, fmt.Errorf("%w: %s %s", ErrCallTimeout, method, pattern)
+		}
+	}
+}
+
+// Reset forgets the calls received so far.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = nil
+}
+
+func (s *Server) record(call Call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, call)
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+type route struct {
+	method   string
+	segments []string
+	handle   func(r *request) (int, any)
+}
+
+type request struct {
+	*http.Request
+	params map[string]string
+	body   json.RawMessage
+}
+
+func (r *request) decode(v any) error {
+	if len(r.body) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(r.body, v)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchPath returns the values of the placeholders of the pattern, nil if
+// the path doesn't match it.
+func matchPath(pattern, path []string) map[string]string {
+	if len(pattern) != len(path) {
+		return nil
+	}
+
+	params := make(map[string]string)
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[strings.Trim(segment, "{}")] = path[i]
+		} else if segment != path[i] {
+			return nil
+		}
+	}
+
+	return params
+}
+
+// readBody reads the JSON body of the request, or the payload_json part of
+// multipart ones sending files.
+func readBody(r *http.Request) (json.RawMessage, error) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return io.ReadAll(r.Body)
+	}
+
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		if part.FormName() == "payload_json" {
+			return io.ReadAll(part)
+		}
+	}
+}
+
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request) {
+	prefix := "/api/v" + discordgo.APIVersion
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	body, err := readBody(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, restError(0, err.Error()))
+		return
+	}
+
+	s.record(Call{Method: r.Method, Path: path, Query: r.URL.Query(), Body: body})
+
+	segments := splitPath(path)
+	for _, route := range s.routes {
+		if route.method != r.Method {
+			continue
+		}
+
+		if params := matchPath(route.segments, segments); params != nil {
+			status, response := route.handle(&request{Request: r, params: params, body: body})
+
+			// Responses may point into the stored state
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			writeJSON(w, status, response)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, restError(0, "404: Not Found"))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	if status == http.StatusNoContent || body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// restError is the body of Discord errors, code is one of the JSON error
+// codes of discordgo.
+func restError(code int, message string) map[string]any {
+	return map[string]any{"code": code, "message": message}
+}
@@ -0,0 +1,334 @@
+package discordtest
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type interactionState struct {
+	interaction *discordgo.Interaction
+	callbacks   []discordgo.InteractionResponseType
+	original    map[string]any
+	followups   []string
+}
+
+// clone deep copies the value through JSON, so tests can't race with the
+// server by holding on to what it stores.
+func clone[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	var c T
+	if err := json.Unmarshal(raw, &c); err != nil {
+		panic(err)
+	}
+
+	return &c
+}
+
+func toMap(v any) map[string]any {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+func toMessage(fields map[string]any) *discordgo.Message {
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		panic(err)
+	}
+
+	var message discordgo.Message
+	if err := json.Unmarshal(raw, &message); err != nil {
+		panic(err)
+	}
+
+	return &message
+}
+
+// AddGuild adds the guild to the server, with a generated ID if it has none.
+// Its roles and channels are added along with it.
+func (s *Server) AddGuild(guild *discordgo.Guild) *discordgo.Guild {
+	guild = clone(guild)
+	if guild.ID == "" {
+		guild.ID = Snowflake()
+	}
+	if guild.Name == "" {
+		guild.Name = "Test Guild"
+	}
+	if guild.PreferredLocale == "" {
+		guild.PreferredLocale = string(discordgo.EnglishUS)
+	}
+
+	channels := guild.Channels
+	guild.Channels = nil
+
+	s.mu.Lock()
+	s.guilds[guild.ID] = guild
+	if _, ok := s.members[guild.ID]; !ok {
+		s.members[guild.ID] = make(map[string]*discordgo.Member)
+	}
+	s.mu.Unlock()
+
+	for _, channel := range channels {
+		channel.GuildID = guild.ID
+		s.AddChannel(channel)
+	}
+
+	return clone(guild)
+}
+
+// AddChannel adds the channel to the server, with a generated ID if it has
+// none.
+func (s *Server) AddChannel(channel *discordgo.Channel) *discordgo.Channel {
+	channel = clone(channel)
+	if channel.ID == "" {
+		channel.ID = Snowflake()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[channel.ID] = channel
+	return clone(channel)
+}
+
+// AddUser adds the user to the server, with a generated ID if it has none.
+func (s *Server) AddUser(user *discordgo.User) *discordgo.User {
+	user = clone(user)
+	if user.ID == "" {
+		user.ID = Snowflake()
+	}
+	if user.Discriminator == "" {
+		user.Discriminator = "0"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.ID] = user
+	return clone(user)
+}
+
+// AddMember adds the member and its user to the guild.
+func (s *Server) AddMember(guildId string, member *discordgo.Member) *discordgo.Member {
+	member = clone(member)
+	if member.User == nil {
+		member.User = &discordgo.User{}
+	}
+
+	member.User = s.AddUser(member.User)
+	member.GuildID = guildId
+	if member.JoinedAt.IsZero() {
+		member.JoinedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.members[guildId]; !ok {
+		s.members[guildId] = make(map[string]*discordgo.Member)
+	}
+
+	s.members[guildId][member.User.ID] = member
+	return clone(member)
+}
+
+// AddRole adds the role to the guild, with a generated ID if it has none.
+func (s *Server) AddRole(guildId string, role *discordgo.Role) *discordgo.Role {
+	role = clone(role)
+	if role.ID == "" {
+		role.ID = Snowflake()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if guild, ok := s.guilds[guildId]; ok {
+		guild.Roles = append(guild.Roles, role)
+	}
+
+	return clone(role)
+}
+
+// AddMessage adds the message to its channel, e.g. to dispatch its deletion
+// afterwards. It gets a generated ID and the bot as author if it has none.
+func (s *Server) AddMessage(message *discordgo.Message) *discordgo.Message {
+	fields := toMap(message)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if message.ID != "" {
+		fields["id"] = message.ID
+	}
+	if message.Author != nil {
+		fields["author"] = toMap(message.Author)
+	}
+
+	return toMessage(s.storeMessage(message.ChannelID, fields))
+}
+
+// Guild returns the guild, nil if it doesn't exist.
+func (s *Server) Guild(guildId string) *discordgo.Guild {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(s.guilds[guildId])
+}
+
+// Channel returns the channel, nil if it doesn't exist.
+func (s *Server) Channel(channelId string) *discordgo.Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(s.channels[channelId])
+}
+
+// Member returns the member of the guild, nil if it isn't in it, e.g. after
+// being kicked.
+func (s *Server) Member(guildId, userId string) *discordgo.Member {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(s.members[guildId][userId])
+}
+
+// Messages returns the messages of the channel, oldest first.
+func (s *Server) Messages(channelId string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]*discordgo.Message, 0, len(s.messages[channelId]))
+	for _, fields := range s.messages[channelId] {
+		messages = append(messages, toMessage(fields))
+	}
+
+	return messages
+}
+
+// Callbacks returns the types of the responses sent to the interaction.
+func (s *Server) Callbacks(i *discordgo.Interaction) []discordgo.InteractionResponseType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.interactions[i.Token]; ok {
+		return append([]discordgo.InteractionResponseType(nil), state.callbacks...)
+	}
+
+	return nil
+}
+
+// Original returns the original response of the interaction, nil if it
+// wasn't answered with a message.
+func (s *Server) Original(i *discordgo.Interaction) *discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.interactions[i.Token]; ok && state.original != nil {
+		return toMessage(state.original)
+	}
+
+	return nil
+}
+
+// Followups returns the followup messages of the interaction.
+func (s *Server) Followups(i *discordgo.Interaction) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.interactions[i.Token]
+	if !ok {
+		return nil
+	}
+
+	messages := make([]*discordgo.Message, 0, len(state.followups))
+	for _, id := range state.followups {
+		if _, fields := s.findMessage(state.interaction.ChannelID, id); fields != nil {
+			messages = append(messages, toMessage(fields))
+		}
+	}
+
+	return messages
+}
+
+// Commands returns the commands registered to the guild, or the global ones
+// when the guild is empty.
+func (s *Server) Commands(guildId string) []*discordgo.ApplicationCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var commands []*discordgo.ApplicationCommand
+	if raw, ok := s.commands[guildId]; ok {
+		json.Unmarshal(raw, &commands)
+	}
+
+	return commands
+}
+
+// trackInteraction remembers the interaction so its responses can be stored
+// in its channel. The caller holds the lock.
+func (s *Server) trackInteraction(i *discordgo.Interaction) *interactionState {
+	state, ok := s.interactions[i.Token]
+	if !ok {
+		state = &interactionState{interaction: clone(i)}
+		s.interactions[i.Token] = state
+	}
+
+	return state
+}
+
+// storeMessage adds a message with the fields to the channel, filling in what
+// Discord would. The caller holds the lock.
+func (s *Server) storeMessage(channelId string, fields map[string]any) map[string]any {
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	if id, _ := fields["id"].(string); id == "" {
+		fields["id"] = Snowflake()
+	}
+	if _, ok := fields["author"]; !ok {
+		fields["author"] = toMap(s.BotUser)
+	}
+	if _, ok := fields["content"]; !ok {
+		fields["content"] = ""
+	}
+
+	fields["channel_id"] = channelId
+	fields["timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+	if channel, ok := s.channels[channelId]; ok && channel.GuildID != "" {
+		fields["guild_id"] = channel.GuildID
+	}
+
+	s.messages[channelId] = append(s.messages[channelId], fields)
+	return fields
+}
+
+// findMessage returns the index and the fields of the message in the
+// channel, nil fields if it doesn't exist. The caller holds the lock.
+func (s *Server) findMessage(channelId, messageId string) (int, map[string]any) {
+	for i, fields := range s.messages[channelId] {
+		if fields["id"] == messageId {
+			return i, fields
+		}
+	}
+
+	return -1, nil
+}
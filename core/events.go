@@ -10,8 +10,17 @@ import (
 
 type EventFunc[T any] func(ctx context.Context, s *discordgo.Session, e *T) error
 
+// HandleEvent adapts an EventFunc to a discordgo handler. Handlers receive the
+// root context and are tracked so shutdown can wait for them, events received
+// once shutdown began are dropped.
 func HandleEvent[T any](fn EventFunc[T]) interface{} {
 	return func(s *discordgo.Session, e *T) {
+		if !botLifecycle.acquire() {
+			log.Debug().Msgf("[EventHandler] Dropped %T event, shutting down", e)
+			return
+		}
+		defer botLifecycle.release()
+
 		defer func() {
 			if rec := recover(); rec != nil {
 				// Get stacktrace
@@ -23,7 +32,7 @@ func HandleEvent[T any](fn EventFunc[T]) interface{} {
 			}
 		}()
 
-		if err := fn(context.Background(), s, e); err != nil {
+		if err := fn(botLifecycle.ctx, s, e); err != nil {
 			log.Error().Err(err).Msg("[EventHandler] Error executing event not handled!")
 		}
 	}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+// FeatureGateKey resolves the service telling which features are enabled,
+// handlers declaring a feature are only registered once it was provided.
+var FeatureGateKey = NewKey[FeatureGate]("core", "service/feature-gate")
+
+// FeatureGate tells whether a feature is enabled in a guild.
+type FeatureGate interface {
+	GetFeature(ctx context.Context, identifier *Identifier, guildId string) (bool, error)
+}
+
+// requirements returns the dependencies of a handler guarded by the feature.
+func requirements(requires []Dependency, feature *Identifier) []Dependency {
+	if feature == nil {
+		return requires
+	}
+
+	return append(append(make([]Dependency, 0, len(requires)+1), requires...), FeatureGateKey)
+}
+
+// eventGuild returns the guild of the event from its GuildID or GuildId
+// field, empty when it has none.
+func eventGuild(e any) string {
+	value := reflect.ValueOf(e)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+
+	for _, name := range []string{"GuildID", "GuildId"} {
+		field, ok := value.Type().FieldByName(name)
+		if !ok || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		// Fails when it's promoted from a nil embedded struct
+		if guildId, err := value.FieldByIndexErr(field.Index); err == nil {
+			return guildId.String()
+		}
+	}
+
+	return ""
+}
+
+// MidwareFeatureGate only runs the handler in the guilds the feature is
+// enabled in, events outside of guilds are skipped. Interactions are told the
+// feature is disabled instead.
+func MidwareFeatureGate[T any](feature *Identifier) MiddlewareFunc[T] {
+	return func(next EventFunc[T]) EventFunc[T] {
+		return func(ctx context.Context, s *discordgo.Session, e *T) error {
+			guildId := eventGuild(e)
+			enabled := false
+
+			if guildId != "" {
+				gate, err := Lookup(ctx, FeatureGateKey)
+				if err != nil {
+					return err
+				}
+
+				enabled, err = gate.GetFeature(ctx, feature, guildId)
+				if err != nil {
+					return fmt.Errorf("checking feature %s: %w", feature, err)
+				}
+			}
+
+			if enabled {
+				return next(ctx, s, e)
+			}
+
+			if interaction, ok := any(e).(*discordgo.InteractionCreate); ok {
+				log.Debug().Msgf("[FeatureGate] Interaction %s for disabled feature \"%s\"", interaction.ID, feature)
+				return replyFeatureDisabled(ctx, s, interaction.Interaction)
+			}
+
+			return nil
+		}
+	}
+}
+
+func replyFeatureDisabled(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction) error {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		return Respond(ctx, s, i).Autocomplete([]*discordgo.ApplicationCommandOptionChoice{})
+	}
+
+	t := coreMessages.For(InteractionLocale(i))
+	return Respond(ctx, s, i).Reply(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       t("feature.disabled.title"),
+			Description: t("feature.disabled.description"),
+			Color:       ColorWarning,
+		}},
+		Flags: discordgo.MessageFlagsEphemeral,
+	})
+}
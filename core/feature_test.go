@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type featureGateFunc func(feature *Identifier, guildId string) (bool, error)
+
+func (f featureGateFunc) GetFeature(_ context.Context, feature *Identifier, guildId string) (bool, error) {
+	return f(feature, guildId)
+}
+
+var testFeature = NewIdentifier("test", "features/greetings")
+
+// gatedContext returns a context resolving the feature gate, the feature is
+// only enabled in the guild named "enabled".
+func gatedContext(err error) context.Context {
+	container := NewContainer()
+	ProvideTo[FeatureGate](container, FeatureGateKey, featureGateFunc(func(feature *Identifier, guildId string) (bool, error) {
+		return guildId == "enabled" && feature == testFeature, err
+	}))
+
+	return WithContainer(context.Background(), container)
+}
+
+func TestMidwareFeatureGateInteractions(t *testing.T) {
+	tests := []struct {
+		name    string
+		guildId string
+		kind    discordgo.InteractionType
+		ran     bool
+		reply   discordgo.InteractionResponseType
+	}{
+		{name: "enabled", guildId: "enabled", kind: discordgo.InteractionApplicationCommand, ran: true},
+		{name: "disabled", guildId: "disabled", kind: discordgo.InteractionApplicationCommand, reply: discordgo.InteractionResponseChannelMessageWithSource},
+		{name: "direct message", kind: discordgo.InteractionMessageComponent, reply: discordgo.InteractionResponseChannelMessageWithSource},
+		{name: "autocomplete", guildId: "disabled", kind: discordgo.InteractionApplicationCommandAutocomplete, reply: discordgo.InteractionApplicationCommandAutocompleteResult},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session, rest := newRestSession(t)
+			e := userEvent("1", test.kind, "alice")
+			e.GuildID = test.guildId
+
+			ran := false
+			handler := ApplyMiddlewares(func(_ context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+				ran = true
+				return nil
+			}, MidwareFeatureGate[discordgo.InteractionCreate](testFeature))
+
+			if err := handler(gatedContext(nil), session, e); err != nil {
+				t.Fatal(err)
+			}
+			if ran != test.ran {
+				t.Fatalf("got ran %v, want %v", ran, test.ran)
+			}
+
+			requests := rest.sent("POST", "/callback")
+			if test.ran {
+				if len(requests) != 0 {
+					t.Errorf("got %d callbacks for an enabled feature", len(requests))
+				}
+				return
+			}
+
+			if len(requests) != 1 {
+				t.Fatalf("got %d callbacks, want the feature disabled reply", len(requests))
+			}
+
+			var response sentResponse
+			if err := requests[0].decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Type != test.reply {
+				t.Errorf("got response type %d, want %d", response.Type, test.reply)
+			}
+			if test.reply == discordgo.InteractionResponseChannelMessageWithSource && (response.Data.Flags != discordgo.MessageFlagsEphemeral || len(response.Data.Embeds) != 1 || response.Data.Embeds[0].Title != coreMessages.Translate(DefaultLocale, "feature.disabled.title", nil)) {
+				t.Errorf("got response %+v, want the ephemeral feature disabled embed", response)
+			}
+		})
+	}
+}
+
+func TestMidwareFeatureGateEvents(t *testing.T) {
+	ran := make([]string, 0)
+	handler := ApplyMiddlewares(func(_ context.Context, _ *discordgo.Session, e *memberKickedEvent) error {
+		ran = append(ran, e.GuildId)
+		return nil
+	}, MidwareFeatureGate[memberKickedEvent](testFeature))
+
+	// Events of disabled guilds and outside of guilds are skipped silently
+	for _, guildId := range []string{"enabled", "disabled", ""} {
+		if err := handler(gatedContext(nil), nil, &memberKickedEvent{GuildId: guildId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(ran) != 1 || ran[0] != "enabled" {
+		t.Errorf("got the handler run for %v, want only the enabled guild", ran)
+	}
+}
+
+func TestMidwareFeatureGateError(t *testing.T) {
+	failure := errors.New("database is down")
+	handler := ApplyMiddlewares(func(_ context.Context, _ *discordgo.Session, _ *memberKickedEvent) error {
+		t.Error("the handler ran without knowing whether the feature is enabled")
+		return nil
+	}, MidwareFeatureGate[memberKickedEvent](testFeature))
+
+	if err := handler(gatedContext(failure), nil, &memberKickedEvent{GuildId: "disabled"}); !errors.Is(err, failure) {
+		t.Errorf("got %v, want %v", err, failure)
+	}
+
+	// Without a gate to ask
+	if err := handler(context.Background(), nil, &memberKickedEvent{GuildId: "enabled"}); !errors.Is(err, ErrDependencyNotProvided) {
+		t.Errorf("got %v, want %v", err, ErrDependencyNotProvided)
+	}
+}
+
+func TestEventGuild(t *testing.T) {
+	tests := []struct {
+		event any
+		want  string
+	}{
+		{&discordgo.MessageCreate{Message: &discordgo.Message{GuildID: "guild"}}, "guild"},
+		{&discordgo.InteractionCreate{Interaction: &discordgo.Interaction{GuildID: "guild"}}, "guild"},
+		{&discordgo.InteractionCreate{}, ""},
+		{&memberKickedEvent{GuildId: "guild"}, "guild"},
+		{&discordgo.Ready{}, ""},
+		{(*memberKickedEvent)(nil), ""},
+		{"guild", ""},
+	}
+
+	for _, test := range tests {
+		if got := eventGuild(test.event); got != test.want {
+			t.Errorf("%T: got %q, want %q", test.event, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
+	"github.com/downloadablefox/twotto/core/discordtest"
+)
+
+func TestCommandThroughGateway(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	session := server.Session()
+	bot := discordtest.NewBot(session)
+
+	identifier := core.NewIdentifier("test", "commands/ping")
+	err := core.RegisterCommands(bot, &core.Command{
+		Identifier: identifier,
+		Definition: &discordgo.ApplicationCommand{Name: "ping", Description: "Ping"},
+		Handler: func(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			return core.Respond(ctx, s, e.Interaction).Reply(&discordgo.InteractionResponseData{Content: "pong"})
+		},
+		Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+			core.MidwareCooldown(identifier, core.PerUser(1, time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	user := server.AddUser(&discordgo.User{Username: "fox"})
+
+	first := server.Command("ping", nil, discordtest.InDM(user))
+	if err := server.Dispatch(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.WaitFor("POST", "/interactions/"+first.ID+"/{token}/callback", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if original := server.Original(first.Interaction); original == nil || original.Content != "pong" {
+		t.Errorf("got original response %+v, want pong", original)
+	}
+
+	// The second use is on cooldown, the reply is in the locale of the user
+	second := server.Command("ping", nil, discordtest.InDM(user), discordtest.WithLocale(discordgo.SpanishES))
+	if err := server.Dispatch(second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.WaitFor("POST", "/interactions/"+second.ID+"/{token}/callback", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	original := server.Original(second.Interaction)
+	if original == nil || len(original.Embeds) != 1 {
+		t.Fatalf("got original response %+v, want the cooldown embed", original)
+	}
+	if original.Embeds[0].Title != "¡Más despacio!" {
+		t.Errorf("got title %q, want the Spanish one", original.Embeds[0].Title)
+	}
+	if original.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("the cooldown reply isn't ephemeral")
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventHandler is the event counterpart of Command, it binds a gateway event
+// handler to its middlewares and dependencies.
+type EventHandler[T any] struct {
+	Identifier *Identifier
+	Handler    EventFunc[T]
+
+	// Middlewares wrap the handler, the first one being the outermost.
+	Middlewares []MiddlewareFunc[T]
+
+	// Requires lists the dependencies the handler resolves with Use.
+	Requires []Dependency
+
+	// Feature guards the handler, it only runs for the events of the guilds
+	// the feature is enabled in.
+	Feature *Identifier
+
+	// Timeout bounds how long the handler can run, DefaultHandlerTimeout is
+	// used when zero.
+	Timeout time.Duration
+}
+
+func (h *EventHandler[T]) middlewares() []MiddlewareFunc[T] {
+	middlewares := make([]MiddlewareFunc[T], 0, len(h.Middlewares)+2)
+	if h.Feature != nil {
+		middlewares = append(middlewares, MidwareFeatureGate[T](h.Feature))
+	}
+	middlewares = append(middlewares, h.Middlewares...)
+
+	return append(middlewares, MidwareTimeout[T](h.Identifier))
+}
+
+// AddEventHandler checks the dependencies of the handler and adds it to the
+// session, wrapped with its middlewares and its timeout.
+func AddEventHandler[T any](bot *Bot, handler *EventHandler[T]) error {
+	if handler.Identifier == nil || handler.Handler == nil {
+		return fmt.Errorf("%w: event handlers need an identifier and a handler", ErrInvalidCommandDeclaration)
+	}
+
+	if err := bot.Container.Validate(requirements(handler.Requires, handler.Feature)...); err != nil {
+		return fmt.Errorf("event handler %s: %w", handler.Identifier, err)
+	}
+
+	if handler.Timeout != 0 {
+		SetHandlerTimeout(handler.Identifier, handler.Timeout)
+	}
+
+	middlewares := handler.middlewares()
+	bot.AddHandler(HandleEvent(bindBot(bot, ApplyMiddlewares(handler.Handler, middlewares...))))
+
+	bot.Handlers.add(handlerInfo(HandlerEvent, handler.Identifier, handler.Feature, handler.Requires, middlewares))
+	return nil
+}
@@ -2,552 +2,225 @@ package core
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-type CommandBuilder struct {
-	discordgo.ApplicationCommand
-}
-
-func NewCommandBuilder() *CommandBuilder {
-	return &CommandBuilder{}
-}
-
-func (c *CommandBuilder) SetName(name string) *CommandBuilder {
-	c.Name = name
-	return c
-}
-
-func (c *CommandBuilder) SetDescription(description string) *CommandBuilder {
-	c.Description = description
-	return c
-}
-
-func (c *CommandBuilder) SetDMPermission(permission bool) *CommandBuilder {
-	c.DMPermission = &permission
-	return c
-}
-
-func (c *CommandBuilder) SetDefaultMemberPermissions(permissions int64) *CommandBuilder {
-	c.DefaultMemberPermissions = &permissions
-	return c
-}
+// Command data helper
+var ErrOptionNotFound = errors.New("option not found")
+var ErrOptionUnexpectedType = errors.New("unexpected option type")
 
-func (c *CommandBuilder) SetNSFW(nsfw bool) *CommandBuilder {
-	c.NSFW = &nsfw
-	return c
-}
+func GetStringOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (string, error) {
+	for _, option := range data {
+		if option.Name == name {
+			if option.Type != discordgo.ApplicationCommandOptionString {
+				return "", ErrOptionUnexpectedType
+			}
 
-func (c *CommandBuilder) AddNameLocale(locale discordgo.Locale, name string) *CommandBuilder {
-	if c.NameLocalizations == nil {
-		temp := make(map[discordgo.Locale]string)
-		c.NameLocalizations = &temp
+			return option.StringValue(), nil
+		}
 	}
 
-	(*c.NameLocalizations)[locale] = name
-
-	return c
+	return "", ErrOptionNotFound
 }
 
-func (c *CommandBuilder) AddDescriptionLocale(locale discordgo.Locale, description string) *CommandBuilder {
-	if c.DescriptionLocalizations == nil {
-		temp := make(map[discordgo.Locale]string)
-		c.DescriptionLocalizations = &temp
+func GetStringDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def string) string {
+	value, err := GetStringOption(data, name)
+	if err != nil {
+		return def
 	}
 
-	(*c.DescriptionLocalizations)[locale] = description
-
-	return c
+	return value
 }
 
-type StringOptionSupplier func(*StringOptionBuilder)
-type IntegerOptionSupplier func(*IntegerOptionBuilder)
-type BooleanOptionSupplier func(*BooleanOptionBuilder)
-type UserOptionSupplier func(*UserOptionBuilder)
-type ChannelOptionSupplier func(*ChannelOptionBuilder)
-type RoleOptionSupplier func(*RoleOptionBuilder)
-type MentionableOptionSupplier func(*MentionableOptionBuilder)
-type NumberOptionSupplier func(*NumberOptionBuilder)
-
-type CommandOption interface {
-	Build() *discordgo.ApplicationCommandOption
-}
+func GetIntegerOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (int, error) {
+	for _, option := range data {
+		if option.Name == name {
+			if option.Type != discordgo.ApplicationCommandOptionInteger {
+				return 0, ErrOptionUnexpectedType
+			}
 
-func (c *CommandBuilder) AddOption(option CommandOption) *CommandBuilder {
-	if c.Options == nil {
-		temp := make([]*discordgo.ApplicationCommandOption, 0)
-		c.Options = temp
+			return int(option.IntValue()), nil
+		}
 	}
 
-	c.Options = append(c.Options, option.Build())
-	return c
-}
-
-func (c *CommandBuilder) AddStringOption(supplier StringOptionSupplier) *CommandBuilder {
-	builder := NewStringOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddIntegerOption(supplier IntegerOptionSupplier) *CommandBuilder {
-	builder := NewIntegerOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddBooleanOption(supplier BooleanOptionSupplier) *CommandBuilder {
-	builder := NewBooleanOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddUserOption(supplier UserOptionSupplier) *CommandBuilder {
-	builder := NewUserOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddChannelOption(supplier ChannelOptionSupplier) *CommandBuilder {
-	builder := NewChannelOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddRoleOption(supplier RoleOptionSupplier) *CommandBuilder {
-	builder := NewRoleOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddMentionableOption(supplier MentionableOptionSupplier) *CommandBuilder {
-	builder := NewMentionableOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddNumberOption(supplier NumberOptionSupplier) *CommandBuilder {
-	builder := NewNumberOptionBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) AddSubCommand(supplier func(*SubCommandBuilder)) *CommandBuilder {
-	builder := NewSubCommandBuilder()
-	supplier(builder)
-	c.AddOption(builder)
-	return c
-}
-
-func (c *CommandBuilder) Build() *discordgo.ApplicationCommand {
-	return &c.ApplicationCommand
-}
-
-type StringOptionBuilder struct {
-	discordgo.ApplicationCommandOption
+	return 0, ErrOptionNotFound
 }
 
-func NewStringOptionBuilder() *StringOptionBuilder {
-	return &StringOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionString,
-		},
+func GetIntegerDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def int) int {
+	value, err := GetIntegerOption(data, name)
+	if err != nil {
+		return def
 	}
-}
 
-func (s *StringOptionBuilder) SetName(name string) *StringOptionBuilder {
-	s.Name = name
-	return s
-}
-
-func (s *StringOptionBuilder) SetDescription(description string) *StringOptionBuilder {
-	s.Description = description
-	return s
-}
-
-func (s *StringOptionBuilder) SetRequired(required bool) *StringOptionBuilder {
-	s.Required = required
-	return s
-}
-
-func (s *StringOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &s.ApplicationCommandOption
+	return value
 }
 
-type IntegerOptionBuilder struct {
-	discordgo.ApplicationCommandOption
-}
+func GetBooleanOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (bool, error) {
+	for _, option := range data {
+		if option.Name == name {
+			if option.Type != discordgo.ApplicationCommandOptionBoolean {
+				return false, ErrOptionUnexpectedType
+			}
 
-func NewIntegerOptionBuilder() *IntegerOptionBuilder {
-	return &IntegerOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionInteger,
-		},
+			return option.BoolValue(), nil
+		}
 	}
-}
-
-func (i *IntegerOptionBuilder) SetName(name string) *IntegerOptionBuilder {
-	i.Name = name
-	return i
-}
-
-func (i *IntegerOptionBuilder) SetDescription(description string) *IntegerOptionBuilder {
-	i.Description = description
-	return i
-}
-
-func (i *IntegerOptionBuilder) SetRequired(required bool) *IntegerOptionBuilder {
-	i.Required = required
-	return i
-}
 
-func (i *IntegerOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &i.ApplicationCommandOption
-}
-
-type BooleanOptionBuilder struct {
-	discordgo.ApplicationCommandOption
+	return false, ErrOptionNotFound
 }
 
-func NewBooleanOptionBuilder() *BooleanOptionBuilder {
-	return &BooleanOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionBoolean,
-		},
+func GetBooleanDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def bool) bool {
+	value, err := GetBooleanOption(data, name)
+	if err != nil {
+		return def
 	}
-}
 
-func (b *BooleanOptionBuilder) SetName(name string) *BooleanOptionBuilder {
-	b.Name = name
-	return b
-}
-
-func (b *BooleanOptionBuilder) SetDescription(description string) *BooleanOptionBuilder {
-	b.Description = description
-	return b
-}
-
-func (b *BooleanOptionBuilder) SetRequired(required bool) *BooleanOptionBuilder {
-	b.Required = required
-	return b
-}
-
-func (b *BooleanOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &b.ApplicationCommandOption
-}
-
-type UserOptionBuilder struct {
-	discordgo.ApplicationCommandOption
+	return value
 }
 
-func NewUserOptionBuilder() *UserOptionBuilder {
-	return &UserOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionUser,
-		},
+func getResolvedData(i *discordgo.Interaction) *discordgo.ApplicationCommandInteractionDataResolved {
+	if i == nil {
+		return nil
 	}
-}
-
-func (u *UserOptionBuilder) SetName(name string) *UserOptionBuilder {
-	u.Name = name
-	return u
-}
-
-func (u *UserOptionBuilder) SetDescription(description string) *UserOptionBuilder {
-	u.Description = description
-	return u
-}
-
-func (u *UserOptionBuilder) SetRequired(required bool) *UserOptionBuilder {
-	u.Required = required
-	return u
-}
 
-func (u *UserOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &u.ApplicationCommandOption
-}
-
-type ChannelOptionBuilder struct {
-	discordgo.ApplicationCommandOption
-}
-
-func NewChannelOptionBuilder() *ChannelOptionBuilder {
-	return &ChannelOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionChannel,
-		},
+	data, ok := i.Data.(discordgo.ApplicationCommandInteractionData)
+	if !ok {
+		return nil
 	}
-}
-
-func (c *ChannelOptionBuilder) SetName(name string) *ChannelOptionBuilder {
-	c.Name = name
-	return c
-}
-
-func (c *ChannelOptionBuilder) SetDescription(description string) *ChannelOptionBuilder {
-	c.Description = description
-	return c
-}
-
-func (c *ChannelOptionBuilder) SetRequired(required bool) *ChannelOptionBuilder {
-	c.Required = required
-	return c
-}
-
-func (c *ChannelOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &c.ApplicationCommandOption
-}
 
-type RoleOptionBuilder struct {
-	discordgo.ApplicationCommandOption
+	return data.Resolved
 }
 
-func NewRoleOptionBuilder() *RoleOptionBuilder {
-	return &RoleOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionRole,
-		},
+// Entities are read from the interaction's resolved data first, then from the
+// state and finally from the REST API. Passing a nil session skips the
+// fallbacks.
+func resolveUser(s *discordgo.Session, guildId string, resolved *discordgo.ApplicationCommandInteractionDataResolved, id string) (*discordgo.User, error) {
+	if resolved != nil {
+		if user, ok := resolved.Users[id]; ok {
+			return user, nil
+		}
 	}
-}
 
-func (r *RoleOptionBuilder) SetName(name string) *RoleOptionBuilder {
-	r.Name = name
-	return r
-}
-
-func (r *RoleOptionBuilder) SetDescription(description string) *RoleOptionBuilder {
-	r.Description = description
-	return r
-}
-
-func (r *RoleOptionBuilder) SetRequired(required bool) *RoleOptionBuilder {
-	r.Required = required
-	return r
-}
-
-func (r *RoleOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &r.ApplicationCommandOption
-}
-
-type MentionableOptionBuilder struct {
-	discordgo.ApplicationCommandOption
-}
-
-func NewMentionableOptionBuilder() *MentionableOptionBuilder {
-	return &MentionableOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionMentionable,
-		},
+	if s == nil {
+		return nil, ErrOptionNotResolved
 	}
-}
-
-func (m *MentionableOptionBuilder) SetName(name string) *MentionableOptionBuilder {
-	m.Name = name
-	return m
-}
-
-func (m *MentionableOptionBuilder) SetDescription(description string) *MentionableOptionBuilder {
-	m.Description = description
-	return m
-}
-
-func (m *MentionableOptionBuilder) SetRequired(required bool) *MentionableOptionBuilder {
-	m.Required = required
-	return m
-}
 
-func (m *MentionableOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &m.ApplicationCommandOption
-}
-
-type NumberOptionBuilder struct {
-	discordgo.ApplicationCommandOption
-}
-
-func NewNumberOptionBuilder() *NumberOptionBuilder {
-	return &NumberOptionBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionNumber,
-		},
+	if guildId != "" {
+		if member, err := s.State.Member(guildId, id); err == nil && member.User != nil {
+			return member.User, nil
+		}
 	}
-}
-
-func (n *NumberOptionBuilder) SetName(name string) *NumberOptionBuilder {
-	n.Name = name
-	return n
-}
-
-func (n *NumberOptionBuilder) SetDescription(description string) *NumberOptionBuilder {
-	n.Description = description
-	return n
-}
-
-func (n *NumberOptionBuilder) SetRequired(required bool) *NumberOptionBuilder {
-	n.Required = required
-	return n
-}
-
-func (n *NumberOptionBuilder) SetMin(min float64) *NumberOptionBuilder {
-	n.MinValue = &min
-	return n
-}
-
-func (n *NumberOptionBuilder) SetMax(max float64) *NumberOptionBuilder {
-	n.MaxValue = max
-	return n
-}
 
-func (n *NumberOptionBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &n.ApplicationCommandOption
+	return s.User(id)
 }
 
-type OptionBuilder struct {
-	discordgo.ApplicationCommandOption
-}
+func resolveMember(s *discordgo.Session, guildId string, resolved *discordgo.ApplicationCommandInteractionDataResolved, id string) (*discordgo.Member, error) {
+	if resolved != nil {
+		if member, ok := resolved.Members[id]; ok {
+			// Resolved members don't include their user
+			if member.User == nil {
+				member.User = resolved.Users[id]
+			}
 
-type SubCommandBuilder struct {
-	discordgo.ApplicationCommandOption
-}
+			member.GuildID = guildId
+			return member, nil
+		}
+	}
 
-func NewSubCommandBuilder() *SubCommandBuilder {
-	return &SubCommandBuilder{
-		ApplicationCommandOption: discordgo.ApplicationCommandOption{
-			Type: discordgo.ApplicationCommandOptionSubCommand,
-		},
+	if s == nil || guildId == "" {
+		return nil, ErrOptionNotResolved
 	}
-}
 
-func (s *SubCommandBuilder) SetName(name string) *SubCommandBuilder {
-	s.Name = name
-	return s
-}
+	if member, err := s.State.Member(guildId, id); err == nil {
+		return member, nil
+	}
 
-func (s *SubCommandBuilder) SetDescription(description string) *SubCommandBuilder {
-	s.Description = description
-	return s
+	return s.GuildMember(guildId, id)
 }
 
-func (s *SubCommandBuilder) AddOption(option CommandOption) *SubCommandBuilder {
-	if s.Options == nil {
-		temp := make([]*discordgo.ApplicationCommandOption, 0)
-		s.Options = temp
+func resolveChannel(s *discordgo.Session, resolved *discordgo.ApplicationCommandInteractionDataResolved, id string) (*discordgo.Channel, error) {
+	if resolved != nil {
+		if channel, ok := resolved.Channels[id]; ok {
+			return channel, nil
+		}
 	}
 
-	s.Options = append(s.Options, option.Build())
-	return s
-}
-
-func (s *SubCommandBuilder) AddStringOption(supplier StringOptionSupplier) *SubCommandBuilder {
-	builder := NewStringOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+	if s == nil {
+		return nil, ErrOptionNotResolved
+	}
 
-func (s *SubCommandBuilder) AddIntegerOption(supplier IntegerOptionSupplier) *SubCommandBuilder {
-	builder := NewIntegerOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+	if channel, err := s.State.Channel(id); err == nil {
+		return channel, nil
+	}
 
-func (s *SubCommandBuilder) AddBooleanOption(supplier BooleanOptionSupplier) *SubCommandBuilder {
-	builder := NewBooleanOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
+	return s.Channel(id)
 }
 
-func (s *SubCommandBuilder) AddUserOption(supplier UserOptionSupplier) *SubCommandBuilder {
-	builder := NewUserOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+func resolveRole(s *discordgo.Session, guildId string, resolved *discordgo.ApplicationCommandInteractionDataResolved, id string) (*discordgo.Role, error) {
+	if resolved != nil {
+		if role, ok := resolved.Roles[id]; ok {
+			return role, nil
+		}
+	}
 
-func (s *SubCommandBuilder) AddChannelOption(supplier ChannelOptionSupplier) *SubCommandBuilder {
-	builder := NewChannelOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+	if s == nil || guildId == "" {
+		return nil, ErrOptionNotResolved
+	}
 
-func (s *SubCommandBuilder) AddRoleOption(supplier RoleOptionSupplier) *SubCommandBuilder {
-	builder := NewRoleOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+	if role, err := s.State.Role(guildId, id); err == nil {
+		return role, nil
+	}
 
-func (s *SubCommandBuilder) AddMentionableOption(supplier MentionableOptionSupplier) *SubCommandBuilder {
-	builder := NewMentionableOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+	roles, err := s.GuildRoles(guildId)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *SubCommandBuilder) AddNumberOption(supplier NumberOptionSupplier) *SubCommandBuilder {
-	builder := NewNumberOptionBuilder()
-	supplier(builder)
-	s.AddOption(builder)
-	return s
-}
+	for _, role := range roles {
+		if role.ID == id {
+			return role, nil
+		}
+	}
 
-func (s *SubCommandBuilder) Build() *discordgo.ApplicationCommandOption {
-	return &s.ApplicationCommandOption
+	return nil, ErrOptionNotResolved
 }
 
-// Command data helper
-var ErrOptionNotFound = errors.New("option not found")
-var ErrOptionUnexpectedType = errors.New("unexpected option type")
-
-func GetStringOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (string, error) {
-	for _, option := range data {
-		if option.Name == name {
-			if option.Type != discordgo.ApplicationCommandOptionString {
-				return "", ErrOptionUnexpectedType
-			}
-
-			return option.StringValue(), nil
+func resolveAttachment(resolved *discordgo.ApplicationCommandInteractionDataResolved, id string) (*discordgo.MessageAttachment, error) {
+	if resolved != nil {
+		if attachment, ok := resolved.Attachments[id]; ok {
+			return attachment, nil
 		}
 	}
 
-	return "", ErrOptionNotFound
+	return nil, ErrOptionNotResolved
 }
 
-func GetStringDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def string) string {
-	value, err := GetStringOption(data, name)
-	if err != nil {
-		return def
+func getTypedSnowflakeOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, types ...discordgo.ApplicationCommandOptionType) (string, error) {
+	option := findOption(data, name)
+	if option == nil {
+		return "", ErrOptionNotFound
 	}
 
-	return value
-}
+	if !slices.Contains(types, option.Type) {
+		return "", ErrOptionUnexpectedType
+	}
 
-func GetIntegerOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (int, error) {
-	for _, option := range data {
-		if option.Name == name {
-			if option.Type != discordgo.ApplicationCommandOptionInteger {
-				return 0, ErrOptionUnexpectedType
-			}
+	return getSnowflakeOption(data, name)
+}
 
-			return int(option.IntValue()), nil
-		}
+func GetUserOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.User, error) {
+	id, err := getTypedSnowflakeOption(data, name, discordgo.ApplicationCommandOptionUser, discordgo.ApplicationCommandOptionMentionable)
+	if err != nil {
+		return nil, err
 	}
 
-	return 0, ErrOptionNotFound
+	return resolveUser(s, i.GuildID, getResolvedData(i), id)
 }
 
-func GetIntegerDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def int) int {
-	value, err := GetIntegerOption(data, name)
+func GetUserDefaultOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.User) *discordgo.User {
+	value, err := GetUserOption(s, i, data, name)
 	if err != nil {
 		return def
 	}
@@ -555,22 +228,17 @@ func GetIntegerDefaultOption(data []*discordgo.ApplicationCommandInteractionData
 	return value
 }
 
-func GetBooleanOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (bool, error) {
-	for _, option := range data {
-		if option.Name == name {
-			if option.Type != discordgo.ApplicationCommandOptionBoolean {
-				return false, ErrOptionUnexpectedType
-			}
-
-			return option.BoolValue(), nil
-		}
+func GetMemberOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.Member, error) {
+	id, err := getTypedSnowflakeOption(data, name, discordgo.ApplicationCommandOptionUser, discordgo.ApplicationCommandOptionMentionable)
+	if err != nil {
+		return nil, err
 	}
 
-	return false, ErrOptionNotFound
+	return resolveMember(s, i.GuildID, getResolvedData(i), id)
 }
 
-func GetBooleanDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def bool) bool {
-	value, err := GetBooleanOption(data, name)
+func GetMemberDefaultOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.Member) *discordgo.Member {
+	value, err := GetMemberOption(s, i, data, name)
 	if err != nil {
 		return def
 	}
@@ -578,23 +246,17 @@ func GetBooleanDefaultOption(data []*discordgo.ApplicationCommandInteractionData
 	return value
 }
 
-/*
-func GetUserOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.User, error) {
-	for _, option := range data {
-		if option.Name == name {
-			if option.Type != discordgo.ApplicationCommandOptionUser {
-				return nil, ErrOptionUnexpectedType
-			}
-
-			return option.UserValue(), nil
-		}
+func GetChannelOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.Channel, error) {
+	id, err := getTypedSnowflakeOption(data, name, discordgo.ApplicationCommandOptionChannel)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, ErrOptionNotFound
+	return resolveChannel(s, getResolvedData(i), id)
 }
 
-func GetUserDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.User) *discordgo.User {
-	value, err := GetUserOption(data, name)
+func GetChannelDefaultOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.Channel) *discordgo.Channel {
+	value, err := GetChannelOption(s, i, data, name)
 	if err != nil {
 		return def
 	}
@@ -602,22 +264,17 @@ func GetUserDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOpt
 	return value
 }
 
-func GetChannelOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.Channel, error) {
-	for _, option := range data {
-		if option.Name == name {
-			if option.Type != discordgo.ApplicationCommandOptionChannel {
-				return nil, ErrOptionUnexpectedType
-			}
-
-			return option.ChannelValue(), nil
-		}
+func GetRoleOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.Role, error) {
+	id, err := getTypedSnowflakeOption(data, name, discordgo.ApplicationCommandOptionRole, discordgo.ApplicationCommandOptionMentionable)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, ErrOptionNotFound
+	return resolveRole(s, i.GuildID, getResolvedData(i), id)
 }
 
-func GetChannelDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.Channel) *discordgo.Channel {
-	value, err := GetChannelOption(data, name)
+func GetRoleDefaultOption(s *discordgo.Session, i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.Role) *discordgo.Role {
+	value, err := GetRoleOption(s, i, data, name)
 	if err != nil {
 		return def
 	}
@@ -625,29 +282,23 @@ func GetChannelDefaultOption(data []*discordgo.ApplicationCommandInteractionData
 	return value
 }
 
-func GetRoleOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, session *discordgo.Session, guild string) (*discordgo.Role, error) {
-	for _, option := range data {
-		if option.Name == name {
-			if option.Type != discordgo.ApplicationCommandOptionRole {
-				return nil, ErrOptionUnexpectedType
-			}
-
-			return option.RoleValue(session, guild), nil
-		}
+func GetAttachmentOption(i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string) (*discordgo.MessageAttachment, error) {
+	id, err := getTypedSnowflakeOption(data, name, discordgo.ApplicationCommandOptionAttachment)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, ErrOptionNotFound
+	return resolveAttachment(getResolvedData(i), id)
 }
 
-func GetRoleDefaultOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.Role) *discordgo.Role {
-	value, err := GetRoleOption(data, )
+func GetAttachmentDefaultOption(i *discordgo.Interaction, data []*discordgo.ApplicationCommandInteractionDataOption, name string, def *discordgo.MessageAttachment) *discordgo.MessageAttachment {
+	value, err := GetAttachmentOption(i, data, name)
 	if err != nil {
 		return def
 	}
 
 	return value
 }
-*/
 
 func GetMentionableOption(data []*discordgo.ApplicationCommandInteractionDataOption, name string) (string, error) {
 	for _, option := range data {
@@ -702,3 +353,19 @@ func GetSubcommandOption(data []*discordgo.ApplicationCommandInteractionDataOpti
 
 	return data[0].Name
 }
+
+// Returns the options of the invoked subcommand, skipping any subcommand
+// group and subcommand options.
+func GetCommandOptions(data discordgo.ApplicationCommandInteractionData) []*discordgo.ApplicationCommandInteractionDataOption {
+	options := data.Options
+	for len(options) > 0 {
+		option := options[0]
+		if option.Type != discordgo.ApplicationCommandOptionSubCommandGroup && option.Type != discordgo.ApplicationCommandOptionSubCommand {
+			break
+		}
+
+		options = option.Options
+	}
+
+	return options
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type HandlerKind string
+
+const (
+	HandlerCommand    HandlerKind = "command"
+	HandlerComponent  HandlerKind = "component"
+	HandlerModal      HandlerKind = "modal"
+	HandlerEvent      HandlerKind = "event"
+	HandlerSubscriber HandlerKind = "subscriber"
+	HandlerJob        HandlerKind = "job"
+)
+
+// HandlerInfo describes a registered handler, middlewares are listed in the
+// order they wrap it, including the ones added on registration.
+type HandlerInfo struct {
+	Identifier  string      `json:"identifier"`
+	Kind        HandlerKind `json:"kind"`
+	Event       string      `json:"event"`
+	Routes      []string    `json:"routes,omitempty"`
+	Module      string      `json:"module,omitempty"`
+	Middlewares []string    `json:"middlewares"`
+	Feature     string      `json:"feature,omitempty"`
+	Requires    []string    `json:"requires,omitempty"`
+	Timeout     string      `json:"timeout"`
+}
+
+// HandlerRegistry keeps track of every handler registered on the bot, so what
+// is loaded can be inspected at runtime.
+type HandlerRegistry struct {
+	mu       sync.RWMutex
+	handlers []HandlerInfo
+	module   string
+}
+
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{handlers: make([]HandlerInfo, 0)}
+}
+
+// setModule attributes the handlers registered from now on to the module,
+// none when empty.
+func (r *HandlerRegistry) setModule(name string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.module = name
+}
+
+func (r *HandlerRegistry) add(info HandlerInfo) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info.Module = r.module
+	r.handlers = append(r.handlers, info)
+}
+
+// removeModule drops the handlers of the module, once it failed to load.
+func (r *HandlerRegistry) removeModule(name string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers = slices.DeleteFunc(r.handlers, func(info HandlerInfo) bool {
+		return info.Module == name
+	})
+}
+
+// Handlers returns the registered handlers sorted by module, then kind and
+// identifier.
+func (r *HandlerRegistry) Handlers() []HandlerInfo {
+	r.mu.RLock()
+	handlers := slices.Clone(r.handlers)
+	r.mu.RUnlock()
+
+	slices.SortStableFunc(handlers, func(a, b HandlerInfo) int {
+		if c := strings.Compare(a.Module, b.Module); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Kind), string(b.Kind)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Identifier, b.Identifier)
+	})
+
+	return handlers
+}
+
+// handlerInfo describes the handler of event T, wrapped with the middlewares.
+func handlerInfo[T any](kind HandlerKind, identifier *Identifier, feature *Identifier, requires []Dependency, middlewares []MiddlewareFunc[T]) HandlerInfo {
+	info := HandlerInfo{
+		Identifier:  identifier.String(),
+		Kind:        kind,
+		Event:       eventType[T]().String(),
+		Middlewares: make([]string, 0, len(middlewares)),
+		Timeout:     timeoutName(GetHandlerTimeout(identifier)),
+	}
+
+	for _, middleware := range middlewares {
+		info.Middlewares = append(info.Middlewares, middlewareName(middleware))
+	}
+	for _, dependency := range requires {
+		info.Requires = append(info.Requires, dependency.String())
+	}
+	if feature != nil {
+		info.Feature = feature.String()
+	}
+
+	return info
+}
+
+func timeoutName(timeout time.Duration) string {
+	if timeout < 0 {
+		return "none"
+	}
+
+	return timeout.String()
+}
+
+// closureSuffix matches what the compiler appends to the names of closures
+// and generic functions, e.g. "[...].func1".
+var closureSuffix = regexp.MustCompile(`(\[\.\.\.\])?(\.func\d+)+(\.\d+)*$`)
+
+// middlewareName returns the name of the function that built the middleware,
+// e.g. "debug.MidwareErrorWrap".
+func middlewareName(middleware any) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return closureSuffix.ReplaceAllString(name, "")
+}
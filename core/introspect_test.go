@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestHandlerRegistry(t *testing.T) {
+	bot := newTestBot(t)
+	ProvideTo[FeatureGate](bot.Container, FeatureGateKey, featureGateFunc(func(_ *Identifier, _ string) (bool, error) {
+		return true, nil
+	}))
+
+	bot.Handlers.setModule("greetings")
+	command := pingCommand(MidwareOwnerOnly())
+	command.Identifier = NewIdentifier("test", "commands/greet")
+	command.Feature = testFeature
+	command.Timeout = time.Minute
+	if err := RegisterCommands(bot, command); err != nil {
+		t.Fatal(err)
+	}
+
+	bot.Handlers.setModule("audit")
+	err := Subscribe(bot, &EventHandler[memberKickedEvent]{
+		Identifier: NewIdentifier("test", "events/audit"),
+		Handler: func(_ context.Context, _ *discordgo.Session, _ *memberKickedEvent) error {
+			return nil
+		},
+		Timeout: -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.Handlers.setModule("")
+
+	handlers := bot.Handlers.Handlers()
+	if len(handlers) != 2 {
+		t.Fatalf("got %d handlers, want 2", len(handlers))
+	}
+
+	// Sorted by module
+	subscriber, ping := handlers[0], handlers[1]
+	if subscriber.Module != "audit" || subscriber.Kind != HandlerSubscriber || subscriber.Timeout != "none" || subscriber.Event != "core.memberKickedEvent" {
+		t.Errorf("got subscriber %+v", subscriber)
+	}
+
+	if ping.Module != "greetings" || ping.Kind != HandlerCommand || ping.Identifier != "test:commands/greet" || ping.Feature != testFeature.String() || ping.Timeout != "1m0s" {
+		t.Errorf("got command %+v", ping)
+	}
+	if !slices.Equal(ping.Routes, []string{"/ping"}) {
+		t.Errorf("got routes %v, want [/ping]", ping.Routes)
+	}
+
+	want := []string{"core.MidwareOwnerOnly", "core.MidwareFeatureGate", "core.MidwareTimeout", "core.MidwareAutoDefer"}
+	if !slices.Equal(ping.Middlewares, want) {
+		t.Errorf("got middlewares %v, want %v", ping.Middlewares, want)
+	}
+
+	bot.Handlers.removeModule("greetings")
+	if handlers := bot.Handlers.Handlers(); len(handlers) != 1 || handlers[0].Module != "audit" {
+		t.Errorf("got handlers %+v after removing the module, want the subscriber only", handlers)
+	}
+}
+
+func TestMiddlewareName(t *testing.T) {
+	tests := []struct {
+		middleware any
+		want       string
+	}{
+		{MidwareOwnerOnly(), "core.MidwareOwnerOnly"},
+		{MidwareTimeout[Job](testJobType), "core.MidwareTimeout"},
+		{MidwareRequireRoles("mods"), "core.MidwareRequireRoles"},
+	}
+
+	for _, test := range tests {
+		if got := middlewareName(test.middleware); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
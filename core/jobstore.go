@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobStore persists the jobs of the scheduler so they survive restarts.
+type JobStore interface {
+	// SaveJob inserts the job and returns its ID. Saving a job with the key
+	// of an existing one replaces it instead, recurring jobs keep their next
+	// run if their cron expression didn't change.
+	SaveJob(ctx context.Context, job *Job) (string, error)
+	// ClaimJobs locks up to limit jobs of the given types due by now until the
+	// lease expires, so other instances don't run them as well.
+	ClaimJobs(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]*Job, error)
+	// RescheduleJob unlocks the job with its new run time, attempts and error.
+	RescheduleJob(ctx context.Context, job *Job) error
+	// FailJob unlocks the job and stops it from running again.
+	FailJob(ctx context.Context, job *Job) error
+	DeleteJob(ctx context.Context, id string) error
+	DeleteJobByKey(ctx context.Context, key string) error
+}
+
+type PostgresJobStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresJobStore(pool *pgxpool.Pool) JobStore {
+	return &PostgresJobStore{pool: pool}
+}
+
+func (r *PostgresJobStore) SaveJob(ctx context.Context, job *Job) (string, error) {
+	var id string
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO core_jobs (id, type, job_key, payload, cron, run_at, max_attempts)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), $6, $7)
+		ON CONFLICT (job_key) DO UPDATE SET
+			type = EXCLUDED.type,
+			payload = EXCLUDED.payload,
+			cron = EXCLUDED.cron,
+			run_at = CASE
+				WHEN EXCLUDED.cron IS NOT NULL AND core_jobs.cron = EXCLUDED.cron AND core_jobs.failed_at IS NULL THEN core_jobs.run_at
+				ELSE EXCLUDED.run_at
+			END,
+			max_attempts = EXCLUDED.max_attempts,
+			attempts = 0,
+			last_error = NULL,
+			failed_at = NULL,
+			updated_at = current_timestamp
+		RETURNING id
+	`, job.Id, job.Type.String(), job.Key, job.Payload, job.Cron, job.RunAt.UTC(), job.MaxAttempts).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (r *PostgresJobStore) ClaimJobs(ctx context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]*Job, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE core_jobs
+		SET locked_until = $3, updated_at = current_timestamp
+		WHERE id IN (
+			SELECT id
+			FROM core_jobs
+			WHERE type = ANY($1) AND run_at <= $2 AND failed_at IS NULL AND (locked_until IS NULL OR locked_until < $2)
+			ORDER BY run_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, type, COALESCE(job_key, ''), payload, COALESCE(cron, ''), run_at, attempts, max_attempts, COALESCE(last_error, '')
+	`, types, now.UTC(), now.Add(lease).UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		var job Job
+		var jobType string
+		if err := rows.Scan(&job.Id, &jobType, &job.Key, &job.Payload, &job.Cron, &job.RunAt, &job.Attempts, &job.MaxAttempts, &job.LastError); err != nil {
+			return nil, err
+		}
+
+		if job.Type, err = ParseIdentifier(jobType); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *PostgresJobStore) RescheduleJob(ctx context.Context, job *Job) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE core_jobs
+		SET run_at = $2, attempts = $3, last_error = NULLIF($4, ''), locked_until = NULL, updated_at = current_timestamp
+		WHERE id = $1
+	`, job.Id, job.RunAt.UTC(), job.Attempts, job.LastError)
+	return err
+}
+
+func (r *PostgresJobStore) FailJob(ctx context.Context, job *Job) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE core_jobs
+		SET failed_at = current_timestamp, attempts = $2, last_error = NULLIF($3, ''), locked_until = NULL, updated_at = current_timestamp
+		WHERE id = $1
+	`, job.Id, job.Attempts, job.LastError)
+	return err
+}
+
+func (r *PostgresJobStore) DeleteJob(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM core_jobs
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+func (r *PostgresJobStore) DeleteJobByKey(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM core_jobs
+		WHERE job_key = $1
+	`, key)
+	return err
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrDrainTimeout = errors.New("timed out waiting for in-flight handlers")
+
+// lifecycle tracks the handlers run through HandleEvent so the bot can stop
+// taking new events and wait for the running ones before tearing down the
+// services they use.
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.RWMutex
+	closing  bool
+	inflight sync.WaitGroup
+
+	shutdown chan int
+}
+
+var botLifecycle = newLifecycle()
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{
+		ctx:      ctx,
+		cancel:   cancel,
+		shutdown: make(chan int, 1),
+	}
+}
+
+func (l *lifecycle) acquire() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closing {
+		return false
+	}
+
+	l.inflight.Add(1)
+	return true
+}
+
+func (l *lifecycle) release() {
+	l.inflight.Done()
+}
+
+// RootContext returns the context handed to every event handler, it gets
+// cancelled once the bot has drained its handlers on shutdown.
+func RootContext() context.Context {
+	return botLifecycle.ctx
+}
+
+// BeginShutdown stops HandleEvent from running new handlers, events received
+// afterwards are dropped.
+func BeginShutdown() {
+	botLifecycle.mu.Lock()
+	defer botLifecycle.mu.Unlock()
+
+	botLifecycle.closing = true
+}
+
+func IsShuttingDown() bool {
+	botLifecycle.mu.RLock()
+	defer botLifecycle.mu.RUnlock()
+
+	return botLifecycle.closing
+}
+
+// Drain waits for the in-flight handlers to finish or for the context to be
+// done, whichever happens first. Call BeginShutdown first so no new handlers
+// are started while waiting.
+func Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		botLifecycle.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ErrDrainTimeout
+	}
+}
+
+// CancelRootContext cancels the context of the handlers still running.
+func CancelRootContext() {
+	botLifecycle.cancel()
+}
+
+// RequestShutdown asks the main loop to shut the bot down and exit with the
+// given code. Only the first request is kept.
+func RequestShutdown(exitCode int) {
+	select {
+	case botLifecycle.shutdown <- exitCode:
+	default:
+	}
+}
+
+// ShutdownRequested receives the exit code passed to RequestShutdown.
+func ShutdownRequested() <-chan int {
+	return botLifecycle.shutdown
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// useLifecycle gives the test a lifecycle of its own, the global one is put
+// back once it's done. Handlers still running on either of them are waited
+// for, they release the lifecycle they were started with.
+func useLifecycle(t *testing.T) {
+	previous := botLifecycle
+	previous.inflight.Wait()
+
+	botLifecycle = newLifecycle()
+	t.Cleanup(func() {
+		botLifecycle.inflight.Wait()
+		botLifecycle = previous
+	})
+}
+
+func TestDrainWaitsForHandlers(t *testing.T) {
+	useLifecycle(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := HandleEvent(func(_ context.Context, _ *discordgo.Session, _ *discordgo.Ready) error {
+		close(started)
+		<-release
+		return nil
+	}).(func(*discordgo.Session, *discordgo.Ready))
+
+	go handler(nil, &discordgo.Ready{})
+	<-started
+
+	BeginShutdown()
+	if !IsShuttingDown() {
+		t.Fatal("not shutting down after BeginShutdown")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := Drain(ctx); !errors.Is(err, ErrDrainTimeout) {
+		t.Fatalf("draining a running handler: got %v, want %v", err, ErrDrainTimeout)
+	}
+
+	close(release)
+	if err := Drain(context.Background()); err != nil {
+		t.Fatalf("draining a finished handler: got %v", err)
+	}
+}
+
+func TestHandleEventDropsEventsWhileShuttingDown(t *testing.T) {
+	useLifecycle(t)
+	BeginShutdown()
+
+	ran := false
+	handler := HandleEvent(func(_ context.Context, _ *discordgo.Session, _ *discordgo.Ready) error {
+		ran = true
+		return nil
+	}).(func(*discordgo.Session, *discordgo.Ready))
+
+	handler(nil, &discordgo.Ready{})
+	if ran {
+		t.Error("the handler ran after BeginShutdown")
+	}
+}
+
+func TestCancelRootContext(t *testing.T) {
+	useLifecycle(t)
+
+	ctx := RootContext()
+	CancelRootContext()
+
+	select {
+	case <-ctx.Done():
+	default:
+		t.Error("the root context wasn't cancelled")
+	}
+}
+
+func TestRequestShutdownKeepsFirstCode(t *testing.T) {
+	useLifecycle(t)
+
+	RequestShutdown(2)
+	RequestShutdown(3)
+
+	if code := <-ShutdownRequested(); code != 2 {
+		t.Errorf("got exit code %d, want 2", code)
+	}
+}
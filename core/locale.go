@@ -0,0 +1,238 @@
+package core
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var ErrInvalidCatalog = errors.New("invalid message catalog")
+
+// DefaultLocale is the locale used when no translation matches, every catalog
+// must provide it.
+const DefaultLocale = discordgo.EnglishUS
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// coreMessages are the messages core answers interactions with.
+var coreMessages = MustLoadCatalog(locales, "locales/*.json")
+
+var placeholderPattern = regexp.MustCompile(`\{([a-zA-Z0-9_-]+)\}`)
+
+// Vars are the values of the placeholders of a message. The "count" value
+// also picks the plural form of the message.
+type Vars map[string]any
+
+// message is either a plain string or a set of plural forms in the catalog
+// files, e.g. {"one": "{count} user", "other": "{count} users"}.
+type message struct {
+	Zero  string `json:"zero"`
+	One   string `json:"one"`
+	Other string `json:"other"`
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.Other = text
+		return nil
+	}
+
+	type forms message
+	if err := json.Unmarshal(data, (*forms)(m)); err != nil {
+		return err
+	}
+
+	if m.Other == "" {
+		return fmt.Errorf("plural messages need an \"other\" form")
+	}
+
+	return nil
+}
+
+func (m *message) form(vars Vars) string {
+	count, ok := pluralCount(vars)
+	switch {
+	case !ok:
+		return m.Other
+	case count == 0 && m.Zero != "":
+		return m.Zero
+	case count == 1 && m.One != "":
+		return m.One
+	default:
+		return m.Other
+	}
+}
+
+func pluralCount(vars Vars) (int64, bool) {
+	switch count := vars["count"].(type) {
+	case int:
+		return int64(count), true
+	case int32:
+		return int64(count), true
+	case int64:
+		return count, true
+	case uint:
+		return int64(count), true
+	default:
+		return 0, false
+	}
+}
+
+// Catalog holds the messages of a module for every locale it supports.
+type Catalog struct {
+	messages map[discordgo.Locale]map[string]*message
+	// locales are sorted, so the fallback to a locale of the same language
+	// always picks the same one
+	locales []discordgo.Locale
+}
+
+// LoadCatalog reads the catalog files matching the pattern, every file being
+// a JSON object of messages named after its locale (e.g. "locales/es-ES.json").
+func LoadCatalog(fsys fs.FS, pattern string) (*Catalog, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &Catalog{
+		messages: make(map[discordgo.Locale]map[string]*message),
+	}
+
+	for _, file := range files {
+		locale := discordgo.Locale(strings.TrimSuffix(path.Base(file), path.Ext(file)))
+		if _, ok := discordgo.Locales[locale]; !ok {
+			return nil, fmt.Errorf("%w: %s isn't named after a locale", ErrInvalidCatalog, file)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		messages := make(map[string]*message)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCatalog, file, err)
+		}
+
+		catalog.messages[locale] = messages
+		catalog.locales = append(catalog.locales, locale)
+	}
+	slices.Sort(catalog.locales)
+
+	if _, ok := catalog.messages[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("%w: missing the %s messages", ErrInvalidCatalog, DefaultLocale)
+	}
+
+	return catalog, nil
+}
+
+func MustLoadCatalog(fsys fs.FS, pattern string) *Catalog {
+	catalog, err := LoadCatalog(fsys, pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	return catalog
+}
+
+// lookup finds the message in the locale, then in any locale of the same
+// language and finally in the default locale.
+func (c *Catalog) lookup(locale discordgo.Locale, key string) (*message, bool) {
+	if msg, ok := c.messages[locale][key]; ok {
+		return msg, true
+	}
+
+	language, _, _ := strings.Cut(string(locale), "-")
+	for _, other := range c.locales {
+		if otherLanguage, _, _ := strings.Cut(string(other), "-"); otherLanguage == language {
+			if msg, ok := c.messages[other][key]; ok {
+				return msg, true
+			}
+		}
+	}
+
+	msg, ok := c.messages[DefaultLocale][key]
+	return msg, ok
+}
+
+// Translate returns the message for the key in the given locale with its
+// placeholders replaced. Missing messages are returned as their key so they
+// stand out.
+func (c *Catalog) Translate(locale discordgo.Locale, key string, vars Vars) string {
+	msg, ok := c.lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(msg.form(vars), func(placeholder string) string {
+		value, ok := vars[placeholder[1:len(placeholder)-1]]
+		if !ok {
+			return placeholder
+		}
+
+		return fmt.Sprint(value)
+	})
+}
+
+// Translator translates messages to a fixed locale.
+type Translator func(key string, vars ...Vars) string
+
+func (c *Catalog) For(locale discordgo.Locale) Translator {
+	return func(key string, vars ...Vars) string {
+		merged := make(Vars)
+		for _, v := range vars {
+			for name, value := range v {
+				merged[name] = value
+			}
+		}
+
+		return c.Translate(locale, key, merged)
+	}
+}
+
+// Localize returns the message for the key in the default locale along with
+// its translations to the other locales, as used by command definitions.
+func (c *Catalog) Localize(key string) (string, map[discordgo.Locale]string) {
+	localizations := make(map[discordgo.Locale]string)
+	for locale, messages := range c.messages {
+		if msg, ok := messages[key]; ok && locale != DefaultLocale {
+			localizations[locale] = msg.Other
+		}
+	}
+
+	return c.Translate(DefaultLocale, key, nil), localizations
+}
+
+// InteractionLocale returns the locale of the user who triggered the
+// interaction, falling back to the guild's preferred locale.
+func InteractionLocale(i *discordgo.Interaction) discordgo.Locale {
+	if i.Locale != "" {
+		return i.Locale
+	}
+
+	if i.GuildLocale != nil && *i.GuildLocale != "" {
+		return *i.GuildLocale
+	}
+
+	return DefaultLocale
+}
+
+// GuildLocale returns the preferred locale of the guild, for messages that
+// aren't answering an interaction.
+func GuildLocale(s *discordgo.Session, guildId string) discordgo.Locale {
+	if guild, err := s.State.Guild(guildId); err == nil && guild.PreferredLocale != "" {
+		return discordgo.Locale(guild.PreferredLocale)
+	}
+
+	return DefaultLocale
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	t.Helper()
+
+	catalog, err := LoadCatalog(fstest.MapFS{
+		"locales/en-US.json": {Data: []byte(`{
+			"greeting": "Hello {name}!",
+			"users": {"zero": "No users", "one": "{count} user", "other": "{count} users"},
+			"only-english": "English"
+		}`)},
+		"locales/es-ES.json":  {Data: []byte(`{"greeting": "¡Hola {name}!", "region": "España"}`)},
+		"locales/es-419.json": {Data: []byte(`{"region": "Latinoamérica"}`)},
+	}, "locales/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return catalog
+}
+
+func TestCatalogTranslate(t *testing.T) {
+	catalog := testCatalog(t)
+
+	tests := []struct {
+		locale discordgo.Locale
+		key    string
+		vars   Vars
+		want   string
+	}{
+		{discordgo.EnglishUS, "greeting", Vars{"name": "fox"}, "Hello fox!"},
+		{discordgo.SpanishES, "greeting", Vars{"name": "fox"}, "¡Hola fox!"},
+		{discordgo.SpanishES, "greeting", nil, "¡Hola {name}!"},
+		{discordgo.SpanishES, "only-english", nil, "English"},
+		{discordgo.German, "greeting", Vars{"name": "fox"}, "Hello fox!"},
+		{discordgo.EnglishUS, "users", Vars{"count": 0}, "No users"},
+		{discordgo.EnglishUS, "users", Vars{"count": 1}, "1 user"},
+		{discordgo.EnglishUS, "users", Vars{"count": int64(3)}, "3 users"},
+		{discordgo.EnglishUS, "missing", nil, "missing"},
+	}
+
+	for _, test := range tests {
+		if got := catalog.Translate(test.locale, test.key, test.vars); got != test.want {
+			t.Errorf("%s %s: got %q, want %q", test.locale, test.key, got, test.want)
+		}
+	}
+}
+
+func TestCatalogLanguageFallback(t *testing.T) {
+	catalog := testCatalog(t)
+
+	// Both Spanish catalogs have it, the first one in order always wins
+	for i := 0; i < 20; i++ {
+		if got := catalog.Translate("es-MX", "region", nil); got != "Latinoamérica" {
+			t.Fatalf("got %q, want the es-419 message", got)
+		}
+	}
+}
+
+func TestCatalogLocalize(t *testing.T) {
+	catalog := testCatalog(t)
+
+	text, localizations := catalog.Localize("greeting")
+	if text != "Hello {name}!" {
+		t.Errorf("got %q, want the en-US message", text)
+	}
+	if len(localizations) != 1 || localizations[discordgo.SpanishES] != "¡Hola {name}!" {
+		t.Errorf("got localizations %v, want the es-ES one", localizations)
+	}
+}
+
+func TestLoadCatalogErrors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"unknown locale": {
+			"locales/en-US.json":   {Data: []byte(`{}`)},
+			"locales/klingon.json": {Data: []byte(`{}`)},
+		},
+		"missing default": {
+			"locales/es-ES.json": {Data: []byte(`{}`)},
+		},
+		"invalid json": {
+			"locales/en-US.json": {Data: []byte(`{"greeting": 1}`)},
+		},
+		"plural without other": {
+			"locales/en-US.json": {Data: []byte(`{"users": {"one": "{count} user"}}`)},
+		},
+	}
+
+	for name, fsys := range tests {
+		if _, err := LoadCatalog(fsys, "locales/*.json"); !errors.Is(err, ErrInvalidCatalog) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrInvalidCatalog)
+		}
+	}
+}
+
+func TestInteractionLocale(t *testing.T) {
+	guildLocale := discordgo.SpanishES
+
+	tests := []struct {
+		interaction *discordgo.Interaction
+		want        discordgo.Locale
+	}{
+		{&discordgo.Interaction{Locale: discordgo.German, GuildLocale: &guildLocale}, discordgo.German},
+		{&discordgo.Interaction{GuildLocale: &guildLocale}, discordgo.SpanishES},
+		{&discordgo.Interaction{}, DefaultLocale},
+	}
+
+	for _, test := range tests {
+		if got := InteractionLocale(test.interaction); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestCoreMessagesTranslated(t *testing.T) {
+	for locale, messages := range coreMessages.messages {
+		for key := range coreMessages.messages[DefaultLocale] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%s is missing %s", locale, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	ErrInvalidModal       = errors.New("invalid modal")
+	ErrModalFieldNotFound = errors.New("modal field not found")
+)
+
+// Limits enforced by Discord on modals.
+const (
+	MaxModalTitleLength     = 45
+	MaxModalTextInputs      = 5
+	MaxTextInputIdLength    = 100
+	MaxTextInputLabelLength = 45
+	MaxTextInputPlaceholder = 100
+	MaxTextInputValueLength = 4000
+)
+
+// Modal binds the handler of a modal submission to its middlewares and
+// dependencies, modals are routed by custom ID like components.
+type Modal struct {
+	Identifier *Identifier
+	Handler    EventFunc[discordgo.InteractionCreate]
+
+	// Middlewares wrap the handler, the first one being the outermost.
+	Middlewares []MiddlewareFunc[discordgo.InteractionCreate]
+
+	// Requires lists the dependencies the handler resolves with Use.
+	Requires []Dependency
+
+	// Feature guards the modal, its handler only runs in the guilds the
+	// feature is enabled in.
+	Feature *Identifier
+
+	// Timeout bounds how long the handler can run, DefaultHandlerTimeout is
+	// used when zero.
+	Timeout time.Duration
+
+	// DeferFlags are the flags of the deferred response sent when the handler
+	// doesn't answer in time.
+	DeferFlags discordgo.MessageFlags
+}
+
+// RegisterModals validates the modals and routes their handlers, wrapped with
+// their middlewares and the timeout and auto defer ones. Nothing is
+// registered if any of them is invalid.
+func RegisterModals(bot *Bot, modals ...*Modal) error {
+	errs := make([]error, 0)
+	seen := make(map[string]bool, len(modals))
+	for _, modal := range modals {
+		if modal.Identifier == nil || modal.Handler == nil {
+			errs = append(errs, fmt.Errorf("%w: modals need an identifier and a handler", ErrInvalidCommandDeclaration))
+			continue
+		}
+
+		if err := bot.Container.Validate(requirements(modal.Requires, modal.Feature)...); err != nil {
+			errs = append(errs, fmt.Errorf("modal %s: %w", modal.Identifier, err))
+		}
+
+		customId := modal.Identifier.String()
+		if seen[customId] || bot.Router.HasModal(customId) {
+			errs = append(errs, fmt.Errorf("%w: modal %s", ErrDuplicateRoute, customId))
+		}
+		seen[customId] = true
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, modal := range modals {
+		if modal.Timeout != 0 {
+			SetHandlerTimeout(modal.Identifier, modal.Timeout)
+		}
+
+		middlewares := interactionMiddlewares(modal.Identifier, modal.Feature, modal.Middlewares, modal.DeferFlags)
+
+		customId := modal.Identifier.String()
+		if err := bot.Router.HandleModal(customId, bindBot(bot, ApplyMiddlewares(modal.Handler, middlewares...))); err != nil {
+			return err
+		}
+		bot.onRollback(func() {
+			bot.Router.unregister(bot.Router.modals, customId)
+		})
+
+		info := handlerInfo(HandlerModal, modal.Identifier, modal.Feature, modal.Requires, middlewares)
+		info.Routes = []string{modal.Identifier.String()}
+		bot.Handlers.add(info)
+	}
+
+	return nil
+}
+
+type TextInputSupplier func(*TextInputBuilder)
+
+type ModalBuilder struct {
+	discordgo.InteractionResponseData
+	inputs      []*TextInputBuilder
+	customIdErr error
+}
+
+func NewModalBuilder() *ModalBuilder {
+	return &ModalBuilder{}
+}
+
+// SetCustomId routes the submission of the modal to the Modal with the given
+// identifier.
+func (m *ModalBuilder) SetCustomId(identifier *Identifier, args ...string) *ModalBuilder {
+	m.CustomID, m.customIdErr = CustomId(identifier, args...)
+	return m
+}
+
+func (m *ModalBuilder) SetTitle(title string) *ModalBuilder {
+	m.Title = title
+	return m
+}
+
+func (m *ModalBuilder) AddTextInput(supplier TextInputSupplier) *ModalBuilder {
+	input := NewTextInputBuilder()
+	supplier(input)
+
+	m.inputs = append(m.inputs, input)
+	return m
+}
+
+func (m *ModalBuilder) Build() (*discordgo.InteractionResponseData, error) {
+	errs := []error{m.customIdErr}
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidModal, m.CustomID, fmt.Sprintf(format, args...)))
+	}
+
+	if m.CustomID == "" {
+		fail("missing custom id")
+	}
+
+	if m.Title == "" || utf8.RuneCountInString(m.Title) > MaxModalTitleLength {
+		fail("title must be between 1 and %d characters", MaxModalTitleLength)
+	}
+
+	if len(m.inputs) == 0 || len(m.inputs) > MaxModalTextInputs {
+		fail("modals need between 1 and %d text inputs", MaxModalTextInputs)
+	}
+
+	data := m.InteractionResponseData
+	data.Components = make([]discordgo.MessageComponent, 0, len(m.inputs))
+
+	ids := make(map[string]bool, len(m.inputs))
+	for _, input := range m.inputs {
+		if ids[input.CustomID] {
+			fail("duplicated text input %q", input.CustomID)
+		}
+		ids[input.CustomID] = true
+
+		if err := input.validate(); err != nil {
+			fail("%s", err)
+		}
+
+		data.Components = append(data.Components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{input.TextInput},
+		})
+	}
+
+	return &data, errors.Join(errs...)
+}
+
+func (m *ModalBuilder) MustBuild() *discordgo.InteractionResponseData {
+	data, err := m.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
+
+type TextInputBuilder struct {
+	discordgo.TextInput
+}
+
+func NewTextInputBuilder() *TextInputBuilder {
+	return &TextInputBuilder{
+		TextInput: discordgo.TextInput{
+			Style: discordgo.TextInputShort,
+		},
+	}
+}
+
+// SetCustomId sets the name the value is submitted under, it's matched
+// against the `modal` tags by BindModal.
+func (t *TextInputBuilder) SetCustomId(customId string) *TextInputBuilder {
+	t.CustomID = customId
+	return t
+}
+
+func (t *TextInputBuilder) SetLabel(label string) *TextInputBuilder {
+	t.Label = label
+	return t
+}
+
+func (t *TextInputBuilder) SetStyle(style discordgo.TextInputStyle) *TextInputBuilder {
+	t.Style = style
+	return t
+}
+
+func (t *TextInputBuilder) SetPlaceholder(placeholder string) *TextInputBuilder {
+	t.Placeholder = placeholder
+	return t
+}
+
+// SetValue pre-fills the text input.
+func (t *TextInputBuilder) SetValue(value string) *TextInputBuilder {
+	t.Value = value
+	return t
+}
+
+func (t *TextInputBuilder) SetRequired(required bool) *TextInputBuilder {
+	t.Required = required
+	return t
+}
+
+func (t *TextInputBuilder) SetMinLength(min int) *TextInputBuilder {
+	t.MinLength = min
+	return t
+}
+
+func (t *TextInputBuilder) SetMaxLength(max int) *TextInputBuilder {
+	t.MaxLength = max
+	return t
+}
+
+func (t *TextInputBuilder) validate() error {
+	errs := make([]error, 0)
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("text input %q: %s", t.CustomID, fmt.Sprintf(format, args...)))
+	}
+
+	if t.CustomID == "" || len(t.CustomID) > MaxTextInputIdLength {
+		fail("custom id must be between 1 and %d characters", MaxTextInputIdLength)
+	}
+
+	if t.Label == "" || utf8.RuneCountInString(t.Label) > MaxTextInputLabelLength {
+		fail("label must be between 1 and %d characters", MaxTextInputLabelLength)
+	}
+
+	if utf8.RuneCountInString(t.Placeholder) > MaxTextInputPlaceholder {
+		fail("placeholder is longer than %d characters", MaxTextInputPlaceholder)
+	}
+
+	if t.MinLength < 0 || t.MinLength > MaxTextInputValueLength {
+		fail("min length must be between 0 and %d", MaxTextInputValueLength)
+	}
+
+	if t.MaxLength < 0 || t.MaxLength > MaxTextInputValueLength {
+		fail("max length can't exceed %d", MaxTextInputValueLength)
+	}
+
+	if t.MaxLength != 0 && t.MinLength > t.MaxLength {
+		fail("min length is greater than max length")
+	}
+
+	if t.Value != "" && t.MaxLength != 0 && utf8.RuneCountInString(t.Value) > t.MaxLength {
+		fail("value is longer than max length")
+	}
+
+	return errors.Join(errs...)
+}
+
+// OpenModal answers the interaction with the modal. Modals have to be the
+// first response to an interaction, so they can't be opened from deferred
+// handlers nor from modal submissions, those return ErrAlreadyResponded.
+func OpenModal(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction, modal *ModalBuilder) error {
+	data, err := modal.Build()
+	if err != nil {
+		return err
+	}
+
+	return Respond(ctx, s, i).Modal(data)
+}
+
+// GetModalValues returns the submitted values keyed by the custom ID of their
+// text input.
+func GetModalValues(data discordgo.ModalSubmitInteractionData) map[string]string {
+	values := make(map[string]string)
+
+	var walk func(components []discordgo.MessageComponent)
+	walk = func(components []discordgo.MessageComponent) {
+		for _, component := range components {
+			switch c := component.(type) {
+			case *discordgo.ActionsRow:
+				walk(c.Components)
+			case discordgo.ActionsRow:
+				walk(c.Components)
+			case *discordgo.TextInput:
+				values[c.CustomID] = c.Value
+			case discordgo.TextInput:
+				values[c.CustomID] = c.Value
+			}
+		}
+	}
+	walk(data.Components)
+
+	return values
+}
+
+func GetModalValue(data discordgo.ModalSubmitInteractionData, customId string) (string, error) {
+	value, ok := GetModalValues(data)[customId]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrModalFieldNotFound, customId)
+	}
+
+	return value, nil
+}
+
+// BindModal decodes the submitted values into the fields of v tagged with
+// `modal:"name"`, accepting the same flags as BindOptions. Blank values count
+// as missing, strings are bound as submitted and numbers and booleans are
+// parsed from the trimmed text.
+func BindModal(data discordgo.ModalSubmitInteractionData, v any) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+
+	values := GetModalValues(data)
+	target = target.Elem()
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+
+		tag, ok := field.Tag.Lookup("modal")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		parsed, err := parseOptionTag(tag)
+		if err != nil {
+			return err
+		}
+
+		value := values[parsed.name]
+		if strings.TrimSpace(value) == "" {
+			if parsed.required {
+				return fmt.Errorf("%w: %s", ErrModalFieldNotFound, parsed.name)
+			}
+
+			if parsed.hasDefault {
+				if err := bindDefault(target.Field(i), parsed); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err := bindModalValue(target.Field(i), parsed.name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func bindModalValue(field reflect.Value, name, value string) error {
+	if field.Kind() == reflect.String {
+		field.SetString(value)
+		return nil
+	}
+
+	var err error
+	value = strings.TrimSpace(value)
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var parsed int64
+		if parsed, err = strconv.ParseInt(value, 10, 64); err == nil {
+			field.SetInt(parsed)
+		}
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+		if parsed, err = strconv.ParseFloat(value, 64); err == nil {
+			field.SetFloat(parsed)
+		}
+	case reflect.Bool:
+		var parsed bool
+		if parsed, err = strconv.ParseBool(value); err == nil {
+			field.SetBool(parsed)
+		}
+	default:
+		return fmt.Errorf("%w: modal field %q can't be bound to a field of type %s", ErrOptionUnexpectedType, name, field.Type())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: modal field %q: %s", ErrOptionUnexpectedType, name, err)
+	}
+
+	return nil
+}
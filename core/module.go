@@ -0,0 +1,375 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrDuplicateModule   = errors.New("module already registered")
+	ErrUnknownModule     = errors.New("unknown module")
+	ErrMissingDependency = errors.New("missing module dependency")
+	ErrDependencyCycle   = errors.New("module dependency cycle")
+)
+
+// Bot holds what modules need to hook themselves into the bot. Session is the
+// session of the first shard, use it for REST only operations.
+type Bot struct {
+	Session   *discordgo.Session
+	Shards    *ShardManager
+	Router    *InteractionRouter
+	Commands  *CommandStack
+	Container *Container
+	Events    *EventBus
+	Scheduler *Scheduler
+	Handlers  *HandlerRegistry
+
+	// rollback undoes what the module being loaded registered so far, nil
+	// when no module is loading.
+	rollback []func()
+}
+
+// withBot attaches the container and event bus of the bot to the context, so
+// handlers resolve and publish through them.
+func withBot(ctx context.Context, bot *Bot) context.Context {
+	ctx = WithContainer(ctx, bot.Container)
+	if bot.Events != nil {
+		ctx = WithEventBus(ctx, bot.Events)
+	}
+
+	return ctx
+}
+
+// bindBot runs the handler with the container and event bus of the bot
+// attached to its context.
+func bindBot[T any](bot *Bot, fn EventFunc[T]) EventFunc[T] {
+	return func(ctx context.Context, s *discordgo.Session, e *T) error {
+		return fn(withBot(ctx, bot), s, e)
+	}
+}
+
+// onRollback adds fn to what is undone if the module being loaded fails.
+func (b *Bot) onRollback(fn func()) {
+	if b.rollback != nil {
+		b.rollback = append(b.rollback, fn)
+	}
+}
+
+// AddHandler adds the event handler to every shard of the bot, the returned
+// function removes it.
+func (b *Bot) AddHandler(handler any) func() {
+	var remove func()
+	if b.Shards == nil {
+		remove = b.Session.AddHandler(handler)
+	} else {
+		remove = b.Shards.AddHandler(handler)
+	}
+
+	b.onRollback(remove)
+	return remove
+}
+
+// SessionFor returns the session of the shard holding the state of the guild,
+// falling back to Session when it's run by another process.
+func (b *Bot) SessionFor(guildId string) *discordgo.Session {
+	if b.Shards != nil {
+		if session := b.Shards.ForGuild(guildId); session != nil {
+			return session
+		}
+	}
+
+	return b.Session
+}
+
+// Module is a self contained feature of the bot. Modules are initialized in
+// dependency order, their commands, components, modals and jobs registered
+// right after Init, and shut down in reverse order.
+type Module interface {
+	// Name is the name used to enable the module and to depend on it.
+	Name() string
+	// Dependencies lists the names of the modules that must be loaded first.
+	Dependencies() []string
+	// Init registers the module's event handlers and prepares its services.
+	Init(bot *Bot) error
+	// Ready is called every time a shard gets the ready event, with the
+	// session of that shard. e.Guilds only lists the guilds of the shard.
+	Ready(ctx context.Context, s *discordgo.Session, e *discordgo.Ready) error
+	// Commands returns the commands of the module, called after Init.
+	Commands() []*Command
+	// Components returns the message components of the module, called after
+	// Init.
+	Components() []*Component
+	// Modals returns the modals of the module, called after Init.
+	Modals() []*Modal
+	// Jobs returns the job handlers of the module, called after Init.
+	Jobs() []*JobHandler
+	// Shutdown releases the resources held by the module.
+	Shutdown(ctx context.Context) error
+}
+
+// BaseModule implements every optional hook of Module as a no-op, embed it to
+// only implement the hooks a module needs.
+type BaseModule struct{}
+
+func (BaseModule) Dependencies() []string {
+	return nil
+}
+
+func (BaseModule) Ready(_ context.Context, _ *discordgo.Session, _ *discordgo.Ready) error {
+	return nil
+}
+
+func (BaseModule) Commands() []*Command {
+	return nil
+}
+
+func (BaseModule) Components() []*Component {
+	return nil
+}
+
+func (BaseModule) Modals() []*Modal {
+	return nil
+}
+
+func (BaseModule) Jobs() []*JobHandler {
+	return nil
+}
+
+func (BaseModule) Shutdown(_ context.Context) error {
+	return nil
+}
+
+type ModuleStatus string
+
+const (
+	ModuleLoaded   ModuleStatus = "loaded"
+	ModuleFailed   ModuleStatus = "failed"
+	ModuleDisabled ModuleStatus = "disabled"
+)
+
+type ModuleReport struct {
+	Name   string
+	Status ModuleStatus
+	Err    error
+}
+
+type ModuleRegistry struct {
+	bot     *Bot
+	modules map[string]Module
+	names   []string
+	loaded  []Module
+	reports []*ModuleReport
+}
+
+func NewModuleRegistry() *ModuleRegistry {
+	return &ModuleRegistry{
+		modules: make(map[string]Module),
+	}
+}
+
+func (r *ModuleRegistry) Register(modules ...Module) error {
+	for _, module := range modules {
+		if _, ok := r.modules[module.Name()]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateModule, module.Name())
+		}
+
+		r.modules[module.Name()] = module
+		r.names = append(r.names, module.Name())
+	}
+
+	return nil
+}
+
+// Load initializes the enabled modules in dependency order. A module fails to
+// load if its Init or handler registration fails, or if any of its
+// dependencies isn't enabled or failed itself; the other modules keep
+// loading. The handlers, routes and commands a failed module registered are
+// removed. Enabling a module that isn't registered is an error.
+func (r *ModuleRegistry) Load(bot *Bot, enabled []string) ([]*ModuleReport, error) {
+	for _, name := range enabled {
+		if _, ok := r.modules[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownModule, name)
+		}
+	}
+
+	order, err := r.resolveOrder(enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	r.bot = bot
+
+	statuses := make(map[string]*ModuleReport, len(r.modules))
+	for _, name := range r.names {
+		if !slices.Contains(enabled, name) {
+			statuses[name] = &ModuleReport{Name: name, Status: ModuleDisabled}
+		}
+	}
+
+	for _, name := range order {
+		module := r.modules[name]
+		report := &ModuleReport{Name: name, Status: ModuleLoaded}
+		statuses[name] = report
+
+		for _, dependency := range module.Dependencies() {
+			if status, ok := statuses[dependency]; !ok || status.Status != ModuleLoaded {
+				report.Status = ModuleFailed
+				report.Err = fmt.Errorf("%w: %s requires %s", ErrMissingDependency, name, dependency)
+				break
+			}
+		}
+
+		if report.Status == ModuleLoaded {
+			if err := r.initModule(bot, module); err != nil {
+				report.Status = ModuleFailed
+				report.Err = err
+			}
+		}
+
+		if report.Status == ModuleFailed {
+			log.Error().Err(report.Err).Msgf("[ModuleRegistry] Failed to load module \"%s\"", name)
+			continue
+		}
+
+		r.loaded = append(r.loaded, module)
+		log.Info().Msgf("[ModuleRegistry] Loaded module \"%s\"", name)
+	}
+
+	r.reports = make([]*ModuleReport, 0, len(r.names))
+	for _, name := range r.names {
+		r.reports = append(r.reports, statuses[name])
+	}
+
+	return r.reports, nil
+}
+
+func (r *ModuleRegistry) initModule(bot *Bot, module Module) error {
+	bot.Handlers.setModule(module.Name())
+	defer bot.Handlers.setModule("")
+
+	bot.rollback = make([]func(), 0)
+	defer func() {
+		bot.rollback = nil
+	}()
+
+	err := r.registerModule(bot, module)
+	if err != nil {
+		for i := len(bot.rollback) - 1; i >= 0; i-- {
+			bot.rollback[i]()
+		}
+		bot.Handlers.removeModule(module.Name())
+	}
+
+	return err
+}
+
+func (r *ModuleRegistry) registerModule(bot *Bot, module Module) error {
+	if err := module.Init(bot); err != nil {
+		return err
+	}
+
+	if err := RegisterCommands(bot, module.Commands()...); err != nil {
+		return err
+	}
+
+	if err := RegisterComponents(bot, module.Components()...); err != nil {
+		return err
+	}
+
+	if err := RegisterModals(bot, module.Modals()...); err != nil {
+		return err
+	}
+
+	return RegisterJobs(bot, module.Jobs()...)
+}
+
+// resolveOrder sorts the enabled modules so every module comes after its
+// dependencies, keeping the registration order otherwise.
+func (r *ModuleRegistry) resolveOrder(enabled []string) ([]string, error) {
+	order := make([]string, 0, len(enabled))
+	visiting := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visited[name] {
+			return nil
+		}
+
+		if visiting[name] {
+			return fmt.Errorf("%w: %v", ErrDependencyCycle, append(path, name))
+		}
+
+		module, ok := r.modules[name]
+		if !ok || !slices.Contains(enabled, name) {
+			// Reported as a missing dependency when loading
+			return nil
+		}
+
+		visiting[name] = true
+		for _, dependency := range module.Dependencies() {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range r.names {
+		if slices.Contains(enabled, name) {
+			if err := visit(name, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return order, nil
+}
+
+func (r *ModuleRegistry) Loaded() []Module {
+	return r.loaded
+}
+
+func (r *ModuleRegistry) Reports() []*ModuleReport {
+	return r.reports
+}
+
+// HandleReadyEvent calls the ready hook of every loaded module, a failing
+// module doesn't stop the others from getting the event.
+func (r *ModuleRegistry) HandleReadyEvent(ctx context.Context, s *discordgo.Session, e *discordgo.Ready) error {
+	if r.bot != nil {
+		ctx = withBot(ctx, r.bot)
+	}
+
+	errs := make([]error, 0)
+	for _, module := range r.loaded {
+		if err := module.Ready(ctx, s, e); err != nil {
+			errs = append(errs, fmt.Errorf("module %s: %w", module.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shutdown shuts the loaded modules down in reverse load order.
+func (r *ModuleRegistry) Shutdown(ctx context.Context) error {
+	errs := make([]error, 0)
+	for i := len(r.loaded) - 1; i >= 0; i-- {
+		module := r.loaded[i]
+		if err := module.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msgf("[ModuleRegistry] Failed to shut down module \"%s\"", module.Name())
+			errs = append(errs, fmt.Errorf("module %s: %w", module.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type testModule struct {
+	BaseModule
+	name       string
+	init       func(bot *Bot) error
+	commands   []*Command
+	components []*Component
+}
+
+func (m *testModule) Name() string {
+	return m.name
+}
+
+func (m *testModule) Init(bot *Bot) error {
+	if m.init == nil {
+		return nil
+	}
+
+	return m.init(bot)
+}
+
+func (m *testModule) Commands() []*Command {
+	return m.commands
+}
+
+func (m *testModule) Components() []*Component {
+	return m.components
+}
+
+func replyWith(content string) EventFunc[discordgo.InteractionCreate] {
+	return func(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+		return Respond(ctx, s, e.Interaction).Reply(&discordgo.InteractionResponseData{Content: content})
+	}
+}
+
+func pingCommand(middlewares ...MiddlewareFunc[discordgo.InteractionCreate]) *Command {
+	return &Command{
+		Identifier:  NewIdentifier("test", "commands/ping"),
+		Definition:  &discordgo.ApplicationCommand{Name: "ping", Description: "Ping"},
+		Handler:     replyWith("pong"),
+		Middlewares: middlewares,
+	}
+}
+
+// newTestBot returns a bot with its own services, its session answers REST
+// requests but never connects to the gateway.
+func newTestBot(t *testing.T) *Bot {
+	session, _ := newRestSession(t)
+	return &Bot{
+		Session:   session,
+		Router:    NewInteractionRouter(),
+		Commands:  NewCommandStack(),
+		Container: NewContainer(),
+		Events:    NewEventBus(),
+		Handlers:  NewHandlerRegistry(),
+	}
+}
+
+func TestLoadRollsBackFailedModules(t *testing.T) {
+	bot := newTestBot(t)
+	button := NewIdentifier("test", "components/button")
+
+	registry := NewModuleRegistry()
+	err := registry.Register(
+		&testModule{
+			name:       "first",
+			commands:   []*Command{pingCommand()},
+			components: []*Component{{Identifier: button, Handler: replyWith("clicked")}},
+		},
+		&testModule{
+			name: "second",
+			init: func(bot *Bot) error {
+				return AddEventHandler(bot, &EventHandler[discordgo.MessageCreate]{
+					Identifier: NewIdentifier("test", "events/message-create"),
+					Handler: func(_ context.Context, _ *discordgo.Session, _ *discordgo.MessageCreate) error {
+						return nil
+					},
+				})
+			},
+			commands: []*Command{{
+				Identifier: NewIdentifier("test", "commands/pong"),
+				Definition: &discordgo.ApplicationCommand{Name: "pong", Description: "Pong"},
+				Handler:    replyWith("ping"),
+			}},
+			// Fails once the command and the event handler are registered
+			components: []*Component{{Identifier: button, Handler: replyWith("clicked again")}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := registry.Load(bot, []string{"first", "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reports[0].Status != ModuleLoaded {
+		t.Errorf("first module: got %s (%v), want it loaded", reports[0].Status, reports[0].Err)
+	}
+	if reports[1].Status != ModuleFailed || !errors.Is(reports[1].Err, ErrDuplicateRoute) {
+		t.Errorf("second module: got %s (%v), want it failed with %v", reports[1].Status, reports[1].Err, ErrDuplicateRoute)
+	}
+
+	if bot.Router.HasCommand("pong") {
+		t.Error("the command of the failed module is still routed")
+	}
+	if commands := bot.Commands.Commands(); len(commands) != 1 || commands[0].Name != "ping" {
+		t.Errorf("got commands %+v, want only ping", commands)
+	}
+	for _, handler := range bot.Handlers.Handlers() {
+		if handler.Module == "second" {
+			t.Errorf("handler %s of the failed module is still listed", handler.Identifier)
+		}
+	}
+}
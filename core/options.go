@@ -0,0 +1,287 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	ErrOptionNotResolved = errors.New("option value not resolved")
+	ErrInvalidBindTarget = errors.New("bind target must be a pointer to a struct")
+	ErrInvalidOptionTag  = errors.New("invalid option tag")
+)
+
+var (
+	userType       = reflect.TypeOf((*discordgo.User)(nil))
+	memberType     = reflect.TypeOf((*discordgo.Member)(nil))
+	roleType       = reflect.TypeOf((*discordgo.Role)(nil))
+	channelType    = reflect.TypeOf((*discordgo.Channel)(nil))
+	attachmentType = reflect.TypeOf((*discordgo.MessageAttachment)(nil))
+)
+
+type optionTag struct {
+	name       string
+	required   bool
+	hasDefault bool
+	def        string
+}
+
+func parseOptionTag(tag string) (*optionTag, error) {
+	parts := strings.Split(tag, ",")
+
+	parsed := &optionTag{name: parts[0]}
+	if parsed.name == "" {
+		return nil, fmt.Errorf("%w: missing option name in %q", ErrInvalidOptionTag, tag)
+	}
+
+	for _, part := range parts[1:] {
+		switch {
+		case part == "required":
+			parsed.required = true
+		case strings.HasPrefix(part, "default="):
+			parsed.hasDefault = true
+			parsed.def = strings.TrimPrefix(part, "default=")
+		default:
+			return nil, fmt.Errorf("%w: unknown flag %q in %q", ErrInvalidOptionTag, part, tag)
+		}
+	}
+
+	return parsed, nil
+}
+
+// BindOptions decodes the options of the invoked subcommand (skipping any
+// subcommand group) into the fields of v tagged with `option:"name"`. Tags
+// accept the "required" and "default=value" flags, e.g.
+// `option:"limit,default=10"`.
+//
+// Strings, integers, floats and booleans are bound from their matching option
+// types, string fields also accept the ID of user, channel, role, mentionable
+// and attachment options. Fields of type *discordgo.User, *discordgo.Member,
+// *discordgo.Role, *discordgo.Channel and *discordgo.MessageAttachment are
+// bound from the interaction's resolved data.
+func BindOptions(data discordgo.ApplicationCommandInteractionData, v any) error {
+	return bindOptions(&optionResolver{resolved: data.Resolved}, data, v)
+}
+
+// BindInteractionOptions works like BindOptions but falls back to the state
+// and the REST API for entities missing from the resolved data.
+func BindInteractionOptions(s *discordgo.Session, i *discordgo.Interaction, v any) error {
+	data := i.ApplicationCommandData()
+	return bindOptions(&optionResolver{session: s, guildId: i.GuildID, resolved: data.Resolved}, data, v)
+}
+
+type optionResolver struct {
+	session  *discordgo.Session
+	guildId  string
+	resolved *discordgo.ApplicationCommandInteractionDataResolved
+}
+
+func bindOptions(resolver *optionResolver, data discordgo.ApplicationCommandInteractionData, v any) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+
+	options := GetCommandOptions(data)
+	target = target.Elem()
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+
+		tag, ok := field.Tag.Lookup("option")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		parsed, err := parseOptionTag(tag)
+		if err != nil {
+			return err
+		}
+
+		if err := bindOption(target.Field(i), options, resolver, parsed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func bindOption(field reflect.Value, options []*discordgo.ApplicationCommandInteractionDataOption, resolver *optionResolver, tag *optionTag) error {
+	err := bindOptionValue(field, options, resolver, tag.name)
+	if !errors.Is(err, ErrOptionNotFound) {
+		return err
+	}
+
+	if tag.required {
+		return fmt.Errorf("%w: %s", ErrOptionNotFound, tag.name)
+	}
+
+	if tag.hasDefault {
+		return bindDefault(field, tag)
+	}
+
+	return nil
+}
+
+func bindOptionValue(field reflect.Value, options []*discordgo.ApplicationCommandInteractionDataOption, resolver *optionResolver, name string) error {
+	switch field.Type() {
+	case userType, memberType, roleType, channelType, attachmentType:
+		value, err := resolver.resolve(options, name, field.Type())
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		value, err := GetStringOption(options, name)
+		if errors.Is(err, ErrOptionUnexpectedType) {
+			value, err = getSnowflakeOption(options, name)
+		}
+		if err != nil {
+			return unexpectedType(err, name, field)
+		}
+
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := GetIntegerOption(options, name)
+		if err != nil {
+			return unexpectedType(err, name, field)
+		}
+
+		field.SetInt(int64(value))
+	case reflect.Float32, reflect.Float64:
+		value, err := GetNumberOption(options, name)
+		if errors.Is(err, ErrOptionUnexpectedType) {
+			var integer int
+			integer, err = GetIntegerOption(options, name)
+			value = float64(integer)
+		}
+		if err != nil {
+			return unexpectedType(err, name, field)
+		}
+
+		field.SetFloat(value)
+	case reflect.Bool:
+		value, err := GetBooleanOption(options, name)
+		if err != nil {
+			return unexpectedType(err, name, field)
+		}
+
+		field.SetBool(value)
+	default:
+		return fmt.Errorf("%w: option %q can't be bound to a field of type %s", ErrOptionUnexpectedType, name, field.Type())
+	}
+
+	return nil
+}
+
+func unexpectedType(err error, name string, field reflect.Value) error {
+	if errors.Is(err, ErrOptionUnexpectedType) {
+		return fmt.Errorf("%w: option %q can't be bound to a field of type %s", ErrOptionUnexpectedType, name, field.Type())
+	}
+
+	return err
+}
+
+func bindDefault(field reflect.Value, tag *optionTag) error {
+	var err error
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(tag.def)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var value int64
+		if value, err = strconv.ParseInt(tag.def, 10, 64); err == nil {
+			field.SetInt(value)
+		}
+	case reflect.Float32, reflect.Float64:
+		var value float64
+		if value, err = strconv.ParseFloat(tag.def, 64); err == nil {
+			field.SetFloat(value)
+		}
+	case reflect.Bool:
+		var value bool
+		if value, err = strconv.ParseBool(tag.def); err == nil {
+			field.SetBool(value)
+		}
+	default:
+		return fmt.Errorf("%w: option %q of type %s can't have a default", ErrInvalidOptionTag, tag.name, field.Type())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: invalid default for option %q: %s", ErrInvalidOptionTag, tag.name, err)
+	}
+
+	return nil
+}
+
+func findOption(options []*discordgo.ApplicationCommandInteractionDataOption, name string) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, option := range options {
+		if option.Name == name {
+			return option
+		}
+	}
+
+	return nil
+}
+
+func getSnowflakeOption(options []*discordgo.ApplicationCommandInteractionDataOption, name string) (string, error) {
+	option := findOption(options, name)
+	if option == nil {
+		return "", ErrOptionNotFound
+	}
+
+	switch option.Type {
+	case discordgo.ApplicationCommandOptionUser,
+		discordgo.ApplicationCommandOptionChannel,
+		discordgo.ApplicationCommandOptionRole,
+		discordgo.ApplicationCommandOptionMentionable,
+		discordgo.ApplicationCommandOptionAttachment:
+		id, ok := option.Value.(string)
+		if !ok {
+			return "", ErrOptionUnexpectedType
+		}
+
+		return id, nil
+	}
+
+	return "", ErrOptionUnexpectedType
+}
+
+func (r *optionResolver) resolve(options []*discordgo.ApplicationCommandInteractionDataOption, name string, target reflect.Type) (any, error) {
+	id, err := getSnowflakeOption(options, name)
+	if err != nil {
+		return nil, unexpectedType(err, name, reflect.Zero(target))
+	}
+
+	var value any
+	switch target {
+	case userType:
+		value, err = resolveUser(r.session, r.guildId, r.resolved, id)
+	case memberType:
+		value, err = resolveMember(r.session, r.guildId, r.resolved, id)
+	case roleType:
+		value, err = resolveRole(r.session, r.guildId, r.resolved, id)
+	case channelType:
+		value, err = resolveChannel(r.session, r.resolved, id)
+	case attachmentType:
+		value, err = resolveAttachment(r.resolved, id)
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrOptionNotResolved) {
+			return nil, fmt.Errorf("%w: %s", ErrOptionNotResolved, name)
+		}
+
+		return nil, err
+	}
+
+	return value, nil
+}
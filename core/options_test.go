@@ -0,0 +1,156 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func stringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value}
+}
+
+// Integers come as floats from the gateway JSON.
+func integerOption(name string, value float64) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionInteger, Value: value}
+}
+
+func userOption(name, userId string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionUser, Value: userId}
+}
+
+func TestBindOptions(t *testing.T) {
+	data := discordgo.ApplicationCommandInteractionData{
+		Name: "e621",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			subcommandGroup("search", subcommand("posts",
+				stringOption("tags", "fox"),
+				integerOption("limit", 5),
+				userOption("user", "42"),
+			)),
+		},
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+			Users: map[string]*discordgo.User{"42": {ID: "42", Username: "fox"}},
+		},
+	}
+
+	var options struct {
+		Tags    string          `option:"tags,required"`
+		Limit   int             `option:"limit,default=10"`
+		Page    int             `option:"page,default=1"`
+		Safe    bool            `option:"safe"`
+		UserId  string          `option:"user"`
+		User    *discordgo.User `option:"user"`
+		Ignored string
+	}
+	if err := BindOptions(data, &options); err != nil {
+		t.Fatal(err)
+	}
+
+	if options.Tags != "fox" || options.Limit != 5 || options.Page != 1 || options.Safe {
+		t.Errorf("got %+v", options)
+	}
+	if options.UserId != "42" || options.User == nil || options.User.Username != "fox" {
+		t.Errorf("user option bound to %q and %+v", options.UserId, options.User)
+	}
+}
+
+func TestBindOptionsErrors(t *testing.T) {
+	data := discordgo.ApplicationCommandInteractionData{
+		Name:    "whitelist",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{subcommand("add", stringOption("user-id", "42"))},
+	}
+
+	var missing struct {
+		Reason string `option:"reason,required"`
+	}
+	if err := BindOptions(data, &missing); !errors.Is(err, ErrOptionNotFound) {
+		t.Errorf("missing required option: got %v, want %v", err, ErrOptionNotFound)
+	}
+
+	var mismatched struct {
+		UserId int `option:"user-id"`
+	}
+	if err := BindOptions(data, &mismatched); !errors.Is(err, ErrOptionUnexpectedType) {
+		t.Errorf("mismatched type: got %v, want %v", err, ErrOptionUnexpectedType)
+	}
+
+	var unresolved struct {
+		User *discordgo.User `option:"user-id"`
+	}
+	if err := BindOptions(data, &unresolved); !errors.Is(err, ErrOptionUnexpectedType) {
+		t.Errorf("string option bound to a user: got %v, want %v", err, ErrOptionUnexpectedType)
+	}
+
+	var badTag struct {
+		UserId string `option:"user-id,optional"`
+	}
+	if err := BindOptions(data, &badTag); !errors.Is(err, ErrInvalidOptionTag) {
+		t.Errorf("unknown tag flag: got %v, want %v", err, ErrInvalidOptionTag)
+	}
+
+	var notPointer struct{}
+	if err := BindOptions(data, notPointer); !errors.Is(err, ErrInvalidBindTarget) {
+		t.Errorf("non pointer target: got %v, want %v", err, ErrInvalidBindTarget)
+	}
+}
+
+func modalData(values map[string]string) discordgo.ModalSubmitInteractionData {
+	rows := make([]discordgo.MessageComponent, 0, len(values))
+	for id, value := range values {
+		rows = append(rows, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{discordgo.TextInput{CustomID: id, Value: value}},
+		})
+	}
+
+	return discordgo.ModalSubmitInteractionData{CustomID: "test:modal", Components: rows}
+}
+
+func TestBindModal(t *testing.T) {
+	data := modalData(map[string]string{
+		"reason":  "  spam\nand more  ",
+		"days":    " 7 ",
+		"notify":  "true",
+		"comment": "   ",
+	})
+
+	var values struct {
+		Reason  string `modal:"reason,required"`
+		Days    int    `modal:"days"`
+		Notify  bool   `modal:"notify"`
+		Comment string `modal:"comment,default=none"`
+		Missing string `modal:"missing"`
+	}
+	if err := BindModal(data, &values); err != nil {
+		t.Fatal(err)
+	}
+
+	// Strings are kept as submitted
+	if values.Reason != "  spam\nand more  " {
+		t.Errorf("reason = %q, want it untrimmed", values.Reason)
+	}
+	if values.Days != 7 || !values.Notify {
+		t.Errorf("got days %d and notify %t", values.Days, values.Notify)
+	}
+	// Blank values count as missing
+	if values.Comment != "none" || values.Missing != "" {
+		t.Errorf("got comment %q and missing %q", values.Comment, values.Missing)
+	}
+}
+
+func TestBindModalErrors(t *testing.T) {
+	var required struct {
+		Reason string `modal:"reason,required"`
+	}
+	if err := BindModal(modalData(map[string]string{"reason": " \n "}), &required); !errors.Is(err, ErrModalFieldNotFound) {
+		t.Errorf("blank required field: got %v, want %v", err, ErrModalFieldNotFound)
+	}
+
+	var number struct {
+		Days int `modal:"days"`
+	}
+	if err := BindModal(modalData(map[string]string{"days": "seven"}), &number); !errors.Is(err, ErrOptionUnexpectedType) {
+		t.Errorf("invalid number: got %v, want %v", err, ErrOptionUnexpectedType)
+	}
+}
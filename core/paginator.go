@@ -0,0 +1,345 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNoPages         = errors.New("paginator has no pages")
+	ErrPageOutOfBounds = errors.New("page out of bounds")
+)
+
+// DefaultPaginatorTimeout is how long paginators stay usable after their last
+// use. The controls are disabled through the token of the last interaction,
+// which lasts 15 minutes, so the timeout can't go past that.
+var DefaultPaginatorTimeout = 5 * time.Minute
+
+const maxPaginatorTimeout = 14 * time.Minute
+
+// PageSource renders the pages of a paginator on demand.
+type PageSource interface {
+	PageCount() int
+	Page(ctx context.Context, index int) (*discordgo.MessageEmbed, error)
+}
+
+// SlicePages is a page source of already rendered pages.
+type SlicePages []*discordgo.MessageEmbed
+
+func (p SlicePages) PageCount() int {
+	return len(p)
+}
+
+func (p SlicePages) Page(_ context.Context, index int) (*discordgo.MessageEmbed, error) {
+	if index < 0 || index >= len(p) {
+		return nil, fmt.Errorf("%w: %d", ErrPageOutOfBounds, index)
+	}
+
+	return p[index], nil
+}
+
+// PageFunc is a page source rendering every page through a callback.
+type PageFunc struct {
+	Count  int
+	Render func(ctx context.Context, index int) (*discordgo.MessageEmbed, error)
+}
+
+func (p PageFunc) PageCount() int {
+	return p.Count
+}
+
+func (p PageFunc) Page(ctx context.Context, index int) (*discordgo.MessageEmbed, error) {
+	if index < 0 || index >= p.Count {
+		return nil, fmt.Errorf("%w: %d", ErrPageOutOfBounds, index)
+	}
+
+	return p.Render(ctx, index)
+}
+
+// ChunkPages splits the items in pages of the given size, rendering each
+// chunk with the callback.
+func ChunkPages[T any](items []T, perPage int, render func(items []T, index int) *discordgo.MessageEmbed) PageSource {
+	if perPage <= 0 {
+		perPage = 1
+	}
+
+	return PageFunc{
+		Count: (len(items) + perPage - 1) / perPage,
+		Render: func(_ context.Context, index int) (*discordgo.MessageEmbed, error) {
+			start := index * perPage
+			end := min(start+perPage, len(items))
+
+			return render(items[start:end], index), nil
+		},
+	}
+}
+
+type paginatorAction string
+
+const (
+	paginatorFirst    paginatorAction = "first"
+	paginatorPrevious paginatorAction = "prev"
+	paginatorNext     paginatorAction = "next"
+	paginatorLast     paginatorAction = "last"
+)
+
+var PaginatorIdent = NewIdentifier("core", "components/paginator")
+
+// Paginator shows the pages of a source as embeds with first, previous, next
+// and last buttons. Only the user who invoked the interaction can use the
+// buttons, and they get disabled once the paginator expires.
+type Paginator struct {
+	id      string
+	source  PageSource
+	timeout time.Duration
+	session *discordgo.Session
+	userId  string
+	locale  discordgo.Locale
+
+	mu sync.Mutex
+	// interaction is the last one that showed the paginator, its token is
+	// the one used to disable the controls
+	interaction *discordgo.Interaction
+	embed       *discordgo.MessageEmbed
+	index       int
+	timer       *time.Timer
+	expired     bool
+}
+
+var paginators = struct {
+	sync.Mutex
+	active map[string]*Paginator
+}{
+	active: make(map[string]*Paginator),
+}
+
+func NewPaginator(source PageSource) *Paginator {
+	return &Paginator{
+		id:      xid.New().String(),
+		source:  source,
+		timeout: DefaultPaginatorTimeout,
+	}
+}
+
+// SetTimeout sets how long the paginator stays usable after its last use.
+func (p *Paginator) SetTimeout(timeout time.Duration) *Paginator {
+	p.timeout = min(timeout, maxPaginatorTimeout)
+	return p
+}
+
+// Send answers the interaction with the first page, or edits its response if
+// it was already deferred or answered.
+func (p *Paginator) Send(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction) error {
+	if p.source.PageCount() == 0 {
+		return ErrNoPages
+	}
+
+	p.session = s
+	p.interaction = i
+	p.userId = InteractionUserId(i)
+	p.locale = InteractionLocale(i)
+
+	embed, err := p.render(ctx)
+	if err != nil {
+		return err
+	}
+	p.embed = embed
+	components, err := p.controls(false)
+	if err != nil {
+		return err
+	}
+
+	if err := Respond(ctx, s, i).Edit(&discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	}); err != nil {
+		return err
+	}
+
+	// Single pages don't need controls
+	if p.source.PageCount() == 1 {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.timer = time.AfterFunc(p.timeout, p.expire)
+	p.mu.Unlock()
+
+	paginators.Lock()
+	paginators.active[p.id] = p
+	paginators.Unlock()
+
+	return nil
+}
+
+// render renders the current page, with the page number in its footer.
+func (p *Paginator) render(ctx context.Context) (*discordgo.MessageEmbed, error) {
+	page, err := p.source.Page(ctx, p.index)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the page so sources can hand out the same embed every time
+	embed := *page
+	indicator := coreMessages.Translate(p.locale, "paginator.page", Vars{"page": p.index + 1, "pages": p.source.PageCount()})
+	if embed.Footer == nil {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: indicator}
+	} else {
+		footer := *embed.Footer
+		footer.Text = footer.Text + " • " + indicator
+		embed.Footer = &footer
+	}
+
+	return &embed, nil
+}
+
+func (p *Paginator) controls(disabled bool) ([]discordgo.MessageComponent, error) {
+	count := p.source.PageCount()
+	if count == 1 {
+		return []discordgo.MessageComponent{}, nil
+	}
+
+	button := func(action paginatorAction, label string, enabled bool) *ButtonBuilder {
+		return NewButtonBuilder().
+			SetStyle(discordgo.SecondaryButton).
+			SetLabel(label).
+			SetCustomId(PaginatorIdent, p.id, string(action)).
+			SetDisabled(disabled || !enabled)
+	}
+
+	row, err := NewActionRowBuilder().
+		AddComponent(
+			button(paginatorFirst, "«", p.index > 0),
+			button(paginatorPrevious, "‹", p.index > 0),
+			button(paginatorNext, "›", p.index < count-1),
+			button(paginatorLast, "»", p.index < count-1),
+		).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return []discordgo.MessageComponent{row}, nil
+}
+
+func (p *Paginator) expire() {
+	paginators.Lock()
+	delete(paginators.active, p.id)
+	paginators.Unlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expired = true
+	components, err := p.controls(true)
+	if err == nil {
+		_, err = p.session.InteractionResponseEdit(p.interaction, &discordgo.WebhookEdit{
+			Embeds:     &[]*discordgo.MessageEmbed{p.embed},
+			Components: &components,
+		})
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Msgf("[Paginator] Failed to disable the controls of paginator %s", p.id)
+	}
+}
+
+func respondEphemeral(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction, embed *discordgo.MessageEmbed) error {
+	return Respond(ctx, s, i).Reply(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Flags:  discordgo.MessageFlagsEphemeral,
+	})
+}
+
+func respondExpiredPaginator(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction) error {
+	t := coreMessages.For(InteractionLocale(i))
+	return respondEphemeral(ctx, s, i, &discordgo.MessageEmbed{
+		Title:       t("paginator.expired.title"),
+		Description: t("paginator.expired.description"),
+		Color:       ColorWarning,
+	})
+}
+
+// HandlePaginatorComponent handles the buttons of every paginator.
+func HandlePaginatorComponent(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	args := CustomIdArgs(ctx)
+	if len(args) != 2 {
+		return fmt.Errorf("%w: %s", ErrInvalidCustomId, e.MessageComponentData().CustomID)
+	}
+
+	paginators.Lock()
+	p, ok := paginators.active[args[0]]
+	paginators.Unlock()
+
+	if !ok {
+		return respondExpiredPaginator(ctx, s, e.Interaction)
+	}
+
+	if InteractionUserId(e.Interaction) != p.userId {
+		t := coreMessages.For(InteractionLocale(e.Interaction))
+		return respondEphemeral(ctx, s, e.Interaction, &discordgo.MessageEmbed{
+			Title:       t("paginator.not-yours.title"),
+			Description: t("paginator.not-yours.description"),
+			Color:       ColorWarning,
+		})
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The paginator expired while waiting for the lock
+	if p.expired {
+		return respondExpiredPaginator(ctx, s, e.Interaction)
+	}
+
+	switch paginatorAction(args[1]) {
+	case paginatorFirst:
+		p.index = 0
+	case paginatorPrevious:
+		p.index = max(p.index-1, 0)
+	case paginatorNext:
+		p.index = min(p.index+1, p.source.PageCount()-1)
+	case paginatorLast:
+		p.index = p.source.PageCount() - 1
+	default:
+		return fmt.Errorf("%w: unknown paginator action %q", ErrInvalidCustomId, args[1])
+	}
+
+	embed, err := p.render(ctx)
+	if err != nil {
+		return err
+	}
+	components, err := p.controls(false)
+	if err != nil {
+		return err
+	}
+
+	if err := Respond(ctx, s, e.Interaction).Update(&discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	}); err != nil {
+		return err
+	}
+
+	// The token of the command expires 15 minutes after it was run, the
+	// controls are disabled through the one of this interaction instead
+	p.interaction = e.Interaction
+	p.embed = embed
+	p.timer.Reset(p.timeout)
+
+	return nil
+}
+
+// PaginatorComponent routes the buttons of every paginator, it has to be
+// registered for paginators to work.
+var PaginatorComponent = &Component{
+	Identifier: PaginatorIdent,
+	Handler:    HandlePaginatorComponent,
+}
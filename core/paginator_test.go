@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func numberPages(count int) PageSource {
+	numbers := make([]int, count)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+
+	return ChunkPages(numbers, 2, func(items []int, index int) *discordgo.MessageEmbed {
+		return &discordgo.MessageEmbed{Description: fmt.Sprint(items)}
+	})
+}
+
+// userEvent is an interaction of the given type invoked by the user in a DM.
+func userEvent(id string, kind discordgo.InteractionType, userId string) *discordgo.InteractionCreate {
+	e := commandEvent(id)
+	e.Type = kind
+	e.User = &discordgo.User{ID: userId}
+	return e
+}
+
+func sentEmbed(t *testing.T, request restRequest) *discordgo.MessageEmbed {
+	t.Helper()
+
+	var response sentResponse
+	if err := request.decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data.Embeds) != 1 {
+		t.Fatalf("got response %+v, want a single embed", response)
+	}
+
+	return response.Data.Embeds[0]
+}
+
+// forgetPaginator stops the paginator once the test is done.
+func forgetPaginator(t *testing.T, p *Paginator) {
+	t.Cleanup(func() {
+		p.timer.Stop()
+
+		paginators.Lock()
+		delete(paginators.active, p.id)
+		paginators.Unlock()
+	})
+}
+
+// controlsDisabled returns whether each paginator button is disabled.
+func controlsDisabled(t *testing.T, components []sentComponent) []bool {
+	t.Helper()
+
+	if len(components) != 1 {
+		t.Fatalf("got %d rows, want 1", len(components))
+	}
+
+	disabled := make([]bool, 0)
+	for _, component := range components[0].Components {
+		disabled = append(disabled, component.Disabled)
+	}
+
+	return disabled
+}
+
+func TestPaginatorSend(t *testing.T) {
+	session, rest := newRestSession(t)
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "alice")
+
+	p := NewPaginator(numberPages(5))
+	if err := p.Send(context.Background(), session, e.Interaction); err != nil {
+		t.Fatal(err)
+	}
+	forgetPaginator(t, p)
+
+	requests := rest.sent("POST", "/callback")
+	if len(requests) != 1 {
+		t.Fatalf("got %d callbacks, want the first page", len(requests))
+	}
+
+	var response sentResponse
+	if err := requests[0].decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if embed := sentEmbed(t, requests[0]); embed.Description != "[1 2]" || embed.Footer == nil || embed.Footer.Text != "Page 1 of 3" {
+		t.Errorf("got embed %+v, want the first page", embed)
+	}
+	if disabled := controlsDisabled(t, response.Data.Components); fmt.Sprint(disabled) != "[true true false false]" {
+		t.Errorf("got disabled buttons %v, want first and previous disabled", disabled)
+	}
+}
+
+func TestPaginatorSinglePage(t *testing.T) {
+	session, rest := newRestSession(t)
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "alice")
+
+	p := NewPaginator(numberPages(2))
+	if err := p.Send(context.Background(), session, e.Interaction); err != nil {
+		t.Fatal(err)
+	}
+
+	var response sentResponse
+	if err := rest.sent("POST", "/callback")[0].decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data.Components) != 0 {
+		t.Errorf("got components %+v, want none for a single page", response.Data.Components)
+	}
+
+	paginators.Lock()
+	_, active := paginators.active[p.id]
+	paginators.Unlock()
+	if active {
+		t.Error("a single page paginator is kept active")
+	}
+}
+
+func TestPaginatorNoPages(t *testing.T) {
+	session, _ := newRestSession(t)
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "alice")
+
+	if err := NewPaginator(numberPages(0)).Send(context.Background(), session, e.Interaction); err != ErrNoPages {
+		t.Errorf("got %v, want %v", err, ErrNoPages)
+	}
+}
+
+func TestHandlePaginatorComponent(t *testing.T) {
+	session, rest := newRestSession(t)
+
+	p := NewPaginator(numberPages(5))
+	if err := p.Send(context.Background(), session, userEvent("1", discordgo.InteractionApplicationCommand, "alice").Interaction); err != nil {
+		t.Fatal(err)
+	}
+	forgetPaginator(t, p)
+
+	click := func(id, userId string, action paginatorAction) restRequest {
+		t.Helper()
+
+		e := userEvent(id, discordgo.InteractionMessageComponent, userId)
+		ctx := withCustomIdArgs(context.Background(), []string{p.id, string(action)})
+		if err := HandlePaginatorComponent(ctx, session, e); err != nil {
+			t.Fatal(err)
+		}
+
+		requests := rest.sent("POST", "/interactions/"+id+"/"+e.Token+"/callback")
+		if len(requests) != 1 {
+			t.Fatalf("got %d callbacks, want 1", len(requests))
+		}
+
+		return requests[0]
+	}
+
+	if embed := sentEmbed(t, click("2", "alice", paginatorLast)); embed.Description != "[5]" || embed.Footer.Text != "Page 3 of 3" {
+		t.Errorf("got embed %+v, want the last page", embed)
+	}
+	if embed := sentEmbed(t, click("3", "alice", paginatorPrevious)); embed.Description != "[3 4]" || embed.Footer.Text != "Page 2 of 3" {
+		t.Errorf("got embed %+v, want the second page", embed)
+	}
+
+	// Other users are told off without moving the paginator
+	if embed := sentEmbed(t, click("4", "bob", paginatorFirst)); embed.Title != "Not your menu" {
+		t.Errorf("got embed %+v, want the not your menu one", embed)
+	}
+	if p.index != 1 {
+		t.Errorf("got page %d after another user clicked, want 1", p.index)
+	}
+}
+
+func TestPaginatorExpires(t *testing.T) {
+	session, rest := newRestSession(t)
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "alice")
+
+	p := NewPaginator(numberPages(5)).SetTimeout(10 * time.Millisecond)
+	if err := p.Send(context.Background(), session, e.Interaction); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(rest.sent("PATCH", "/messages/@original")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	edits := rest.sent("PATCH", "/webhooks/app/"+e.Token+"/messages/@original")
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits, want the controls disabled", len(edits))
+	}
+
+	var edit sentMessage
+	if err := edits[0].decode(&edit); err != nil {
+		t.Fatal(err)
+	}
+	if disabled := controlsDisabled(t, edit.Components); fmt.Sprint(disabled) != "[true true true true]" {
+		t.Errorf("got disabled buttons %v, want all of them disabled", disabled)
+	}
+
+	// Clicks on the expired paginator are answered with an expired notice
+	click := userEvent("2", discordgo.InteractionMessageComponent, "alice")
+	ctx := withCustomIdArgs(context.Background(), []string{p.id, string(paginatorNext)})
+	if err := HandlePaginatorComponent(ctx, session, click); err != nil {
+		t.Fatal(err)
+	}
+	if embed := sentEmbed(t, rest.sent("POST", "/interactions/2/"+click.Token+"/callback")[0]); embed.Title != "Menu expired" {
+		t.Errorf("got embed %+v, want the expired one", embed)
+	}
+}
+
+func TestPaginatorTimeoutClamped(t *testing.T) {
+	if p := NewPaginator(numberPages(5)).SetTimeout(time.Hour); p.timeout != maxPaginatorTimeout {
+		t.Errorf("got timeout %s, want it clamped to %s", p.timeout, maxPaginatorTimeout)
+	}
+}
+
+func TestPaginatorLocalized(t *testing.T) {
+	session, rest := newRestSession(t)
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "alice")
+	e.Locale = discordgo.SpanishES
+
+	p := NewPaginator(numberPages(5))
+	if err := p.Send(context.Background(), session, e.Interaction); err != nil {
+		t.Fatal(err)
+	}
+	forgetPaginator(t, p)
+
+	if embed := sentEmbed(t, rest.sent("POST", "/callback")[0]); embed.Footer == nil || embed.Footer.Text != "Página 1 de 3" {
+		t.Errorf("got embed %+v, want the Spanish page number", embed)
+	}
+}
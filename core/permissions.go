@@ -0,0 +1,183 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var owners = struct {
+	sync.RWMutex
+	ids []string
+}{}
+
+// SetOwners sets the users allowed to run owner only commands, the first one
+// is the contact shown to users when something goes wrong.
+func SetOwners(ids ...string) {
+	owners.Lock()
+	defer owners.Unlock()
+
+	owners.ids = slices.Clone(ids)
+}
+
+func Owners() []string {
+	owners.RLock()
+	defer owners.RUnlock()
+
+	return slices.Clone(owners.ids)
+}
+
+func IsOwner(userId string) bool {
+	owners.RLock()
+	defer owners.RUnlock()
+
+	return userId != "" && slices.Contains(owners.ids, userId)
+}
+
+// OwnerMention mentions the first owner, it's empty when there are none.
+func OwnerMention() string {
+	owners.RLock()
+	defer owners.RUnlock()
+
+	if len(owners.ids) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("<@%s>", owners.ids[0])
+}
+
+var permissionNames = []struct {
+	permission int64
+	name       string
+}{
+	{discordgo.PermissionAdministrator, "Administrator"},
+	{discordgo.PermissionManageServer, "Manage Server"},
+	{discordgo.PermissionManageRoles, "Manage Roles"},
+	{discordgo.PermissionManageChannels, "Manage Channels"},
+	{discordgo.PermissionManageMessages, "Manage Messages"},
+	{discordgo.PermissionManageThreads, "Manage Threads"},
+	{discordgo.PermissionManageWebhooks, "Manage Webhooks"},
+	{discordgo.PermissionKickMembers, "Kick Members"},
+	{discordgo.PermissionBanMembers, "Ban Members"},
+	{discordgo.PermissionModerateMembers, "Timeout Members"},
+	{discordgo.PermissionViewAuditLogs, "View Audit Log"},
+	{discordgo.PermissionSendMessages, "Send Messages"},
+	{discordgo.PermissionEmbedLinks, "Embed Links"},
+	{discordgo.PermissionAttachFiles, "Attach Files"},
+	{discordgo.PermissionMentionEveryone, "Mention Everyone"},
+}
+
+// PermissionNames lists the names of the permissions in the bitset, as shown
+// in the Discord client.
+func PermissionNames(permissions int64) []string {
+	names := make([]string, 0)
+	for _, p := range permissionNames {
+		if permissions&p.permission != 0 {
+			names = append(names, p.name)
+			permissions &^= p.permission
+		}
+	}
+
+	if permissions != 0 {
+		names = append(names, fmt.Sprintf("Other (%d)", permissions))
+	}
+
+	return names
+}
+
+// MissingPermissionEmbed is the standard reply to users who aren't allowed to
+// use a command, in their locale.
+func MissingPermissionEmbed(locale discordgo.Locale, reason string) *discordgo.MessageEmbed {
+	t := coreMessages.For(locale)
+	return &discordgo.MessageEmbed{
+		Title:       t("permissions.title"),
+		Description: reason,
+		Color:       ColorError,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: t("permissions.footer"),
+		},
+	}
+}
+
+// denyInteraction answers users who aren't allowed to run the handler with
+// the reason message, empty choices are sent to autocompletes.
+func denyInteraction(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate, key string, vars Vars) error {
+	log.Debug().Msgf("[PermissionMidware] Denied interaction %s to user %s: %s", e.ID, InteractionUserId(e.Interaction), coreMessages.Translate(DefaultLocale, key, vars))
+
+	responder := Respond(c, s, e.Interaction)
+	if e.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		return responder.Autocomplete([]*discordgo.ApplicationCommandOptionChoice{})
+	}
+
+	locale := InteractionLocale(e.Interaction)
+	return responder.Reply(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{MissingPermissionEmbed(locale, coreMessages.Translate(locale, key, vars))},
+		Flags:  discordgo.MessageFlagsEphemeral,
+	})
+}
+
+// MidwareOwnerOnly only lets the bot owners run the handler. It has to run
+// before anything answers the interaction.
+func MidwareOwnerOnly() MiddlewareFunc[discordgo.InteractionCreate] {
+	return func(next EventFunc[discordgo.InteractionCreate]) EventFunc[discordgo.InteractionCreate] {
+		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			if !IsOwner(InteractionUserId(e.Interaction)) {
+				return denyInteraction(c, s, e, "permissions.owner-only", nil)
+			}
+
+			return next(c, s, e)
+		}
+	}
+}
+
+// MidwareRequirePermissions only lets members with all the permissions in the
+// channel run the handler. It has to run before anything answers the
+// interaction.
+func MidwareRequirePermissions(permissions int64) MiddlewareFunc[discordgo.InteractionCreate] {
+	return func(next EventFunc[discordgo.InteractionCreate]) EventFunc[discordgo.InteractionCreate] {
+		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			if e.Member == nil {
+				return denyInteraction(c, s, e, "permissions.guild-only", nil)
+			}
+
+			// Administrators implicitly have every permission
+			granted := e.Member.Permissions
+			if granted&discordgo.PermissionAdministrator == 0 && granted&permissions != permissions {
+				missing := PermissionNames(permissions &^ granted)
+				return denyInteraction(c, s, e, "permissions.missing", Vars{"permissions": strings.Join(missing, "**, **")})
+			}
+
+			return next(c, s, e)
+		}
+	}
+}
+
+// MidwareRequireRoles only lets members with any of the roles run the
+// handler. It has to run before anything answers the interaction.
+func MidwareRequireRoles(roleIds ...string) MiddlewareFunc[discordgo.InteractionCreate] {
+	return func(next EventFunc[discordgo.InteractionCreate]) EventFunc[discordgo.InteractionCreate] {
+		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			if e.Member == nil {
+				return denyInteraction(c, s, e, "permissions.guild-only", nil)
+			}
+
+			for _, roleId := range roleIds {
+				if slices.Contains(e.Member.Roles, roleId) {
+					return next(c, s, e)
+				}
+			}
+
+			mentions := make([]string, 0, len(roleIds))
+			for _, roleId := range roleIds {
+				mentions = append(mentions, fmt.Sprintf("<@&%s>", roleId))
+			}
+
+			return denyInteraction(c, s, e, "permissions.roles", Vars{"roles": strings.Join(mentions, ", ")})
+		}
+	}
+}
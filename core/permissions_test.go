@@ -0,0 +1,172 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// useOwners sets the owners for the test, there are none once it's done.
+func useOwners(t *testing.T, ids ...string) {
+	SetOwners(ids...)
+	t.Cleanup(func() {
+		SetOwners()
+	})
+}
+
+func guildEvent(id string, member *discordgo.Member) *discordgo.InteractionCreate {
+	e := commandEvent(id)
+	e.GuildID = "guild"
+	e.Member = member
+	return e
+}
+
+// runGuarded runs a handler behind the middleware, returning whether it ran
+// and the denial sent instead.
+func runGuarded(t *testing.T, middleware MiddlewareFunc[discordgo.InteractionCreate], e *discordgo.InteractionCreate) (bool, *sentResponse) {
+	t.Helper()
+
+	session, rest := newRestSession(t)
+	ran := false
+	handler := ApplyMiddlewares(func(_ context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+		ran = true
+		return nil
+	}, middleware)
+
+	if err := handler(context.Background(), session, e); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := rest.sent("POST", "/callback")
+	if len(requests) == 0 {
+		return ran, nil
+	}
+
+	var response sentResponse
+	if err := requests[0].decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	return ran, &response
+}
+
+func TestMidwareOwnerOnly(t *testing.T) {
+	useOwners(t, "owner")
+
+	if ran, denial := runGuarded(t, MidwareOwnerOnly(), userEvent("1", discordgo.InteractionApplicationCommand, "owner")); !ran || denial != nil {
+		t.Errorf("owner: got ran %v and denial %+v, want it to run", ran, denial)
+	}
+
+	ran, denial := runGuarded(t, MidwareOwnerOnly(), userEvent("2", discordgo.InteractionApplicationCommand, "someone"))
+	if ran {
+		t.Fatal("the handler ran for a user who isn't an owner")
+	}
+	if denial == nil || denial.Data.Flags != discordgo.MessageFlagsEphemeral || len(denial.Data.Embeds) != 1 || denial.Data.Embeds[0].Title != "Missing permission" {
+		t.Errorf("got denial %+v, want the ephemeral missing permission embed", denial)
+	}
+}
+
+func TestOwnersDefaultToNone(t *testing.T) {
+	useOwners(t)
+
+	if IsOwner("") || IsOwner("someone") {
+		t.Error("got an owner with no owners configured")
+	}
+	if mention := OwnerMention(); mention != "" {
+		t.Errorf("got mention %q, want none", mention)
+	}
+}
+
+func TestMidwareRequirePermissions(t *testing.T) {
+	middleware := MidwareRequirePermissions(discordgo.PermissionBanMembers | discordgo.PermissionKickMembers)
+
+	tests := []struct {
+		name    string
+		event   *discordgo.InteractionCreate
+		ran     bool
+		details string
+	}{
+		{
+			name:  "granted",
+			event: guildEvent("1", &discordgo.Member{User: &discordgo.User{ID: "mod"}, Permissions: discordgo.PermissionBanMembers | discordgo.PermissionKickMembers}),
+			ran:   true,
+		},
+		{
+			name:  "administrator",
+			event: guildEvent("2", &discordgo.Member{User: &discordgo.User{ID: "admin"}, Permissions: discordgo.PermissionAdministrator}),
+			ran:   true,
+		},
+		{
+			name:    "missing",
+			event:   guildEvent("3", &discordgo.Member{User: &discordgo.User{ID: "member"}, Permissions: discordgo.PermissionKickMembers}),
+			details: "**Ban Members**",
+		},
+		{
+			name:    "direct message",
+			event:   userEvent("4", discordgo.InteractionApplicationCommand, "member"),
+			details: "only be used in a server",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ran, denial := runGuarded(t, middleware, test.event)
+			if ran != test.ran {
+				t.Fatalf("got ran %v, want %v", ran, test.ran)
+			}
+
+			if test.ran {
+				return
+			}
+			if denial == nil || len(denial.Data.Embeds) != 1 || !strings.Contains(denial.Data.Embeds[0].Description, test.details) {
+				t.Errorf("got denial %+v, want it to mention %q", denial, test.details)
+			}
+		})
+	}
+}
+
+func TestMidwareRequireRoles(t *testing.T) {
+	middleware := MidwareRequireRoles("mods", "admins")
+
+	member := &discordgo.Member{User: &discordgo.User{ID: "mod"}, Roles: []string{"everyone", "mods"}}
+	if ran, _ := runGuarded(t, middleware, guildEvent("1", member)); !ran {
+		t.Error("the handler didn't run for a member with one of the roles")
+	}
+
+	member = &discordgo.Member{User: &discordgo.User{ID: "member"}, Roles: []string{"everyone"}}
+	ran, denial := runGuarded(t, middleware, guildEvent("2", member))
+	if ran {
+		t.Fatal("the handler ran for a member without the roles")
+	}
+	if denial == nil || !strings.Contains(denial.Data.Embeds[0].Description, "<@&mods>, <@&admins>") {
+		t.Errorf("got denial %+v, want the roles mentioned", denial)
+	}
+}
+
+func TestDenyAutocomplete(t *testing.T) {
+	e := userEvent("1", discordgo.InteractionApplicationCommandAutocomplete, "someone")
+
+	ran, denial := runGuarded(t, MidwareOwnerOnly(), e)
+	if ran || denial == nil || denial.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+		t.Errorf("got ran %v and response %+v, want empty choices", ran, denial)
+	}
+}
+
+func TestMissingPermissionLocalized(t *testing.T) {
+	e := userEvent("1", discordgo.InteractionApplicationCommand, "someone")
+	e.Locale = discordgo.SpanishES
+
+	_, denial := runGuarded(t, MidwareOwnerOnly(), e)
+	if denial == nil || denial.Data.Embeds[0].Title != "Falta un permiso" || denial.Data.Embeds[0].Description != "Solo los dueños del bot pueden usar este comando." {
+		t.Errorf("got denial %+v, want it in Spanish", denial)
+	}
+}
+
+func TestPermissionNames(t *testing.T) {
+	names := PermissionNames(discordgo.PermissionManageRoles | discordgo.PermissionBanMembers | 1<<50)
+	if got := strings.Join(names, ", "); got != "Manage Roles, Ban Members, Other (1125899906842624)" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+// RecordedEvent is a raw gateway event as written by the EventRecorder, one
+// per line.
+type RecordedEvent struct {
+	Time    time.Time       `json:"time"`
+	Shard   int             `json:"shard"`
+	Type    string          `json:"type"`
+	GuildId string          `json:"guild_id,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// EventRecorder writes the raw events dispatched by the gateway to an NDJSON
+// file, so what happened in production can be replayed. The file is rotated
+// once it reaches its max size, the previous ones are kept with a numbered
+// suffix (".1" being the most recent).
+type EventRecorder struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewEventRecorder opens the recording, appending to it if it exists. A
+// maxSize of zero never rotates it.
+func NewEventRecorder(path string, maxSize int64, maxFiles int) (*EventRecorder, error) {
+	r := &EventRecorder{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *EventRecorder) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// rotate shifts the previous recordings by one, dropping the oldest, and
+// starts a new one. The caller holds the lock.
+func (r *EventRecorder) rotate() error {
+	err := r.file.Close()
+	r.file = nil
+	if err != nil {
+		return err
+	}
+
+	if r.maxFiles > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxFiles))
+		for i := r.maxFiles - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+
+		err = os.Rename(r.path, r.path+".1")
+	} else {
+		err = os.Remove(r.path)
+	}
+
+	// Keep recording to the current file if it couldn't be moved away
+	return errors.Join(err, r.open())
+}
+
+// Record writes the event to the recording.
+func (r *EventRecorder) Record(event *RecordedEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return os.ErrClosed
+	}
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(line)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate the recording: %w", err)
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+// HandleEvent records every event received by the session, add it as a
+// handler of each shard.
+func (r *EventRecorder) HandleEvent(s *discordgo.Session, e *discordgo.Event) {
+	if e.Type == "" {
+		return
+	}
+
+	err := r.Record(&RecordedEvent{
+		Time:    time.Now().UTC(),
+		Shard:   s.ShardID,
+		Type:    e.Type,
+		GuildId: eventGuildId(e.Type, e.RawData),
+		Data:    e.RawData,
+	})
+	if err != nil && !errors.Is(err, os.ErrClosed) {
+		log.Error().Err(err).Msgf("[EventRecorder] Failed to record %s event", e.Type)
+	}
+}
+
+// eventGuildId returns the ID of the guild the event belongs to, empty for
+// events outside of guilds.
+func eventGuildId(typ string, data json.RawMessage) string {
+	var ids struct {
+		Id      string `json:"id"`
+		GuildId string `json:"guild_id"`
+	}
+	json.Unmarshal(data, &ids)
+
+	switch typ {
+	case "GUILD_CREATE", "GUILD_UPDATE", "GUILD_DELETE":
+		return ids.Id
+	default:
+		return ids.GuildId
+	}
+}
+
+// Close closes the recording, events recorded afterwards are dropped.
+func (r *EventRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// ReadEvents calls fn with every event of the recording, in the order they
+// were recorded.
+func ReadEvents(reader io.Reader, fn func(event *RecordedEvent) error) error {
+	decoder := json.NewDecoder(reader)
+	for {
+		var event RecordedEvent
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+}
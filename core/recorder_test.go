@@ -0,0 +1,147 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// readRecording returns the types of the events of the recording.
+func readRecording(t *testing.T, path string) []string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	types := make([]string, 0)
+	err = ReadEvents(file, func(event *RecordedEvent) error {
+		types = append(types, event.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return types
+}
+
+func TestEventRecorderHandleEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	recorder, err := NewEventRecorder(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := &discordgo.Session{ShardID: 2}
+	recorder.HandleEvent(session, &discordgo.Event{Type: "GUILD_CREATE", RawData: json.RawMessage(`{"id":"guild"}`)})
+	recorder.HandleEvent(session, &discordgo.Event{Type: "MESSAGE_CREATE", RawData: json.RawMessage(`{"id":"message","guild_id":"guild"}`)})
+	recorder.HandleEvent(session, &discordgo.Event{Type: "READY", RawData: json.RawMessage(`{"v":10}`)})
+	// Opcodes other than dispatches have no type
+	recorder.HandleEvent(session, &discordgo.Event{Operation: 11})
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	events := make([]*RecordedEvent, 0)
+	if err := ReadEvents(file, func(event *RecordedEvent) error {
+		events = append(events, event)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	for i, want := range []string{"guild", "guild", ""} {
+		if events[i].Shard != 2 || events[i].GuildId != want || events[i].Time.IsZero() {
+			t.Errorf("got event %+v, want it from shard 2 in guild %q", events[i], want)
+		}
+	}
+	if string(events[1].Data) != `{"id":"message","guild_id":"guild"}` {
+		t.Errorf("got data %s, want the raw event", events[1].Data)
+	}
+}
+
+func TestEventRecorderRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	// Every line has the same size, two of them fit in a file
+	line := &RecordedEvent{Type: "TYPING_START", Data: json.RawMessage(`{}`)}
+	encoded, _ := json.Marshal(line)
+	recorder, err := NewEventRecorder(path, int64(2*(len(encoded)+1)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, typ := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		if err := recorder.Record(&RecordedEvent{Type: strings.Repeat(typ, len(line.Type)), Data: line.Data}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		path:        "GGGGGGGGGGGG",
+		path + ".1": "EEEEEEEEEEEE FFFFFFFFFFFF",
+		path + ".2": "CCCCCCCCCCCC DDDDDDDDDDDD",
+	}
+	for file, types := range want {
+		if got := strings.Join(readRecording(t, file), " "); got != types {
+			t.Errorf("%s: got %q, want %q", filepath.Base(file), got, types)
+		}
+	}
+
+	// The oldest one was dropped
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v for a third rotated file, want it missing", err)
+	}
+}
+
+func TestEventRecorderAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	for _, typ := range []string{"READY", "RESUMED"} {
+		recorder, err := NewEventRecorder(path, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := recorder.Record(&RecordedEvent{Type: typ, Data: json.RawMessage(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+		recorder.Close()
+	}
+
+	if got := strings.Join(readRecording(t, path), " "); got != "READY RESUMED" {
+		t.Errorf("got %q, want the events of both runs", got)
+	}
+}
+
+func TestEventRecorderClosed(t *testing.T) {
+	recorder, err := NewEventRecorder(filepath.Join(t.TempDir(), "events.ndjson"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+
+	if err := recorder.Record(&RecordedEvent{Type: "READY"}); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("got %v, want %v", err, os.ErrClosed)
+	}
+}
@@ -0,0 +1,331 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var ErrAlreadyResponded = errors.New("interaction already responded")
+
+type ResponseState int
+
+const (
+	// ResponseUnanswered interactions haven't been acknowledged yet.
+	ResponseUnanswered ResponseState = iota
+	// ResponseDeferred interactions show a loading message, the next reply
+	// replaces it.
+	ResponseDeferred
+	// ResponseDeferredUpdate components were acknowledged without touching
+	// their message, replies are sent as followups.
+	ResponseDeferredUpdate
+	// ResponseReplied interactions have their original response, further
+	// replies are sent as followups.
+	ResponseReplied
+)
+
+func (s ResponseState) String() string {
+	switch s {
+	case ResponseUnanswered:
+		return "unanswered"
+	case ResponseDeferred:
+		return "deferred"
+	case ResponseDeferredUpdate:
+		return "deferred update"
+	case ResponseReplied:
+		return "replied"
+	default:
+		return "unknown"
+	}
+}
+
+// Responder answers an interaction while keeping track of what was already
+// sent, so every call picks the Discord endpoint that fits. Calls are
+// serialized, so the auto defer timer can't race the handler.
+type Responder struct {
+	session     *discordgo.Session
+	interaction *discordgo.Interaction
+
+	mu    sync.Mutex
+	state ResponseState
+	flags discordgo.MessageFlags
+}
+
+func NewResponder(s *discordgo.Session, i *discordgo.Interaction) *Responder {
+	return &Responder{
+		session:     s,
+		interaction: i,
+	}
+}
+
+func (r *Responder) State() ResponseState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.state
+}
+
+// Flags returns the flags of the original response, e.g. whether it's
+// ephemeral.
+func (r *Responder) Flags() discordgo.MessageFlags {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.flags
+}
+
+// respond sends the original response, interactions someone else answered
+// behind the responder's back are marked as replied.
+func (r *Responder) respond(response *discordgo.InteractionResponse, state ResponseState) error {
+	err := r.session.InteractionRespond(r.interaction, response)
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeInteractionHasAlreadyBeenAcknowledged {
+		r.state = ResponseReplied
+		return ErrAlreadyResponded
+	}
+
+	if err != nil {
+		return err
+	}
+
+	r.state = state
+	if response.Data != nil {
+		r.flags = response.Data.Flags
+	}
+
+	return nil
+}
+
+// Defer acknowledges the interaction with a loading message, components are
+// acknowledged without touching their message instead. Answered interactions
+// are left untouched and return ErrAlreadyResponded.
+func (r *Responder) Defer(flags discordgo.MessageFlags) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != ResponseUnanswered {
+		return ErrAlreadyResponded
+	}
+
+	switch r.interaction.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionModalSubmit:
+		return r.respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Flags: flags,
+			},
+		}, ResponseDeferred)
+	case discordgo.InteractionMessageComponent:
+		return r.respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+		}, ResponseDeferredUpdate)
+	default:
+		return nil
+	}
+}
+
+// Reply sends a message: as the original response, replacing the loading
+// message of deferred interactions or as a followup once replied. The flags
+// of deferred interactions can't change anymore.
+func (r *Responder) Reply(data *discordgo.InteractionResponseData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case ResponseUnanswered:
+		return r.respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: data,
+		}, ResponseReplied)
+	case ResponseDeferred:
+		if _, err := r.session.InteractionResponseEdit(r.interaction, editFromData(data)); err != nil {
+			return err
+		}
+
+		r.state = ResponseReplied
+		return nil
+	default:
+		_, err := r.session.FollowupMessageCreate(r.interaction, true, &discordgo.WebhookParams{
+			TTS:             data.TTS,
+			Content:         data.Content,
+			Components:      data.Components,
+			Embeds:          data.Embeds,
+			AllowedMentions: data.AllowedMentions,
+			Files:           data.Files,
+			Flags:           data.Flags,
+		})
+		return err
+	}
+}
+
+// Update edits the message of a component, either as the original response
+// or through an edit once acknowledged.
+func (r *Responder) Update(data *discordgo.InteractionResponseData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == ResponseUnanswered {
+		return r.respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: data,
+		}, ResponseReplied)
+	}
+
+	if _, err := r.session.InteractionResponseEdit(r.interaction, editFromData(data)); err != nil {
+		return err
+	}
+
+	if r.state == ResponseDeferred {
+		r.state = ResponseReplied
+	}
+
+	return nil
+}
+
+// Edit edits the original response, unanswered interactions get the edit sent
+// as their reply instead.
+func (r *Responder) Edit(edit *discordgo.WebhookEdit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == ResponseUnanswered {
+		return r.respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: dataFromEdit(edit),
+		}, ResponseReplied)
+	}
+
+	if _, err := r.session.InteractionResponseEdit(r.interaction, edit); err != nil {
+		return err
+	}
+
+	if r.state == ResponseDeferred {
+		r.state = ResponseReplied
+	}
+
+	return nil
+}
+
+// Followup sends a new message, unanswered interactions get it sent as their
+// reply instead.
+func (r *Responder) Followup(params *discordgo.WebhookParams) (*discordgo.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == ResponseUnanswered {
+		err := r.respond(&discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				TTS:             params.TTS,
+				Content:         params.Content,
+				Components:      params.Components,
+				Embeds:          params.Embeds,
+				AllowedMentions: params.AllowedMentions,
+				Files:           params.Files,
+				Flags:           params.Flags,
+			},
+		}, ResponseReplied)
+		return nil, err
+	}
+
+	return r.session.FollowupMessageCreate(r.interaction, true, params)
+}
+
+// Modal opens a modal, which is only possible as the original response.
+func (r *Responder) Modal(data *discordgo.InteractionResponseData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != ResponseUnanswered {
+		return ErrAlreadyResponded
+	}
+
+	return r.respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: data,
+	}, ResponseReplied)
+}
+
+// Autocomplete answers an autocomplete interaction with its choices.
+func (r *Responder) Autocomplete(choices []*discordgo.ApplicationCommandOptionChoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != ResponseUnanswered {
+		return ErrAlreadyResponded
+	}
+
+	return r.respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	}, ResponseReplied)
+}
+
+func editFromData(data *discordgo.InteractionResponseData) *discordgo.WebhookEdit {
+	return &discordgo.WebhookEdit{
+		Content:         &data.Content,
+		Components:      &data.Components,
+		Embeds:          &data.Embeds,
+		Files:           data.Files,
+		Attachments:     data.Attachments,
+		AllowedMentions: data.AllowedMentions,
+	}
+}
+
+func dataFromEdit(edit *discordgo.WebhookEdit) *discordgo.InteractionResponseData {
+	data := &discordgo.InteractionResponseData{
+		Files:           edit.Files,
+		Attachments:     edit.Attachments,
+		AllowedMentions: edit.AllowedMentions,
+	}
+
+	if edit.Content != nil {
+		data.Content = *edit.Content
+	}
+
+	if edit.Components != nil {
+		data.Components = *edit.Components
+	}
+
+	if edit.Embeds != nil {
+		data.Embeds = *edit.Embeds
+	}
+
+	return data
+}
+
+type responderKey struct{}
+
+func WithResponder(ctx context.Context, r *Responder) context.Context {
+	return context.WithValue(ctx, responderKey{}, r)
+}
+
+// Respond returns the responder of the interaction from the context. Handlers
+// running without MidwareResponder get an untracked one, which still falls
+// back gracefully when the interaction was already answered.
+func Respond(ctx context.Context, s *discordgo.Session, i *discordgo.Interaction) *Responder {
+	if r, ok := ctx.Value(responderKey{}).(*Responder); ok && r.interaction.ID == i.ID {
+		return r
+	}
+
+	return NewResponder(s, i)
+}
+
+// MidwareResponder injects the responder shared by the handler and the
+// middlewares answering its interaction, it must be the outermost one.
+func MidwareResponder() MiddlewareFunc[discordgo.InteractionCreate] {
+	return func(next EventFunc[discordgo.InteractionCreate]) EventFunc[discordgo.InteractionCreate] {
+		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			if r, ok := c.Value(responderKey{}).(*Responder); ok && r.interaction.ID == e.ID {
+				return next(c, s, e)
+			}
+
+			return next(WithResponder(c, NewResponder(s, e.Interaction)), s, e)
+		}
+	}
+}
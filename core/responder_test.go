@@ -0,0 +1,138 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestResponderDeferThenReply(t *testing.T) {
+	session, rest := newRestSession(t)
+	e := commandEvent("1")
+	r := NewResponder(session, e.Interaction)
+
+	if err := r.Defer(discordgo.MessageFlagsEphemeral); err != nil {
+		t.Fatal(err)
+	}
+	if r.State() != ResponseDeferred || r.Flags() != discordgo.MessageFlagsEphemeral {
+		t.Fatalf("got state %s and flags %d after deferring", r.State(), r.Flags())
+	}
+
+	// Replies replace the loading message, then become followups
+	if err := r.Reply(&discordgo.InteractionResponseData{Content: "pong"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reply(&discordgo.InteractionResponseData{Content: "pong again"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if callbacks := rest.callbacks(t, e.Interaction); len(callbacks) != 1 || callbacks[0] != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("got callbacks %v, want a single deferred one", callbacks)
+	}
+
+	edits := rest.sent("PATCH", "/webhooks/app/"+e.Token+"/messages/@original")
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits of the original response, want 1", len(edits))
+	}
+	var edit discordgo.WebhookEdit
+	if err := edits[0].decode(&edit); err != nil {
+		t.Fatal(err)
+	}
+	if edit.Content == nil || *edit.Content != "pong" {
+		t.Errorf("got edit %+v, want pong", edit)
+	}
+
+	followups := rest.sent("POST", "/webhooks/app/"+e.Token)
+	if len(followups) != 1 {
+		t.Fatalf("got %d followups, want 1", len(followups))
+	}
+	var followup discordgo.WebhookParams
+	if err := followups[0].decode(&followup); err != nil {
+		t.Fatal(err)
+	}
+	if followup.Content != "pong again" {
+		t.Errorf("got followup %+v, want pong again", followup)
+	}
+
+	if r.State() != ResponseReplied {
+		t.Errorf("got state %s, want %s", r.State(), ResponseReplied)
+	}
+}
+
+func TestResponderEditUnanswered(t *testing.T) {
+	session, rest := newRestSession(t)
+	e := commandEvent("1")
+	r := NewResponder(session, e.Interaction)
+
+	content := "pong"
+	if err := r.Edit(&discordgo.WebhookEdit{Content: &content}); err != nil {
+		t.Fatal(err)
+	}
+
+	if callbacks := rest.callbacks(t, e.Interaction); len(callbacks) != 1 || callbacks[0] != discordgo.InteractionResponseChannelMessageWithSource {
+		t.Errorf("got callbacks %v, want the edit sent as the reply", callbacks)
+	}
+	if edits := rest.sent("PATCH", "/messages/@original"); len(edits) != 0 {
+		t.Errorf("got %d edits of an unanswered interaction", len(edits))
+	}
+}
+
+func TestResponderComponents(t *testing.T) {
+	session, rest := newRestSession(t)
+
+	deferred := commandEvent("1")
+	deferred.Type = discordgo.InteractionMessageComponent
+	r := NewResponder(session, deferred.Interaction)
+	if err := r.Defer(0); err != nil {
+		t.Fatal(err)
+	}
+	if r.State() != ResponseDeferredUpdate {
+		t.Errorf("got state %s, want %s", r.State(), ResponseDeferredUpdate)
+	}
+
+	// The message of the component is left alone, replies are followups
+	if err := r.Reply(&discordgo.InteractionResponseData{Content: "clicked"}); err != nil {
+		t.Fatal(err)
+	}
+	if callbacks := rest.callbacks(t, deferred.Interaction); len(callbacks) != 1 || callbacks[0] != discordgo.InteractionResponseDeferredMessageUpdate {
+		t.Errorf("got callbacks %v, want a deferred update", callbacks)
+	}
+	if followups := rest.sent("POST", "/webhooks/app/"+deferred.Token); len(followups) != 1 {
+		t.Errorf("got %d followups, want 1", len(followups))
+	}
+
+	updated := commandEvent("2")
+	updated.Type = discordgo.InteractionMessageComponent
+	if err := NewResponder(session, updated.Interaction).Update(&discordgo.InteractionResponseData{Content: "updated"}); err != nil {
+		t.Fatal(err)
+	}
+	if callbacks := rest.callbacks(t, updated.Interaction); len(callbacks) != 1 || callbacks[0] != discordgo.InteractionResponseUpdateMessage {
+		t.Errorf("got callbacks %v, want an update", callbacks)
+	}
+}
+
+func TestResponderAlreadyResponded(t *testing.T) {
+	session, _ := newRestSession(t)
+	e := commandEvent("1")
+	r := NewResponder(session, e.Interaction)
+
+	if err := r.Reply(&discordgo.InteractionResponseData{Content: "pong"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Defer(0); !errors.Is(err, ErrAlreadyResponded) {
+		t.Errorf("deferring a replied interaction: got %v, want %v", err, ErrAlreadyResponded)
+	}
+	if err := r.Modal(&discordgo.InteractionResponseData{CustomID: "test:modal", Title: "Modal"}); !errors.Is(err, ErrAlreadyResponded) {
+		t.Errorf("opening a modal on a replied interaction: got %v, want %v", err, ErrAlreadyResponded)
+	}
+
+	// Someone else answered behind the back of this responder
+	other := NewResponder(session, e.Interaction)
+	if err := other.Reply(&discordgo.InteractionResponseData{Content: "pong"}); !errors.Is(err, ErrAlreadyResponded) {
+		t.Errorf("got %v, want %v", err, ErrAlreadyResponded)
+	}
+	if other.State() != ResponseReplied {
+		t.Errorf("got state %s, want %s", other.State(), ResponseReplied)
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type restRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+func (r restRequest) decode(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// sentComponent is a message component as sent to Discord, discordgo can't
+// unmarshal them into its interface.
+type sentComponent struct {
+	Type       discordgo.ComponentType `json:"type"`
+	CustomID   string                  `json:"custom_id"`
+	Disabled   bool                    `json:"disabled"`
+	Components []sentComponent         `json:"components"`
+}
+
+// sentMessage is the message of an interaction response or of an edit.
+type sentMessage struct {
+	Content    string                    `json:"content"`
+	Embeds     []*discordgo.MessageEmbed `json:"embeds"`
+	Components []sentComponent           `json:"components"`
+	Flags      discordgo.MessageFlags    `json:"flags"`
+}
+
+type sentResponse struct {
+	Type discordgo.InteractionResponseType `json:"type"`
+	Data sentMessage                       `json:"data"`
+}
+
+// restRecorder stands in for the Discord REST API in the tests that don't
+// need a gateway. Every request succeeds, except for answering an interaction
+// twice which fails like Discord does.
+type restRecorder struct {
+	mu       sync.Mutex
+	requests []restRequest
+	answered map[string]bool
+}
+
+// newRestSession returns a session sending its requests to a recorder.
+func newRestSession(t *testing.T) (*discordgo.Session, *restRecorder) {
+	t.Helper()
+
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &restRecorder{answered: make(map[string]bool)}
+	session.Client = &http.Client{Transport: recorder}
+	return session, recorder
+}
+
+func (r *restRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, restRequest{Method: req.Method, Path: req.URL.Path, Body: body})
+
+	status, response := http.StatusOK, `{"id": "1"}`
+	if parts := strings.Split(req.URL.Path, "/"); len(parts) > 2 && parts[len(parts)-1] == "callback" {
+		// Callbacks are at /interactions/{id}/{token}/callback
+		id := parts[len(parts)-3]
+		if r.answered[id] {
+			status, response = http.StatusBadRequest, `{"code": 40060, "message": "Interaction has already been acknowledged."}`
+		} else {
+			r.answered[id] = true
+			status, response = http.StatusNoContent, ""
+		}
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(response)),
+		Request:    req,
+	}, nil
+}
+
+// sent returns the requests of the method whose path ends with the suffix.
+func (r *restRecorder) sent(method, suffix string) []restRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requests := make([]restRequest, 0)
+	for _, request := range r.requests {
+		if request.Method == method && strings.HasSuffix(request.Path, suffix) {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+// callbacks returns the types of the responses sent to the interaction.
+func (r *restRecorder) callbacks(t *testing.T, i *discordgo.Interaction) []discordgo.InteractionResponseType {
+	t.Helper()
+
+	types := make([]discordgo.InteractionResponseType, 0)
+	for _, request := range r.sent(http.MethodPost, "/interactions/"+i.ID+"/"+i.Token+"/callback") {
+		var response discordgo.InteractionResponse
+		if err := request.decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, response.Type)
+	}
+
+	return types
+}
+
+func commandEvent(id string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:    id,
+		AppID: "app",
+		Type:  discordgo.InteractionApplicationCommand,
+		Token: "token-" + id,
+		Data:  discordgo.ApplicationCommandInteractionData{Name: "ping"},
+	}}
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	ErrUnknownCommand      = errors.New("unknown command")
+	ErrUnknownAutocomplete = errors.New("unknown autocomplete")
+	ErrUnknownComponent    = errors.New("unknown component")
+	ErrUnknownModal        = errors.New("unknown modal")
+	ErrDuplicateRoute      = errors.New("route already registered")
+)
+
+// InteractionRouter dispatches every interaction received by the gateway to
+// the handler registered for it. Commands are keyed by their path, which is
+// the command name followed by the subcommand group and subcommand names
+// separated by spaces (e.g. "whitelist config enable").
+type InteractionRouter struct {
+	mu            sync.RWMutex
+	commands      map[string]EventFunc[discordgo.InteractionCreate]
+	autocompletes map[string]EventFunc[discordgo.InteractionCreate]
+	components    map[string]EventFunc[discordgo.InteractionCreate]
+	modals        map[string]EventFunc[discordgo.InteractionCreate]
+}
+
+func NewInteractionRouter() *InteractionRouter {
+	return &InteractionRouter{
+		commands:      make(map[string]EventFunc[discordgo.InteractionCreate]),
+		autocompletes: make(map[string]EventFunc[discordgo.InteractionCreate]),
+		components:    make(map[string]EventFunc[discordgo.InteractionCreate]),
+		modals:        make(map[string]EventFunc[discordgo.InteractionCreate]),
+	}
+}
+
+func CommandPath(names ...string) string {
+	return strings.Join(names, " ")
+}
+
+func (r *InteractionRouter) register(table map[string]EventFunc[discordgo.InteractionCreate], key string, fn EventFunc[discordgo.InteractionCreate]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := table[key]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateRoute, key)
+	}
+
+	table[key] = fn
+	return nil
+}
+
+func (r *InteractionRouter) unregister(table map[string]EventFunc[discordgo.InteractionCreate], key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(table, key)
+}
+
+// HandleCommand registers the handler for a command path. A handler
+// registered for a parent path receives every subcommand that doesn't have a
+// more specific handler.
+func (r *InteractionRouter) HandleCommand(path string, fn EventFunc[discordgo.InteractionCreate]) error {
+	return r.register(r.commands, path, fn)
+}
+
+// HandleAutocomplete registers the autocomplete handler for a command path,
+// the same fallback rules as HandleCommand apply.
+func (r *InteractionRouter) HandleAutocomplete(path string, fn EventFunc[discordgo.InteractionCreate]) error {
+	return r.register(r.autocompletes, path, fn)
+}
+
+// HandleComponent registers the handler for a component custom ID. Custom IDs
+// are matched by their "/" separated segments, so a handler registered for
+// "whitelist:confirm" also receives "whitelist:confirm/123".
+func (r *InteractionRouter) HandleComponent(customId string, fn EventFunc[discordgo.InteractionCreate]) error {
+	return r.register(r.components, customId, fn)
+}
+
+// HandleModal registers the handler for a modal custom ID, matched the same
+// way as components.
+func (r *InteractionRouter) HandleModal(customId string, fn EventFunc[discordgo.InteractionCreate]) error {
+	return r.register(r.modals, customId, fn)
+}
+
+// HasCommand tells whether a handler is registered for the command or any of
+// its subcommands.
+func (r *InteractionRouter) HasCommand(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, table := range []map[string]EventFunc[discordgo.InteractionCreate]{r.commands, r.autocompletes} {
+		for path := range table {
+			if path == name || strings.HasPrefix(path, name+" ") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasComponent tells whether a handler is registered for the custom ID.
+func (r *InteractionRouter) HasComponent(customId string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.components[customId]
+	return ok
+}
+
+// HasModal tells whether a handler is registered for the custom ID.
+func (r *InteractionRouter) HasModal(customId string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.modals[customId]
+	return ok
+}
+
+func (r *InteractionRouter) lookup(table map[string]EventFunc[discordgo.InteractionCreate], segments []string, separator string) (EventFunc[discordgo.InteractionCreate], int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(segments); i > 0; i-- {
+		if fn, ok := table[strings.Join(segments[:i], separator)]; ok {
+			return fn, i, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// ResolveCommandPath returns the command name followed by the invoked
+// subcommand group and subcommand, if any.
+func ResolveCommandPath(data discordgo.ApplicationCommandInteractionData) []string {
+	path := []string{data.Name}
+
+	options := data.Options
+	for len(options) > 0 {
+		option := options[0]
+		if option.Type != discordgo.ApplicationCommandOptionSubCommandGroup && option.Type != discordgo.ApplicationCommandOptionSubCommand {
+			break
+		}
+
+		path = append(path, option.Name)
+		options = option.Options
+	}
+
+	return path
+}
+
+func (r *InteractionRouter) HandleInteraction(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	switch e.Type {
+	case discordgo.InteractionApplicationCommand:
+		path := ResolveCommandPath(e.ApplicationCommandData())
+		fn, _, ok := r.lookup(r.commands, path, " ")
+		if !ok {
+			return fmt.Errorf("%w: /%s", ErrUnknownCommand, CommandPath(path...))
+		}
+
+		return fn(ctx, s, e)
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		path := ResolveCommandPath(e.ApplicationCommandData())
+		fn, _, ok := r.lookup(r.autocompletes, path, " ")
+		if !ok {
+			return fmt.Errorf("%w: /%s", ErrUnknownAutocomplete, CommandPath(path...))
+		}
+
+		return fn(ctx, s, e)
+	case discordgo.InteractionMessageComponent:
+		return r.handleCustomId(ctx, s, e, r.components, e.MessageComponentData().CustomID, ErrUnknownComponent)
+	case discordgo.InteractionModalSubmit:
+		return r.handleCustomId(ctx, s, e, r.modals, e.ModalSubmitData().CustomID, ErrUnknownModal)
+	}
+
+	return nil
+}
+
+func (r *InteractionRouter) handleCustomId(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate, table map[string]EventFunc[discordgo.InteractionCreate], customId string, errUnknown error) error {
+	segments := strings.Split(customId, "/")
+	fn, matched, ok := r.lookup(table, segments, "/")
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknown, customId)
+	}
+
+	// The segments past the route are the arguments of the custom ID
+	args, err := decodeCustomIdArgs(segments[matched:])
+	if err != nil {
+		return err
+	}
+
+	return fn(withCustomIdArgs(ctx, args), s, e)
+}
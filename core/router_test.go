@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func commandInteraction(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:   "1",
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{Name: name, Options: options},
+	}}
+}
+
+func componentInteraction(customId string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:   "1",
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: customId},
+	}}
+}
+
+func subcommand(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options}
+}
+
+func subcommandGroup(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionSubCommandGroup, Options: options}
+}
+
+// recordRoute returns a handler appending the name to the routes it was
+// called for.
+func recordRoute(routes *[]string, name string) EventFunc[discordgo.InteractionCreate] {
+	return func(_ context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+		*routes = append(*routes, name)
+		return nil
+	}
+}
+
+func TestRouterRoutesCommandPaths(t *testing.T) {
+	router := NewInteractionRouter()
+
+	var routes []string
+	for _, path := range []string{"whitelist", "whitelist add", "whitelist config enable"} {
+		if err := router.HandleCommand(path, recordRoute(&routes, path)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	interactions := []*discordgo.InteractionCreate{
+		commandInteraction("whitelist", subcommand("add")),
+		commandInteraction("whitelist", subcommandGroup("config", subcommand("enable"))),
+		// No handler for these, the parent one gets them
+		commandInteraction("whitelist", subcommand("list")),
+		commandInteraction("whitelist", subcommandGroup("config", subcommand("status"))),
+	}
+	for _, e := range interactions {
+		if err := router.HandleInteraction(context.Background(), nil, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"whitelist add", "whitelist config enable", "whitelist", "whitelist"}
+	if !slices.Equal(routes, want) {
+		t.Errorf("routed to %q, want %q", routes, want)
+	}
+}
+
+func TestRouterUnknownRoutes(t *testing.T) {
+	router := NewInteractionRouter()
+
+	var routes []string
+	if err := router.HandleCommand("ledger enable", recordRoute(&routes, "ledger enable")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := router.HandleInteraction(context.Background(), nil, commandInteraction("ledger", subcommand("disable")))
+	if !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("got %v, want %v", err, ErrUnknownCommand)
+	}
+
+	err = router.HandleInteraction(context.Background(), nil, componentInteraction("ledger:confirm"))
+	if !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("got %v, want %v", err, ErrUnknownComponent)
+	}
+
+	if len(routes) != 0 {
+		t.Errorf("unexpected routes %q", routes)
+	}
+}
+
+func TestRouterRejectsDuplicateRoutes(t *testing.T) {
+	router := NewInteractionRouter()
+
+	var routes []string
+	if err := router.HandleCommand("ping", recordRoute(&routes, "first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.HandleCommand("ping", recordRoute(&routes, "second")); !errors.Is(err, ErrDuplicateRoute) {
+		t.Errorf("got %v, want %v", err, ErrDuplicateRoute)
+	}
+
+	if err := router.HandleComponent("ping:button", recordRoute(&routes, "first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.HandleComponent("ping:button", recordRoute(&routes, "second")); !errors.Is(err, ErrDuplicateRoute) {
+		t.Errorf("got %v, want %v", err, ErrDuplicateRoute)
+	}
+
+	// The first handler is kept
+	if err := router.HandleInteraction(context.Background(), nil, commandInteraction("ping")); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(routes, []string{"first"}) {
+		t.Errorf("routed to %q, want the first handler", routes)
+	}
+}
+
+func TestRouterHasCommand(t *testing.T) {
+	router := NewInteractionRouter()
+	if err := router.HandleCommand("whitelist config enable", recordRoute(new([]string), "")); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{"whitelist": true, "white": false, "ledger": false} {
+		if got := router.HasCommand(name); got != want {
+			t.Errorf("HasCommand(%q) = %t, want %t", name, got, want)
+		}
+	}
+
+	router.unregister(router.commands, "whitelist config enable")
+	if router.HasCommand("whitelist") {
+		t.Error("HasCommand(\"whitelist\") = true after unregistering it")
+	}
+}
+
+func TestRouterPassesCustomIdArgs(t *testing.T) {
+	router := NewInteractionRouter()
+	identifier := NewIdentifier("test", "components/confirm")
+
+	var args []string
+	err := router.HandleComponent(identifier.String(), func(ctx context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+		args = CustomIdArgs(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customId, err := CustomId(identifier, "a/b", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := router.HandleInteraction(context.Background(), nil, componentInteraction(customId)); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"a/b", "c"}; !slices.Equal(args, want) {
+		t.Errorf("got args %q, want %q", args, want)
+	}
+}
@@ -0,0 +1,489 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrUnknownJob  = errors.New("unknown job type")
+	ErrNoScheduler = errors.New("no job scheduler configured")
+	ErrJobPanicked = errors.New("job panicked")
+)
+
+var SchedulerKey = NewKey[*Scheduler]("core", "service/scheduler")
+
+// jobStoreTimeout bounds the store operations of the scheduler itself.
+const jobStoreTimeout = 10 * time.Second
+
+var (
+	// JobPollInterval is how often the scheduler looks for due jobs.
+	JobPollInterval = 10 * time.Second
+	// JobLease is how long a claimed job stays locked, jobs of an instance
+	// that died while running them are picked up again once it expires. It
+	// has to be longer than the timeout of any job.
+	JobLease = 15 * time.Minute
+	// DefaultJobAttempts applies to jobs without a limit of their own.
+	DefaultJobAttempts = 5
+)
+
+// JobBackoff returns how long to wait before retrying a job that failed the
+// given number of times, doubling from 30 seconds up to an hour.
+var JobBackoff = func(attempts int) time.Duration {
+	backoff := 30 * time.Second
+	for i := 1; i < attempts && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, time.Hour)
+}
+
+// Job is a unit of work persisted by the scheduler, it's the event handed to
+// job handlers.
+type Job struct {
+	Id          string
+	Type        *Identifier
+	Key         string
+	Payload     json.RawMessage
+	Cron        string
+	RunAt       time.Time
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+}
+
+// Bind decodes the payload of the job into v.
+func (j *Job) Bind(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// JobSpec describes a job to schedule.
+type JobSpec struct {
+	// Type is the identifier of the handler running the job.
+	Type *Identifier
+	// Key replaces the pending job with the same key instead of adding
+	// another one, e.g. one reminder per user.
+	Key string
+	// Payload is encoded as JSON and handed to the handler through Job.Bind.
+	Payload any
+	// RunAt is when the job runs, right away when zero, or at the next
+	// occurrence of Cron if set.
+	RunAt time.Time
+	// Cron makes the job recurring, it runs again at every occurrence.
+	Cron string
+	// MaxAttempts bounds how many times a failing job is retried,
+	// DefaultJobAttempts is used when zero.
+	MaxAttempts int
+}
+
+// JobHandler is the job counterpart of Command, it binds the handler of a job
+// type to its middlewares and dependencies.
+type JobHandler struct {
+	Identifier *Identifier
+	Handler    EventFunc[Job]
+
+	// Middlewares wrap the handler, the first one being the outermost.
+	Middlewares []MiddlewareFunc[Job]
+
+	// Requires lists the dependencies the handler resolves with Use.
+	Requires []Dependency
+
+	// Timeout bounds how long the handler can run, DefaultHandlerTimeout is
+	// used when zero.
+	Timeout time.Duration
+
+	// Cron schedules the job to run periodically without a payload, keyed by
+	// its identifier so restarts don't add it twice.
+	Cron string
+
+	// MaxAttempts bounds how many times a failing job is retried unless
+	// overridden by its JobSpec, DefaultJobAttempts is used when zero.
+	MaxAttempts int
+}
+
+// Scheduler runs delayed and recurring jobs persisted in a JobStore, retrying
+// failed ones with an exponential backoff. Jobs are claimed with a lease so
+// several instances can share the same store.
+type Scheduler struct {
+	store   JobStore
+	session *discordgo.Session
+
+	mu        sync.RWMutex
+	handlers  map[string]*jobHandler
+	recurring []*JobSpec
+
+	started  bool
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type jobHandler struct {
+	fn          EventFunc[Job]
+	maxAttempts int
+}
+
+func NewScheduler(store JobStore, session *discordgo.Session) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		session:  session,
+		handlers: make(map[string]*jobHandler),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// RegisterJobs checks the dependencies of the job handlers and registers them
+// to the bot scheduler, wrapped with their middlewares and their timeout.
+func RegisterJobs(bot *Bot, jobs ...*JobHandler) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	if bot.Scheduler == nil {
+		return ErrNoScheduler
+	}
+
+	for _, job := range jobs {
+		if job.Identifier == nil || job.Handler == nil {
+			return fmt.Errorf("%w: jobs need an identifier and a handler", ErrInvalidCommandDeclaration)
+		}
+
+		if err := bot.Container.Validate(job.Requires...); err != nil {
+			return fmt.Errorf("job %s: %w", job.Identifier, err)
+		}
+
+		if job.Cron != "" {
+			if _, err := ParseCron(job.Cron); err != nil {
+				return fmt.Errorf("job %s: %w", job.Identifier, err)
+			}
+		}
+
+		if job.Timeout != 0 {
+			SetHandlerTimeout(job.Identifier, job.Timeout)
+		}
+
+		middlewares := make([]MiddlewareFunc[Job], 0, len(job.Middlewares)+1)
+		middlewares = append(middlewares, job.Middlewares...)
+		middlewares = append(middlewares, MidwareTimeout[Job](job.Identifier))
+
+		fn := bindBot(bot, ApplyMiddlewares(job.Handler, middlewares...))
+
+		info := handlerInfo(HandlerJob, job.Identifier, nil, job.Requires, middlewares)
+		if job.Cron != "" {
+			info.Routes = []string{job.Cron}
+		}
+		bot.Handlers.add(info)
+
+		key := job.Identifier.String()
+		bot.onRollback(func() {
+			bot.Scheduler.mu.Lock()
+			defer bot.Scheduler.mu.Unlock()
+
+			delete(bot.Scheduler.handlers, key)
+			bot.Scheduler.recurring = slices.DeleteFunc(bot.Scheduler.recurring, func(spec *JobSpec) bool {
+				return spec.Key == key
+			})
+		})
+
+		bot.Scheduler.mu.Lock()
+		bot.Scheduler.handlers[key] = &jobHandler{
+			fn:          fn,
+			maxAttempts: job.MaxAttempts,
+		}
+
+		if job.Cron != "" {
+			bot.Scheduler.recurring = append(bot.Scheduler.recurring, &JobSpec{
+				Type:        job.Identifier,
+				Key:         key,
+				Cron:        job.Cron,
+				MaxAttempts: job.MaxAttempts,
+			})
+		}
+		bot.Scheduler.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Schedule persists the job and returns its ID, or the ID of the job it
+// replaced if it has a key.
+func (s *Scheduler) Schedule(ctx context.Context, spec *JobSpec) (string, error) {
+	if spec.Type == nil {
+		return "", fmt.Errorf("%w: jobs need a type", ErrUnknownJob)
+	}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[spec.Type.String()]
+	s.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownJob, spec.Type)
+	}
+
+	payload := json.RawMessage("{}")
+	if spec.Payload != nil {
+		encoded, err := json.Marshal(spec.Payload)
+		if err != nil {
+			return "", err
+		}
+		payload = encoded
+	}
+
+	runAt := spec.RunAt
+	if spec.Cron != "" {
+		cron, err := ParseCron(spec.Cron)
+		if err != nil {
+			return "", err
+		}
+
+		if runAt.IsZero() {
+			if runAt, err = cron.Next(time.Now()); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+
+	maxAttempts := spec.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = handler.maxAttempts
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultJobAttempts
+	}
+
+	id, err := s.store.SaveJob(ctx, &Job{
+		Id:          xid.New().String(),
+		Type:        spec.Type,
+		Key:         spec.Key,
+		Payload:     payload,
+		Cron:        spec.Cron,
+		RunAt:       runAt,
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Don't wait for the next poll when the job is already due
+	if !runAt.After(time.Now()) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return id, nil
+}
+
+// Cancel deletes the job with the given ID.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	return s.store.DeleteJob(ctx, id)
+}
+
+// CancelKey deletes the job with the given key.
+func (s *Scheduler) CancelKey(ctx context.Context, key string) error {
+	return s.store.DeleteJobByKey(ctx, key)
+}
+
+// Start schedules the recurring jobs and starts polling for due jobs. Call it
+// once the modules registered their jobs.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	recurring := s.recurring
+	s.started = true
+	s.mu.Unlock()
+
+	for _, spec := range recurring {
+		if _, err := s.Schedule(ctx, spec); err != nil {
+			return fmt.Errorf("recurring job %s: %w", spec.Type, err)
+		}
+	}
+
+	go s.poll()
+	return nil
+}
+
+// Stop stops polling for jobs, the running ones are waited for by Drain like
+// any other handler. It does nothing if the scheduler wasn't started.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+
+		s.mu.RLock()
+		started := s.started
+		s.mu.RUnlock()
+
+		if started {
+			<-s.done
+		}
+	})
+}
+
+func (s *Scheduler) poll() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(JobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *Scheduler) runDue() {
+	if IsShuttingDown() {
+		return
+	}
+
+	s.mu.RLock()
+	types := make([]string, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		types = append(types, jobType)
+	}
+	s.mu.RUnlock()
+
+	if len(types) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobStoreTimeout)
+	defer cancel()
+
+	jobs, err := s.store.ClaimJobs(ctx, types, time.Now(), JobLease, 10)
+	if err != nil {
+		log.Error().Err(err).Msg("[Scheduler] Failed to claim due jobs!")
+		return
+	}
+
+	for _, job := range jobs {
+		if !botLifecycle.acquire() {
+			// Let the next start pick it up without counting an attempt
+			log.Debug().Msgf("[Scheduler] Released job %s, shutting down", job.Id)
+			s.save(job, s.store.RescheduleJob)
+			continue
+		}
+
+		go s.run(job)
+	}
+}
+
+func (s *Scheduler) run(job *Job) {
+	defer botLifecycle.release()
+
+	s.mu.RLock()
+	handler := s.handlers[job.Type.String()]
+	s.mu.RUnlock()
+
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				// Get stacktrace
+				stacktrace := make([]byte, 4096)
+				count := runtime.Stack(stacktrace, false)
+
+				log.Debug().Msg("[Scheduler] Stack trace: \n" + string(stacktrace[:count]))
+				err = fmt.Errorf("%w: %v", ErrJobPanicked, rec)
+			}
+		}()
+
+		return handler.fn(botLifecycle.ctx, s.session, job)
+	}()
+
+	s.complete(job, err)
+}
+
+// complete records the outcome of the job: finished one-off jobs are deleted,
+// recurring ones move to their next occurrence, failed ones are retried
+// later until they run out of attempts.
+func (s *Scheduler) complete(job *Job, err error) {
+	now := time.Now()
+
+	if err == nil {
+		log.Debug().Msgf("[Scheduler] Job %s (%s) completed", job.Id, job.Type)
+
+		if job.Cron == "" {
+			s.save(job, func(ctx context.Context, job *Job) error {
+				return s.store.DeleteJob(ctx, job.Id)
+			})
+			return
+		}
+
+		job.Attempts, job.LastError = 0, ""
+		s.reschedule(job, now)
+		return
+	}
+
+	job.Attempts++
+	job.LastError = err.Error()
+
+	if job.Attempts < job.MaxAttempts {
+		backoff := JobBackoff(job.Attempts)
+		log.Warn().Err(err).Msgf("[Scheduler] Job %s (%s) failed, attempt %d of %d, retrying in %s", job.Id, job.Type, job.Attempts, job.MaxAttempts, backoff)
+
+		job.RunAt = now.Add(backoff)
+		s.save(job, s.store.RescheduleJob)
+		return
+	}
+
+	log.Error().Err(err).Msgf("[Scheduler] Job %s (%s) failed after %d attempts!", job.Id, job.Type, job.Attempts)
+
+	// Recurring jobs give up on this occurrence only
+	if job.Cron != "" {
+		job.Attempts = 0
+		s.reschedule(job, now)
+		return
+	}
+
+	s.save(job, s.store.FailJob)
+}
+
+func (s *Scheduler) reschedule(job *Job, now time.Time) {
+	cron, err := ParseCron(job.Cron)
+	if err == nil {
+		job.RunAt, err = cron.Next(now)
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msgf("[Scheduler] Failed to reschedule recurring job %s (%s)!", job.Id, job.Type)
+		job.LastError = err.Error()
+		s.save(job, s.store.FailJob)
+		return
+	}
+
+	s.save(job, s.store.RescheduleJob)
+}
+
+// save runs the store operation on its own context, the one of the job may
+// already be cancelled.
+func (s *Scheduler) save(job *Job, op func(ctx context.Context, job *Job) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jobStoreTimeout)
+	defer cancel()
+
+	if err := op(ctx, job); err != nil {
+		log.Error().Err(err).Msgf("[Scheduler] Failed to save the state of job %s (%s)!", job.Id, job.Type)
+	}
+}
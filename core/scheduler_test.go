@@ -0,0 +1,363 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// memoryJobStore keeps the jobs in memory, like PostgresJobStore does in its
+// table.
+type memoryJobStore struct {
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	failed map[string]bool
+	locked map[string]time.Time
+}
+
+func newMemoryJobStore() *memoryJobStore {
+	return &memoryJobStore{
+		jobs:   make(map[string]*Job),
+		failed: make(map[string]bool),
+		locked: make(map[string]time.Time),
+	}
+}
+
+func (m *memoryJobStore) SaveJob(_ context.Context, job *Job) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := *job
+	for id, other := range m.jobs {
+		if job.Key == "" || other.Key != job.Key {
+			continue
+		}
+
+		saved.Id = id
+		if job.Cron != "" && other.Cron == job.Cron && !m.failed[id] {
+			saved.RunAt = other.RunAt
+		}
+		delete(m.failed, id)
+	}
+
+	m.jobs[saved.Id] = &saved
+	return saved.Id, nil
+}
+
+func (m *memoryJobStore) ClaimJobs(_ context.Context, types []string, now time.Time, lease time.Duration, limit int) ([]*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]*Job, 0)
+	for id, job := range m.jobs {
+		if len(jobs) == limit || !slices.Contains(types, job.Type.String()) || job.RunAt.After(now) || m.failed[id] || m.locked[id].After(now) {
+			continue
+		}
+
+		m.locked[id] = now.Add(lease)
+		claimed := *job
+		jobs = append(jobs, &claimed)
+	}
+
+	return jobs, nil
+}
+
+func (m *memoryJobStore) RescheduleJob(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := *job
+	m.jobs[job.Id] = &saved
+	delete(m.locked, job.Id)
+	return nil
+}
+
+func (m *memoryJobStore) FailJob(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := *job
+	m.jobs[job.Id] = &saved
+	m.failed[job.Id] = true
+	delete(m.locked, job.Id)
+	return nil
+}
+
+func (m *memoryJobStore) DeleteJob(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.jobs, id)
+	return nil
+}
+
+func (m *memoryJobStore) DeleteJobByKey(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, job := range m.jobs {
+		if job.Key == key {
+			delete(m.jobs, id)
+		}
+	}
+	return nil
+}
+
+// job returns a copy of the stored job, and whether it failed for good.
+func (m *memoryJobStore) job(id string) (*Job, bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, false, false
+	}
+
+	copied := *job
+	return &copied, m.failed[id], true
+}
+
+func (m *memoryJobStore) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.jobs)
+}
+
+var testJobType = NewIdentifier("test", "jobs/remind")
+
+// newTestScheduler returns a bot with a scheduler on a memory store, running
+// the handler for testJobType jobs.
+func newTestScheduler(t *testing.T, job *JobHandler) (*Bot, *memoryJobStore) {
+	t.Helper()
+
+	store := newMemoryJobStore()
+	bot := newTestBot(t)
+	bot.Scheduler = NewScheduler(store, bot.Session)
+
+	if job.Identifier == nil {
+		job.Identifier = testJobType
+	}
+	if err := RegisterJobs(bot, job); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(bot.Scheduler.Stop)
+
+	return bot, store
+}
+
+func noopJob(_ context.Context, _ *discordgo.Session, _ *Job) error {
+	return nil
+}
+
+func TestScheduleUnknownJob(t *testing.T) {
+	bot, _ := newTestScheduler(t, &JobHandler{Handler: noopJob})
+
+	_, err := bot.Scheduler.Schedule(context.Background(), &JobSpec{Type: NewIdentifier("test", "jobs/unknown")})
+	if !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("got %v, want %v", err, ErrUnknownJob)
+	}
+}
+
+func TestScheduleAttempts(t *testing.T) {
+	bot, store := newTestScheduler(t, &JobHandler{Handler: noopJob, MaxAttempts: 3})
+
+	tests := []struct {
+		spec *JobSpec
+		want int
+	}{
+		{&JobSpec{Type: testJobType, RunAt: time.Now().Add(time.Hour)}, 3},
+		{&JobSpec{Type: testJobType, RunAt: time.Now().Add(time.Hour), MaxAttempts: 7}, 7},
+	}
+
+	for _, test := range tests {
+		id, err := bot.Scheduler.Schedule(context.Background(), test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job, _, _ := store.job(id); job.MaxAttempts != test.want {
+			t.Errorf("got %d attempts, want %d", job.MaxAttempts, test.want)
+		}
+	}
+}
+
+func TestScheduleReplacesKey(t *testing.T) {
+	bot, store := newTestScheduler(t, &JobHandler{Handler: noopJob})
+	ctx := context.Background()
+
+	first, err := bot.Scheduler.Schedule(ctx, &JobSpec{Type: testJobType, Key: "user", RunAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runAt := time.Now().Add(2 * time.Hour)
+	second, err := bot.Scheduler.Schedule(ctx, &JobSpec{Type: testJobType, Key: "user", RunAt: runAt, Payload: map[string]string{"text": "later"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second || store.count() != 1 {
+		t.Fatalf("got jobs %s and %s with %d stored, want the first one replaced", first, second, store.count())
+	}
+
+	job, _, _ := store.job(first)
+	var payload map[string]string
+	if err := job.Bind(&payload); err != nil {
+		t.Fatal(err)
+	}
+	if !job.RunAt.Equal(runAt) || payload["text"] != "later" {
+		t.Errorf("got job %+v, want the second one", job)
+	}
+
+	if err := bot.Scheduler.CancelKey(ctx, "user"); err != nil || store.count() != 0 {
+		t.Errorf("got %v with %d stored, want the job cancelled", err, store.count())
+	}
+}
+
+func TestSchedulerRunsDueJobs(t *testing.T) {
+	useLifecycle(t)
+
+	received := make(chan string, 1)
+	bot, store := newTestScheduler(t, &JobHandler{
+		Handler: func(_ context.Context, _ *discordgo.Session, job *Job) error {
+			var payload map[string]string
+			if err := job.Bind(&payload); err != nil {
+				return err
+			}
+
+			received <- payload["text"]
+			return nil
+		},
+	})
+
+	if err := bot.Scheduler.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bot.Scheduler.Schedule(context.Background(), &JobSpec{Type: testJobType, Payload: map[string]string{"text": "now"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case text := <-received:
+		if text != "now" {
+			t.Errorf("got payload %q", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the due job didn't run")
+	}
+
+	// Finished jobs are deleted once the handler returns
+	deadline := time.Now().Add(time.Second)
+	for store.count() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if store.count() != 0 {
+		t.Error("the finished job is still stored")
+	}
+}
+
+func TestSchedulerStartsRecurringJobs(t *testing.T) {
+	useLifecycle(t)
+	bot, store := newTestScheduler(t, &JobHandler{Handler: noopJob, Cron: "@hourly"})
+
+	if err := bot.Scheduler.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	job, _, ok := store.job(onlyJobId(t, store))
+	if !ok || job.Key != testJobType.String() || job.Cron != "@hourly" || !job.RunAt.After(time.Now()) {
+		t.Errorf("got job %+v, want it at the next hour", job)
+	}
+}
+
+func onlyJobId(t *testing.T, store *memoryJobStore) string {
+	t.Helper()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if len(store.jobs) != 1 {
+		t.Fatalf("got %d jobs, want 1", len(store.jobs))
+	}
+	for id := range store.jobs {
+		return id
+	}
+	return ""
+}
+
+func TestSchedulerComplete(t *testing.T) {
+	failure := errors.New("discord is down")
+
+	tests := []struct {
+		name     string
+		job      Job
+		err      error
+		stored   bool
+		failed   bool
+		attempts int
+		runAt    time.Duration
+	}{
+		{name: "finished", job: Job{MaxAttempts: 3}},
+		{name: "retried", job: Job{MaxAttempts: 3}, err: failure, stored: true, attempts: 1, runAt: 30 * time.Second},
+		{name: "backed off", job: Job{MaxAttempts: 3, Attempts: 1}, err: failure, stored: true, attempts: 2, runAt: time.Minute},
+		{name: "out of attempts", job: Job{MaxAttempts: 3, Attempts: 2}, err: failure, stored: true, failed: true, attempts: 3},
+		{name: "recurring", job: Job{MaxAttempts: 3, Cron: "@hourly"}, stored: true, runAt: time.Hour},
+		{name: "recurring out of attempts", job: Job{MaxAttempts: 3, Attempts: 2, Cron: "@hourly"}, err: failure, stored: true, runAt: time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newMemoryJobStore()
+			scheduler := NewScheduler(store, nil)
+
+			job := test.job
+			job.Id, job.Type = "job", testJobType
+			if _, err := store.SaveJob(context.Background(), &job); err != nil {
+				t.Fatal(err)
+			}
+
+			now := time.Now()
+			scheduler.complete(&job, test.err)
+
+			saved, failed, stored := store.job("job")
+			if stored != test.stored {
+				t.Fatalf("got stored %v, want %v", stored, test.stored)
+			}
+			if !stored {
+				return
+			}
+
+			if failed != test.failed || saved.Attempts != test.attempts {
+				t.Errorf("got failed %v after %d attempts, want %v after %d", failed, saved.Attempts, test.failed, test.attempts)
+			}
+			if test.runAt != 0 && (saved.RunAt.Before(now) || saved.RunAt.After(now.Add(test.runAt+time.Second))) {
+				t.Errorf("got run at %s, want it within %s", saved.RunAt, test.runAt)
+			}
+			if test.err != nil && saved.LastError != test.err.Error() {
+				t.Errorf("got last error %q", saved.LastError)
+			}
+		})
+	}
+}
+
+func TestJobBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		4:  4 * time.Minute,
+		20: time.Hour,
+	}
+
+	for attempts, want := range tests {
+		if got := JobBackoff(attempts); got != want {
+			t.Errorf("attempt %d: got %s, want %s", attempts, got, want)
+		}
+	}
+}
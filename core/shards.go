@@ -0,0 +1,290 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrInvalidShard = errors.New("invalid shard")
+
+// IdentifyInterval is how long Discord wants between identifies of the same
+// rate limit bucket, shards are started in batches of max concurrency.
+var IdentifyInterval = 5 * time.Second
+
+type ShardState string
+
+const (
+	ShardIdle         ShardState = "idle"
+	ShardConnecting   ShardState = "connecting"
+	ShardConnected    ShardState = "connected"
+	ShardReady        ShardState = "ready"
+	ShardDisconnected ShardState = "disconnected"
+)
+
+type ShardStatus struct {
+	Id      int
+	State   ShardState
+	Since   time.Time
+	Latency time.Duration
+	Guilds  int
+}
+
+// ShardManager runs one gateway session per shard of the bot. Handlers and
+// settings apply to every shard, REST only operations can go through any of
+// them as they share the same rate limiter.
+type ShardManager struct {
+	count          int
+	ids            []int
+	maxConcurrency int
+	sessions       map[int]*discordgo.Session
+
+	mu     sync.RWMutex
+	states map[int]*ShardStatus
+}
+
+// NewShardManager creates the sessions of the given shards out of count, all
+// of them when no IDs are given. A count of zero uses the amount of shards
+// recommended by Discord.
+func NewShardManager(token string, count int, ids ...int) (*ShardManager, error) {
+	rest, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrency := 1
+	if count <= 0 {
+		gateway, err := rest.GatewayBot()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the recommended shard count: %w", err)
+		}
+
+		count = gateway.Shards
+		maxConcurrency = max(gateway.SessionStartLimit.MaxConcurrency, 1)
+		log.Info().Msgf("[ShardManager] Using the %d shards recommended by Discord", count)
+	}
+
+	if len(ids) == 0 {
+		for id := 0; id < count; id++ {
+			ids = append(ids, id)
+		}
+	}
+
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	manager := &ShardManager{
+		count:          count,
+		ids:            ids,
+		maxConcurrency: maxConcurrency,
+		sessions:       make(map[int]*discordgo.Session, len(ids)),
+		states:         make(map[int]*ShardStatus, len(ids)),
+	}
+
+	for i, id := range ids {
+		if id < 0 || id >= count {
+			return nil, fmt.Errorf("%w: %d is out of the 0-%d range", ErrInvalidShard, id, count-1)
+		}
+
+		session := rest
+		if i > 0 {
+			if session, err = discordgo.New("Bot " + token); err != nil {
+				return nil, err
+			}
+
+			session.Client = rest.Client
+			session.Ratelimiter = rest.Ratelimiter
+		}
+
+		session.ShardID = id
+		session.ShardCount = count
+
+		manager.sessions[id] = session
+		manager.states[id] = &ShardStatus{Id: id, State: ShardIdle, Since: time.Now()}
+		manager.track(session)
+	}
+
+	return manager, nil
+}
+
+// track keeps the status of the shard up to date.
+func (m *ShardManager) track(session *discordgo.Session) {
+	session.AddHandler(func(s *discordgo.Session, _ *discordgo.Connect) {
+		m.setState(s.ShardID, ShardConnected)
+	})
+	session.AddHandler(func(s *discordgo.Session, _ *discordgo.Ready) {
+		m.setState(s.ShardID, ShardReady)
+	})
+	session.AddHandler(func(s *discordgo.Session, _ *discordgo.Resumed) {
+		m.setState(s.ShardID, ShardReady)
+	})
+	session.AddHandler(func(s *discordgo.Session, _ *discordgo.Disconnect) {
+		m.setState(s.ShardID, ShardDisconnected)
+	})
+}
+
+func (m *ShardManager) setState(id int, state ShardState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if status, ok := m.states[id]; ok && status.State != state {
+		log.Debug().Msgf("[ShardManager] Shard %d is now %s", id, state)
+		status.State = state
+		status.Since = time.Now()
+	}
+}
+
+// Count returns the total amount of shards of the bot, including the ones
+// run by other processes.
+func (m *ShardManager) Count() int {
+	return m.count
+}
+
+// Ids returns the IDs of the shards run by this manager.
+func (m *ShardManager) Ids() []int {
+	return slices.Clone(m.ids)
+}
+
+// Session returns the session of the first shard, for REST only operations.
+func (m *ShardManager) Session() *discordgo.Session {
+	return m.sessions[m.ids[0]]
+}
+
+// Sessions returns the sessions of every shard, ordered by shard ID.
+func (m *ShardManager) Sessions() []*discordgo.Session {
+	sessions := make([]*discordgo.Session, 0, len(m.ids))
+	for _, id := range m.ids {
+		sessions = append(sessions, m.sessions[id])
+	}
+
+	return sessions
+}
+
+// Shard returns the session of the shard, nil if it isn't run by this
+// manager.
+func (m *ShardManager) Shard(id int) *discordgo.Session {
+	return m.sessions[id]
+}
+
+// ShardOf returns the ID of the shard receiving the events of the guild.
+func (m *ShardManager) ShardOf(guildId string) (int, error) {
+	id, err := strconv.ParseUint(guildId, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid guild ID %q", ErrInvalidShard, guildId)
+	}
+
+	return int((id >> 22) % uint64(m.count)), nil
+}
+
+// ForGuild returns the session of the shard holding the state of the guild,
+// nil if it isn't run by this manager.
+func (m *ShardManager) ForGuild(guildId string) *discordgo.Session {
+	id, err := m.ShardOf(guildId)
+	if err != nil {
+		return nil
+	}
+
+	return m.sessions[id]
+}
+
+// Configure applies the settings, e.g. intents, to every shard. Call it
+// before Open.
+func (m *ShardManager) Configure(fn func(s *discordgo.Session)) {
+	for _, id := range m.ids {
+		fn(m.sessions[id])
+	}
+}
+
+// AddHandler adds the event handler to every shard, the returned function
+// removes it.
+func (m *ShardManager) AddHandler(handler any) func() {
+	removes := make([]func(), 0, len(m.ids))
+	for _, id := range m.ids {
+		removes = append(removes, m.sessions[id].AddHandler(handler))
+	}
+
+	return func() {
+		for _, remove := range removes {
+			remove()
+		}
+	}
+}
+
+// Open connects every shard to the gateway, waiting IdentifyInterval between
+// batches so identifies aren't rate limited. Already opened shards are closed
+// if one fails.
+func (m *ShardManager) Open() error {
+	for i, id := range m.ids {
+		if i > 0 && i%m.maxConcurrency == 0 {
+			time.Sleep(IdentifyInterval)
+		}
+
+		m.setState(id, ShardConnecting)
+		log.Info().Msgf("[ShardManager] Opening shard %d of %d", id, m.count)
+
+		if err := m.sessions[id].Open(); err != nil {
+			m.setState(id, ShardDisconnected)
+			m.Close()
+			return fmt.Errorf("shard %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// Close disconnects every shard from the gateway, all at once as closing a
+// session waits for Discord to acknowledge it.
+func (m *ShardManager) Close() error {
+	errs := make([]error, len(m.ids))
+
+	var wg sync.WaitGroup
+	for i, id := range m.ids {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+
+			if err := m.sessions[id].Close(); err != nil {
+				errs[i] = fmt.Errorf("shard %d: %w", id, err)
+			}
+			m.setState(id, ShardDisconnected)
+		}(i, id)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Status returns the status of every shard, ordered by shard ID.
+func (m *ShardManager) Status() []ShardStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]ShardStatus, 0, len(m.ids))
+	for _, id := range m.ids {
+		status := *m.states[id]
+
+		// The heartbeat times are written by the gateway goroutines
+		session := m.sessions[id]
+		session.RLock()
+		if !session.LastHeartbeatSent.IsZero() && !session.LastHeartbeatAck.IsZero() {
+			status.Latency = max(session.HeartbeatLatency(), 0)
+		}
+		session.RUnlock()
+		if session.State != nil {
+			session.State.RLock()
+			status.Guilds = len(session.State.Guilds)
+			session.State.RUnlock()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// guildOnShard returns the ID of a guild whose events go to the shard.
+func guildOnShard(shard, serial int) string {
+	return strconv.FormatUint(uint64(serial*4+shard)<<22, 10)
+}
+
+func newTestShards(t *testing.T) *ShardManager {
+	t.Helper()
+
+	// No gateway request is made with an explicit count
+	shards, err := NewShardManager("token", 4, 3, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return shards
+}
+
+func TestNewShardManager(t *testing.T) {
+	shards := newTestShards(t)
+
+	if ids := shards.Ids(); len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("got shards %v, want [1 3]", ids)
+	}
+	if shards.Count() != 4 || shards.Session() != shards.Shard(1) {
+		t.Errorf("got count %d, want 4 with the first shard for REST", shards.Count())
+	}
+
+	first, second := shards.Shard(1), shards.Shard(3)
+	if first.ShardID != 1 || second.ShardID != 3 || second.ShardCount != 4 {
+		t.Errorf("got shards %d and %d of %d", first.ShardID, second.ShardID, second.ShardCount)
+	}
+	if first.Client != second.Client || first.Ratelimiter != second.Ratelimiter {
+		t.Error("the shards don't share the REST client and rate limiter")
+	}
+
+	if _, err := NewShardManager("token", 4, 4); !errors.Is(err, ErrInvalidShard) {
+		t.Errorf("got %v for an out of range shard, want %v", err, ErrInvalidShard)
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	shards := newTestShards(t)
+
+	for _, shard := range []int{0, 1, 2, 3} {
+		if got, err := shards.ShardOf(guildOnShard(shard, 7)); err != nil || got != shard {
+			t.Errorf("got shard %d and %v, want %d", got, err, shard)
+		}
+	}
+
+	if _, err := shards.ShardOf("guild"); !errors.Is(err, ErrInvalidShard) {
+		t.Errorf("got %v for an invalid guild ID, want %v", err, ErrInvalidShard)
+	}
+
+	if session := shards.ForGuild(guildOnShard(3, 1)); session != shards.Shard(3) {
+		t.Error("got the wrong session for a guild of shard 3")
+	}
+	if session := shards.ForGuild(guildOnShard(2, 1)); session != nil {
+		t.Error("got a session for a guild of a shard run elsewhere")
+	}
+}
+
+func TestShardStatus(t *testing.T) {
+	shards := newTestShards(t)
+	shards.setState(3, ShardReady)
+	shards.Shard(3).State.GuildAdd(&discordgo.Guild{ID: guildOnShard(3, 1)})
+
+	statuses := shards.Status()
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want 2", len(statuses))
+	}
+	if statuses[0].Id != 1 || statuses[0].State != ShardIdle || statuses[0].Latency != 0 {
+		t.Errorf("got status %+v, want the first shard idle", statuses[0])
+	}
+	if statuses[1].Id != 3 || statuses[1].State != ShardReady || statuses[1].Guilds != 1 {
+		t.Errorf("got status %+v, want the second shard ready with a guild", statuses[1])
+	}
+}
+
+func TestSessionFor(t *testing.T) {
+	bot := newTestBot(t)
+	bot.Shards = newTestShards(t)
+
+	if session := bot.SessionFor(guildOnShard(3, 1)); session != bot.Shards.Shard(3) {
+		t.Error("got the wrong session for a guild of shard 3")
+	}
+	if session := bot.SessionFor(guildOnShard(2, 1)); session != bot.Session {
+		t.Error("a guild of a shard run elsewhere didn't fall back to the bot session")
+	}
+	if session := bot.SessionFor(""); session != bot.Session {
+		t.Error("no guild didn't fall back to the bot session")
+	}
+}
+
+func TestPublishUsesShardSession(t *testing.T) {
+	bot := newTestBot(t)
+	bot.Shards = newTestShards(t)
+
+	received := make(chan *discordgo.Session, 1)
+	err := Subscribe(bot, &EventHandler[memberKickedEvent]{
+		Identifier: NewIdentifier("test", "events/kicked"),
+		Handler: func(_ context.Context, s *discordgo.Session, _ *memberKickedEvent) error {
+			received <- s
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Publish(WithEventBus(context.Background(), bot.Events), &memberKickedEvent{GuildId: guildOnShard(3, 1)})
+
+	select {
+	case session := <-received:
+		if session != bot.Shards.Shard(3) {
+			t.Error("the subscriber didn't get the session of the guild's shard")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the event wasn't delivered")
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrHandlerTimeout = errors.New("handler timed out")
+
+// DefaultHandlerTimeout applies to handlers without a timeout of their own.
+var DefaultHandlerTimeout = 30 * time.Second
+
+// AutoDeferAfter is how long an interaction handler can take before it gets
+// deferred, Discord drops interactions not acknowledged within 3 seconds.
+var AutoDeferAfter = 2500 * time.Millisecond
+
+var (
+	handlerTimeoutsMu sync.RWMutex
+	handlerTimeouts   = make(map[string]time.Duration)
+)
+
+// SetHandlerTimeout overrides the timeout of the handler with the given
+// identifier, a negative timeout disables it.
+func SetHandlerTimeout(identifier *Identifier, timeout time.Duration) {
+	handlerTimeoutsMu.Lock()
+	defer handlerTimeoutsMu.Unlock()
+
+	handlerTimeouts[identifier.String()] = timeout
+}
+
+func GetHandlerTimeout(identifier *Identifier) time.Duration {
+	handlerTimeoutsMu.RLock()
+	defer handlerTimeoutsMu.RUnlock()
+
+	if timeout, ok := handlerTimeouts[identifier.String()]; ok {
+		return timeout
+	}
+
+	return DefaultHandlerTimeout
+}
+
+// MidwareTimeout attaches the deadline of the handler with the given
+// identifier to its context. Handlers still running past it get logged, and
+// the ones that give up because of it return an ErrHandlerTimeout error.
+func MidwareTimeout[T any](identifier *Identifier) MiddlewareFunc[T] {
+	return func(next EventFunc[T]) EventFunc[T] {
+		return func(c context.Context, s *discordgo.Session, e *T) error {
+			timeout := GetHandlerTimeout(identifier)
+			if timeout <= 0 {
+				return next(c, s, e)
+			}
+
+			cause := fmt.Errorf("%w: %s exceeded %s", ErrHandlerTimeout, identifier, timeout)
+			ctx, cancel := context.WithTimeoutCause(c, timeout, cause)
+			defer cancel()
+
+			stop := context.AfterFunc(ctx, func() {
+				if errors.Is(context.Cause(ctx), ErrHandlerTimeout) {
+					log.Warn().Msgf("[TimeoutMidware] Handler \"%s\" exceeded its %s deadline!", identifier, timeout)
+				}
+			})
+			defer stop()
+
+			err := next(ctx, s, e)
+			if errors.Is(context.Cause(ctx), ErrHandlerTimeout) && (err == nil || errors.Is(err, context.DeadlineExceeded)) {
+				return context.Cause(ctx)
+			}
+
+			return err
+		}
+	}
+}
+
+// MidwareAutoDefer defers command, component and modal interactions whose
+// handler hasn't returned within AutoDeferAfter, so slow handlers can still
+// answer through their Responder. Components are deferred as an update of
+// their message. Interactions the handler already answered are left
+// untouched.
+func MidwareAutoDefer(flags discordgo.MessageFlags) MiddlewareFunc[discordgo.InteractionCreate] {
+	return func(next EventFunc[discordgo.InteractionCreate]) EventFunc[discordgo.InteractionCreate] {
+		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			switch e.Type {
+			case discordgo.InteractionApplicationCommand, discordgo.InteractionModalSubmit, discordgo.InteractionMessageComponent:
+			default:
+				return next(c, s, e)
+			}
+
+			after := AutoDeferAfter
+			responder := Respond(c, s, e.Interaction)
+			timer := time.AfterFunc(after, func() {
+				err := responder.Defer(flags)
+				if errors.Is(err, ErrAlreadyResponded) {
+					return
+				}
+
+				if err != nil {
+					log.Warn().Err(err).Msg("[AutoDeferMidware] Failed to defer interaction!")
+					return
+				}
+
+				log.Debug().Msgf("[AutoDeferMidware] Deferred interaction %s after %s", e.ID, after)
+			})
+			defer timer.Stop()
+
+			return next(c, s, e)
+		}
+	}
+}
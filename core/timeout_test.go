@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestMidwareTimeout(t *testing.T) {
+	identifier := NewIdentifier("test", "events/slow")
+	SetHandlerTimeout(identifier, 10*time.Millisecond)
+
+	handler := ApplyMiddlewares(func(ctx context.Context, _ *discordgo.Session, _ *discordgo.Ready) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, MidwareTimeout[discordgo.Ready](identifier))
+
+	if err := handler(context.Background(), nil, &discordgo.Ready{}); !errors.Is(err, ErrHandlerTimeout) {
+		t.Errorf("got %v, want %v", err, ErrHandlerTimeout)
+	}
+}
+
+func TestMidwareTimeoutDisabled(t *testing.T) {
+	identifier := NewIdentifier("test", "events/unbounded")
+	SetHandlerTimeout(identifier, -1)
+
+	handler := ApplyMiddlewares(func(ctx context.Context, _ *discordgo.Session, _ *discordgo.Ready) error {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("the handler got a deadline")
+		}
+		return nil
+	}, MidwareTimeout[discordgo.Ready](identifier))
+
+	if err := handler(context.Background(), nil, &discordgo.Ready{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// useAutoDeferAfter shortens the auto defer delay for the test.
+func useAutoDeferAfter(t *testing.T, after time.Duration) {
+	previous := AutoDeferAfter
+	AutoDeferAfter = after
+	t.Cleanup(func() {
+		AutoDeferAfter = previous
+	})
+}
+
+func TestMidwareAutoDeferSlowHandler(t *testing.T) {
+	useAutoDeferAfter(t, 10*time.Millisecond)
+	session, rest := newRestSession(t)
+
+	handler := ApplyMiddlewares(func(_ context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}, MidwareAutoDefer(discordgo.MessageFlagsEphemeral))
+
+	e := commandEvent("1")
+	if err := handler(context.Background(), session, e); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := rest.sent("POST", "/callback")
+	if len(requests) != 1 {
+		t.Fatalf("got %d callbacks, want the deferred one", len(requests))
+	}
+
+	var response discordgo.InteractionResponse
+	if err := requests[0].decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || response.Data == nil || response.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("got callback %+v, want an ephemeral deferred response", response)
+	}
+}
+
+func TestMidwareAutoDeferFastHandler(t *testing.T) {
+	useAutoDeferAfter(t, 50*time.Millisecond)
+	session, rest := newRestSession(t)
+
+	handler := ApplyMiddlewares(func(_ context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) error {
+		return nil
+	}, MidwareAutoDefer(0))
+
+	if err := handler(context.Background(), session, commandEvent("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if requests := rest.sent("POST", "/callback"); len(requests) != 0 {
+		t.Errorf("got %d callbacks for a handler that returned in time", len(requests))
+	}
+}
+
+func TestMidwareAutoDeferAnsweredHandler(t *testing.T) {
+	useAutoDeferAfter(t, 10*time.Millisecond)
+	session, rest := newRestSession(t)
+
+	handler := ApplyMiddlewares(func(_ context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+		err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Content: "pong"},
+		})
+		time.Sleep(100 * time.Millisecond)
+		return err
+	}, MidwareAutoDefer(0))
+
+	e := commandEvent("1")
+	if err := handler(context.Background(), session, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if callbacks := rest.callbacks(t, e.Interaction); len(callbacks) != 2 || callbacks[0] != discordgo.InteractionResponseChannelMessageWithSource {
+		t.Errorf("got callbacks %v, want the reply then the rejected defer", callbacks)
+	}
+}
@@ -0,0 +1,287 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var ErrInvalidCommand = errors.New("invalid command")
+
+// Limits enforced by Discord on application commands.
+const (
+	MaxCommandNameLength        = 32
+	MaxCommandDescriptionLength = 100
+	MaxCommandOptions           = 25
+	MaxCommandChoices           = 25
+	MaxCommandChoiceLength      = 100
+	MaxCommandOptionLength      = 6000
+	MaxCommandTotalLength       = 4000
+)
+
+var commandNamePattern = regexp.MustCompile(`^[-_\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+type commandValidator struct {
+	errs  []error
+	total int
+}
+
+func (v *commandValidator) fail(path string, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%w: %s: %s", ErrInvalidCommand, path, fmt.Sprintf(format, args...)))
+}
+
+// ValidateCommand checks a command against the limits Discord enforces when
+// registering it. Every problem found is returned joined in a single error
+// wrapping ErrInvalidCommand.
+func ValidateCommand(command *discordgo.ApplicationCommand) error {
+	v := &commandValidator{}
+	path := "/" + command.Name
+
+	switch command.Type {
+	case 0, discordgo.ChatApplicationCommand:
+		v.validateChatName(path, command.Name)
+		v.validateDescription(path, command.Description)
+
+		if command.NameLocalizations != nil {
+			for locale, name := range *command.NameLocalizations {
+				v.validateChatName(fmt.Sprintf("%s (%s)", path, locale), name)
+			}
+		}
+
+		if command.DescriptionLocalizations != nil {
+			for locale, description := range *command.DescriptionLocalizations {
+				v.validateDescription(fmt.Sprintf("%s (%s)", path, locale), description)
+			}
+		}
+
+		v.validateOptions(path, command.Options, 0)
+	case discordgo.UserApplicationCommand, discordgo.MessageApplicationCommand:
+		if length := utf8.RuneCountInString(command.Name); length < 1 || length > MaxCommandNameLength {
+			v.fail(path, "name must be between 1 and %d characters", MaxCommandNameLength)
+		}
+
+		if command.Description != "" {
+			v.fail(path, "context menu commands can't have a description")
+		}
+
+		if len(command.Options) > 0 {
+			v.fail(path, "context menu commands can't have options")
+		}
+	default:
+		v.fail(path, "unknown command type %d", command.Type)
+	}
+
+	if v.total > MaxCommandTotalLength {
+		v.fail(path, "names, descriptions and choices add up to %d characters, the limit is %d", v.total, MaxCommandTotalLength)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+func (v *commandValidator) validateChatName(path string, name string) {
+	v.total += utf8.RuneCountInString(name)
+
+	if !commandNamePattern.MatchString(name) {
+		v.fail(path, "name %q must be 1-%d letters, numbers, dashes or underscores", name, MaxCommandNameLength)
+	} else if strings.ToLower(name) != name {
+		v.fail(path, "name %q must be lowercase", name)
+	}
+}
+
+func (v *commandValidator) validateDescription(path string, description string) {
+	length := utf8.RuneCountInString(description)
+	v.total += length
+
+	if length < 1 || length > MaxCommandDescriptionLength {
+		v.fail(path, "description must be between 1 and %d characters", MaxCommandDescriptionLength)
+	}
+}
+
+// depth is 0 for the options of the command, 1 for the ones of a subcommand or
+// group and 2 for the ones of a subcommand inside a group.
+func (v *commandValidator) validateOptions(path string, options []*discordgo.ApplicationCommandOption, depth int) {
+	if len(options) > MaxCommandOptions {
+		v.fail(path, "has %d options, the limit is %d", len(options), MaxCommandOptions)
+	}
+
+	names := make(map[string]bool, len(options))
+	optional := false
+	subcommands, parameters := 0, 0
+
+	for _, option := range options {
+		if option == nil {
+			v.fail(path, "has a nil option")
+			continue
+		}
+
+		optionPath := path + " " + option.Name
+		if names[option.Name] {
+			v.fail(optionPath, "duplicated option name")
+		}
+		names[option.Name] = true
+
+		v.validateChatName(optionPath, option.Name)
+		v.validateDescription(optionPath, option.Description)
+
+		for locale, name := range option.NameLocalizations {
+			v.validateChatName(fmt.Sprintf("%s (%s)", optionPath, locale), name)
+		}
+
+		for locale, description := range option.DescriptionLocalizations {
+			v.validateDescription(fmt.Sprintf("%s (%s)", optionPath, locale), description)
+		}
+
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionSubCommandGroup:
+			subcommands++
+			if depth > 0 {
+				v.fail(optionPath, "subcommand groups can only be declared at the top level")
+			}
+
+			for _, subcommand := range option.Options {
+				if subcommand != nil && subcommand.Type != discordgo.ApplicationCommandOptionSubCommand {
+					v.fail(optionPath+" "+subcommand.Name, "subcommand groups can only contain subcommands")
+				}
+			}
+
+			v.validateOptions(optionPath, option.Options, depth+1)
+		case discordgo.ApplicationCommandOptionSubCommand:
+			subcommands++
+			if depth > 1 {
+				v.fail(optionPath, "subcommands can't be nested deeper than a group")
+			}
+
+			for _, parameter := range option.Options {
+				if parameter != nil && isSubCommandOption(parameter) {
+					v.fail(optionPath+" "+parameter.Name, "subcommands can't contain subcommands or groups")
+				}
+			}
+
+			v.validateOptions(optionPath, option.Options, depth+1)
+		default:
+			parameters++
+			if option.Required && optional {
+				v.fail(optionPath, "required options must be declared before optional ones")
+			}
+			optional = optional || !option.Required
+
+			v.validateParameter(optionPath, option)
+		}
+	}
+
+	if subcommands > 0 && parameters > 0 {
+		v.fail(path, "subcommands and groups can't be mixed with other options")
+	}
+}
+
+func (v *commandValidator) validateParameter(path string, option *discordgo.ApplicationCommandOption) {
+	if len(option.Options) > 0 {
+		v.fail(path, "only subcommands and groups can have nested options")
+	}
+
+	if len(option.ChannelTypes) > 0 && option.Type != discordgo.ApplicationCommandOptionChannel {
+		v.fail(path, "channel types can only be set on channel options")
+	}
+
+	switch option.Type {
+	case discordgo.ApplicationCommandOptionString:
+		if option.MinLength != nil && (*option.MinLength < 0 || *option.MinLength > MaxCommandOptionLength) {
+			v.fail(path, "min length must be between 0 and %d", MaxCommandOptionLength)
+		}
+
+		if option.MaxLength != 0 && (option.MaxLength < 1 || option.MaxLength > MaxCommandOptionLength) {
+			v.fail(path, "max length must be between 1 and %d", MaxCommandOptionLength)
+		}
+
+		if option.MinLength != nil && option.MaxLength != 0 && *option.MinLength > option.MaxLength {
+			v.fail(path, "min length can't be greater than max length")
+		}
+	case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+		if option.MinLength != nil || option.MaxLength != 0 {
+			v.fail(path, "min and max length can only be set on string options")
+		}
+
+		if option.MinValue != nil && option.MaxValue != 0 && *option.MinValue > option.MaxValue {
+			v.fail(path, "min value can't be greater than max value")
+		}
+	default:
+		if option.MinLength != nil || option.MaxLength != 0 {
+			v.fail(path, "min and max length can only be set on string options")
+		}
+
+		if option.MinValue != nil || option.MaxValue != 0 {
+			v.fail(path, "min and max value can only be set on integer and number options")
+		}
+
+		if option.Autocomplete {
+			v.fail(path, "autocomplete can only be enabled on string, integer and number options")
+		}
+
+		if len(option.Choices) > 0 {
+			v.fail(path, "choices can only be set on string, integer and number options")
+		}
+
+		return
+	}
+
+	if option.Autocomplete && len(option.Choices) > 0 {
+		v.fail(path, "autocomplete can't be enabled on an option with choices")
+	}
+
+	if len(option.Choices) > MaxCommandChoices {
+		v.fail(path, "has %d choices, the limit is %d", len(option.Choices), MaxCommandChoices)
+	}
+
+	for _, choice := range option.Choices {
+		v.validateChoice(path, option.Type, choice)
+	}
+}
+
+func (v *commandValidator) validateChoice(path string, optionType discordgo.ApplicationCommandOptionType, choice *discordgo.ApplicationCommandOptionChoice) {
+	if choice == nil {
+		v.fail(path, "has a nil choice")
+		return
+	}
+
+	choicePath := fmt.Sprintf("%s [%s]", path, choice.Name)
+
+	length := utf8.RuneCountInString(choice.Name)
+	v.total += length
+	if length < 1 || length > MaxCommandChoiceLength {
+		v.fail(choicePath, "choice name must be between 1 and %d characters", MaxCommandChoiceLength)
+	}
+
+	for locale, name := range choice.NameLocalizations {
+		if length := utf8.RuneCountInString(name); length < 1 || length > MaxCommandChoiceLength {
+			v.fail(fmt.Sprintf("%s (%s)", choicePath, locale), "choice name must be between 1 and %d characters", MaxCommandChoiceLength)
+		}
+	}
+
+	switch value := choice.Value.(type) {
+	case string:
+		v.total += utf8.RuneCountInString(value)
+		if optionType != discordgo.ApplicationCommandOptionString {
+			v.fail(choicePath, "string choices can only be used on string options")
+		} else if length := utf8.RuneCountInString(value); length < 1 || length > MaxCommandChoiceLength {
+			v.fail(choicePath, "choice value must be between 1 and %d characters", MaxCommandChoiceLength)
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if optionType == discordgo.ApplicationCommandOptionString {
+			v.fail(choicePath, "numeric choices can't be used on string options")
+		}
+	case float32, float64:
+		if optionType != discordgo.ApplicationCommandOptionNumber {
+			v.fail(choicePath, "decimal choices can only be used on number options")
+		}
+	default:
+		v.fail(choicePath, "unsupported choice value type %T", choice.Value)
+	}
+}
+
+func isSubCommandOption(option *discordgo.ApplicationCommandOption) bool {
+	return option.Type == discordgo.ApplicationCommandOptionSubCommand || option.Type == discordgo.ApplicationCommandOptionSubCommandGroup
+}
@@ -0,0 +1 @@
+drop table core_jobs;
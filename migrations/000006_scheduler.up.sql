@@ -0,0 +1,24 @@
+create table core_jobs (
+    -- metadata
+    id varchar(20) primary key,
+    type varchar(255) not null,
+    job_key varchar(255) unique,
+
+    -- job
+    payload jsonb not null default '{}',
+    cron varchar(255),
+    run_at timestamptz not null,
+
+    -- retries
+    attempts integer not null default 0,
+    max_attempts integer not null default 5,
+    last_error text,
+    locked_until timestamptz,
+    failed_at timestamptz,
+
+    -- timestamps
+    created_at timestamptz not null default current_timestamp,
+    updated_at timestamptz not null default current_timestamp
+);
+
+create index core_jobs_run_at on core_jobs (run_at) where failed_at is null;
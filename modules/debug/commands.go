@@ -1,11 +1,13 @@
 package debug
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -13,302 +15,269 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-var ErrorTestCommandPermissions int64 = discordgo.PermissionAdministrator
-
-var ErrorTestCommand = &discordgo.ApplicationCommand{
-	Name:                     "error-test",
-	Description:              "Development command for testing error handling",
-	DefaultMemberPermissions: &ErrorTestCommandPermissions,
-	Options: []*discordgo.ApplicationCommandOption{
-		{
-			Name:        "no-reply",
-			Description: "Throws error before sending a reply.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-		},
-		{
-			Name:        "reply",
-			Description: "Throws error after replying to interaction.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "ephemeral",
-					Description: "Whether or not the reply should be ephemeral.",
-					Type:        discordgo.ApplicationCommandOptionBoolean,
-				},
-			},
-		},
-		{
-			Name:        "defered",
-			Description: "Defer a response before throwing an error.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "ephemeral",
-					Description: "Whether or not the defer should be ephemeral.",
-					Type:        discordgo.ApplicationCommandOptionBoolean,
-				},
-			},
-		},
-		{
-			Name:        "panic",
-			Description: "This will generate a panic in the bot, this option will not reply an error.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-		},
-	},
-}
+var ErrorTestCommand = core.NewCommandBuilder().
+	SetName("error-test").
+	SetDescription("Development command for testing error handling").
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("no-reply").
+			SetDescription("Throws error before sending a reply.")
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("reply").
+			SetDescription("Throws error after replying to interaction.").
+			AddBooleanOption(func(b *core.BooleanOptionBuilder) {
+				b.SetName("ephemeral").
+					SetDescription("Whether or not the reply should be ephemeral.")
+			})
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("defered").
+			SetDescription("Defer a response before throwing an error.").
+			AddBooleanOption(func(b *core.BooleanOptionBuilder) {
+				b.SetName("ephemeral").
+					SetDescription("Whether or not the defer should be ephemeral.")
+			})
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("panic").
+			SetDescription("This will generate a panic in the bot, this option will not reply an error.")
+	}).
+	MustBuild()
 
 var (
-	_ core.EventFunc[discordgo.InteractionCreate] = HandleErrorTestCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleErrorTestNoReplyCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleErrorTestReplyCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleErrorTestDeferedCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleErrorTestPanicCommand
 )
 
-func HandleErrorTestCommand(_ context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	data := e.ApplicationCommandData()
+var ErrErrorTest = errors.New("this is a made up error")
 
-	options := data.Options
-	switch options[0].Name {
-	case "reply":
-		var flags discordgo.MessageFlags
-		if len(options[0].Options) == 0 || options[0].Options[0].BoolValue() {
-			flags |= discordgo.MessageFlagsEphemeral
-		}
+func HandleErrorTestNoReplyCommand(_ context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	return ErrErrorTest
+}
 
-		response := &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags: flags,
-				Embeds: []*discordgo.MessageEmbed{
-					{
-						Title:       "Meow! :3",
-						Color:       core.ColorResult,
-						Description: "This is a funny & quirky response! Totally not going to die in the next 2 nanoseconds. An error is about to occur after this, depending on the handling something might or not happen.",
-					},
-				},
+func HandleErrorTestReplyCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	options := core.GetCommandOptions(e.ApplicationCommandData())
+
+	var flags discordgo.MessageFlags
+	if core.GetBooleanDefaultOption(options, "ephemeral", true) {
+		flags |= discordgo.MessageFlagsEphemeral
+	}
+
+	response := &discordgo.InteractionResponseData{
+		Flags: flags,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Meow! :3",
+				Color:       core.ColorResult,
+				Description: "This is a funny & quirky response! Totally not going to die in the next 2 nanoseconds. An error is about to occur after this, depending on the handling something might or not happen.",
 			},
-		}
+		},
+	}
 
-		if err := s.InteractionRespond(e.Interaction, response); err != nil {
-			return err
-		}
-	case "defered":
-		var flags discordgo.MessageFlags
-		if len(options[0].Options) == 0 || options[0].Options[0].BoolValue() {
-			flags |= discordgo.MessageFlagsEphemeral
-		}
+	if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
+		return err
+	}
 
-		response := &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{},
-		}
+	return ErrErrorTest
+}
 
-		if err := s.InteractionRespond(e.Interaction, response); err != nil {
-			return err
-		}
-	case "panic":
-		response := &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags: discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{
-					{
-						Title:       "Welp this hurts!",
-						Color:       core.ColorResult,
-						Description: "A panic is going to happen in my runtime in the next instants. Please beware that if unhandled correctly this might make me despawn (exit on failure) which wouldn't be optimal.",
-					},
-				},
-			},
-		}
+func HandleErrorTestDeferedCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	options := core.GetCommandOptions(e.ApplicationCommandData())
 
-		if err := s.InteractionRespond(e.Interaction, response); err != nil {
-			return err
-		}
+	var flags discordgo.MessageFlags
+	if core.GetBooleanDefaultOption(options, "ephemeral", true) {
+		flags |= discordgo.MessageFlagsEphemeral
+	}
 
-		panic("This is a fake panic! Comming from error test command.")
+	if err := core.Respond(c, s, e.Interaction).Defer(flags); err != nil {
+		return err
 	}
 
-	return errors.New("this is a made up error")
+	return ErrErrorTest
 }
 
-var PingCommand = &discordgo.ApplicationCommand{
-	Name:        "ping",
-	Description: "Ping the bot to see if it's alive!",
+func HandleErrorTestPanicCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	if err := core.Respond(c, s, e.Interaction).Defer(discordgo.MessageFlagsEphemeral); err != nil {
+		return err
+	}
+
+	panic("This is a fake panic! Comming from error test command.")
 }
 
+var PingCommand = core.NewCommandBuilder().
+	SetName("ping").
+	SetDescription("Ping the bot to see if it's alive!").
+	MustBuild()
+
 var (
 	_ core.EventFunc[discordgo.InteractionCreate] = HandlePingCommand
 )
 
-func HandlePingCommand(_ context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	response := &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags: discordgo.MessageFlagsEphemeral,
-			Embeds: []*discordgo.MessageEmbed{
-				{
-					Title:       "Pong! :3",
-					Color:       core.ColorInfo,
-					Description: fmt.Sprintf("I am alive and well! Server time is <t:%d:f>.", time.Now().Unix()),
-				},
+func HandlePingCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	response := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Pong! :3",
+				Color:       core.ColorInfo,
+				Description: fmt.Sprintf("I am alive and well! Server time is <t:%d:f>.", time.Now().Unix()),
 			},
 		},
 	}
 
-	if err := s.InteractionRespond(e.Interaction, response); err != nil {
+	if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-var FeatureCommandPermissions int64 = discordgo.PermissionAdministrator
-
-var FeatureCommand = &discordgo.ApplicationCommand{
-	Name:                     "feature",
-	Description:              "Manage features for the bot.",
-	DefaultMemberPermissions: &FeatureCommandPermissions,
-	Options: []*discordgo.ApplicationCommandOption{
-		{
-			Name:        "get",
-			Description: "Get the state of a feature.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:         "feature",
-					Description:  "The feature to get the state of.",
-					Type:         discordgo.ApplicationCommandOptionString,
-					Required:     true,
-					Autocomplete: true,
-				},
-			},
-		},
-		{
-			Name:        "set",
-			Description: "Set the state of a feature.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:         "feature",
-					Description:  "The feature to set the state of.",
-					Type:         discordgo.ApplicationCommandOptionString,
-					Required:     true,
-					Autocomplete: true,
-				},
-				{
-					Name:        "state",
-					Description: "The state to set the feature to.",
-					Type:        discordgo.ApplicationCommandOptionBoolean,
-					Required:    true,
-				},
-			},
-		},
-	},
-}
+var FeatureCommand = core.NewCommandBuilder().
+	SetName("feature").
+	SetDescription("Manage features for the bot.").
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("get").
+			SetDescription("Get the state of a feature.").
+			AddStringOption(func(s *core.StringOptionBuilder) {
+				s.SetName("feature").
+					SetDescription("The feature to get the state of.").
+					SetRequired(true).
+					SetAutocomplete(true)
+			})
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("set").
+			SetDescription("Set the state of a feature.").
+			AddStringOption(func(s *core.StringOptionBuilder) {
+				s.SetName("feature").
+					SetDescription("The feature to set the state of.").
+					SetRequired(true).
+					SetAutocomplete(true)
+			}).
+			AddBooleanOption(func(b *core.BooleanOptionBuilder) {
+				b.SetName("state").
+					SetDescription("The state to set the feature to.").
+					SetRequired(true)
+			})
+	}).
+	MustBuild()
 
 var (
-	_ core.EventFunc[discordgo.InteractionCreate] = HandleFeatureCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleFeatureGetCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleFeatureSetCommand
 	_ core.EventFunc[discordgo.InteractionCreate] = HandleFeatureAutocomplete
 )
 
-func HandleFeatureCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	data := e.ApplicationCommandData()
+func HandleFeatureGetCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	fs := core.Use(c, FeatureServiceKey)
 
-	fs, ok := c.Value(FeatureServiceKey).(FeatureService)
-	if !ok {
-		return ErrFeatureServiceNotFound
+	options := core.GetCommandOptions(e.ApplicationCommandData())
+	featureName, err := core.GetStringOption(options, "feature")
+	if err != nil {
+		return err
 	}
 
-	options := data.Options
-	switch options[0].Name {
-	case "get":
-		featureName := options[0].Options[0].StringValue()
-		identifier, err := core.ParseIdentifier(featureName)
-		if err != nil {
-			return err
-		}
+	identifier, err := core.ParseIdentifier(featureName)
+	if err != nil {
+		return err
+	}
 
-		enabled, err := fs.GetFeature(context.Background(), identifier, e.GuildID)
-		if err != nil {
-			if errors.Is(err, ErrFeatureNotRegistered) {
-				response := &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Flags: discordgo.MessageFlagsEphemeral,
-						Embeds: []*discordgo.MessageEmbed{
-							{
-								Title:       "Feature not registered!",
-								Color:       core.ColorError,
-								Description: fmt.Sprintf("The feature `%s` is not registered for this guild.", featureName),
-							},
-						},
+	enabled, err := fs.GetFeature(c, identifier, e.GuildID)
+	if err != nil {
+		if errors.Is(err, ErrFeatureNotRegistered) {
+			response := &discordgo.InteractionResponseData{
+				Flags: discordgo.MessageFlagsEphemeral,
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title:       "Feature not registered!",
+						Color:       core.ColorError,
+						Description: fmt.Sprintf("The feature `%s` is not registered for this guild.", featureName),
 					},
-				}
-
-				if err := s.InteractionRespond(e.Interaction, response); err != nil {
-					return err
-				}
+				},
+			}
 
-				return nil
+			if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
+				return err
 			}
 
-			return err
+			return nil
 		}
 
-		response := &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags: discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{
-					{
-						Title:       "Feature state",
-						Color:       core.ColorInfo,
-						Description: fmt.Sprintf("The feature `%s` is currently %s.", featureName, map[bool]string{true: "enabled", false: "disabled"}[enabled]),
-					},
-				},
+		return err
+	}
+
+	response := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Feature state",
+				Color:       core.ColorInfo,
+				Description: fmt.Sprintf("The feature `%s` is currently %s.", featureName, map[bool]string{true: "enabled", false: "disabled"}[enabled]),
 			},
-		}
+		},
+	}
 
-		if err := s.InteractionRespond(e.Interaction, response); err != nil {
-			return err
-		}
-	case "set":
-		featureName := options[0].Options[0].StringValue()
-		identifier, err := core.ParseIdentifier(featureName)
-		if err != nil {
-			return err
-		}
+	if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
+		return err
+	}
 
-		state := options[0].Options[1].BoolValue()
+	return nil
+}
 
-		if err := fs.SetFeature(context.Background(), identifier, e.GuildID, state); err != nil {
-			return err
-		}
+func HandleFeatureSetCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	fs := core.Use(c, FeatureServiceKey)
 
-		response := &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags: discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{
-					{
-						Title:       "Feature state updated!",
-						Color:       core.ColorSuccess,
-						Description: fmt.Sprintf("The feature `%s` is now %s.", featureName, map[bool]string{true: "enabled", false: "disabled"}[state]),
-					},
-				},
+	options := core.GetCommandOptions(e.ApplicationCommandData())
+	featureName, err := core.GetStringOption(options, "feature")
+	if err != nil {
+		return err
+	}
+
+	identifier, err := core.ParseIdentifier(featureName)
+	if err != nil {
+		return err
+	}
+
+	state, err := core.GetBooleanOption(options, "state")
+	if err != nil {
+		return err
+	}
+
+	if err := fs.SetFeature(c, identifier, e.GuildID, state); err != nil {
+		return err
+	}
+
+	core.Publish(c, &FeatureToggledEvent{
+		GuildId: e.GuildID,
+		Feature: identifier,
+		Enabled: state,
+		UserId:  core.InteractionUserId(e.Interaction),
+	})
+
+	response := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Feature state updated!",
+				Color:       core.ColorSuccess,
+				Description: fmt.Sprintf("The feature `%s` is now %s.", featureName, map[bool]string{true: "enabled", false: "disabled"}[state]),
 			},
-		}
+		},
+	}
 
-		if err := s.InteractionRespond(e.Interaction, response); err != nil {
-			return err
-		}
+	if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func HandleFeatureAutocomplete(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	fs, ok := c.Value(FeatureServiceKey).(FeatureService)
-	if !ok {
-		return ErrFeatureServiceNotFound
-	}
+	fs := core.Use(c, FeatureServiceKey)
 
 	features, err := fs.ListFeatures()
 	if err != nil {
@@ -323,11 +292,101 @@ func HandleFeatureAutocomplete(c context.Context, s *discordgo.Session, e *disco
 		})
 	}
 
-	if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
-		Data: &discordgo.InteractionResponseData{
-			Choices: choices,
+	if err := core.Respond(c, s, e.Interaction).Autocomplete(choices); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var RestartCommand = core.NewCommandBuilder().
+	SetName("restart").
+	SetDescription("Restarts the bot.").
+	MustBuild()
+
+func HandleRestartCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	response := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Restarting...",
+				Color:       core.ColorInfo,
+				Description: "The bot is now restarting. Please wait a moment.",
+			},
 		},
+	}
+
+	if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
+		return err
+	}
+
+	// Log the restart
+	log.Info().Msg("[Debug] Restart command received- Restarting bot...")
+
+	// Shut down gracefully, the exit code makes the supervisor restart the bot
+	core.RequestShutdown(1)
+
+	return nil
+}
+
+var CommandsCommand = core.NewCommandBuilder().
+	SetName("commands").
+	SetDescription("Manage where the bot commands are registered.").
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("sync").
+			SetDescription("Sync the commands to their current scope.")
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("promote").
+			SetDescription("Move the commands from the development guilds to the global scope.")
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("demote").
+			SetDescription("Move the commands from the global scope to a development guild.").
+			AddStringOption(func(s *core.StringOptionBuilder) {
+				s.SetName("guild-id").
+					SetDescription("The id of the guild to move the commands to, defaults to this one.").
+					SetMinLength(17).
+					SetMaxLength(20)
+			})
+	}).
+	MustBuild()
+
+var (
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleCommandsSyncCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleCommandsPromoteCommand
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleCommandsDemoteCommand
+)
+
+func describeCommandScope(stack *core.CommandStack) string {
+	if stack.IsGlobal() {
+		return "globally"
+	}
+
+	guilds := make([]string, 0, len(stack.Guilds()))
+	for _, guildId := range stack.Guilds() {
+		guilds = append(guilds, fmt.Sprintf("`%s`", guildId))
+	}
+
+	return "to the guilds " + strings.Join(guilds, ", ")
+}
+
+func HandleCommandsSyncCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	stack := core.Use(c, CommandStackKey)
+
+	if err := stack.SyncAll(s); err != nil {
+		return err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Commands synced!",
+		Color:       core.ColorSuccess,
+		Description: fmt.Sprintf("%d commands are registered %s.", len(stack.Commands()), describeCommandScope(stack)),
+	}
+
+	if err := core.Respond(c, s, e.Interaction).Edit(&discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
 	}
@@ -335,52 +394,211 @@ func HandleFeatureAutocomplete(c context.Context, s *discordgo.Session, e *disco
 	return nil
 }
 
-var RestartCommand = &discordgo.ApplicationCommand{
-	Name:        "restart",
-	Description: "Restarts the bot.",
-	Version:     "1.0.0",
+func HandleCommandsPromoteCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	stack := core.Use(c, CommandStackKey)
+
+	if err := stack.Promote(s); err != nil {
+		return err
+	}
+
+	log.Info().Msg("[DebugModule] Commands promoted to the global scope")
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Commands promoted!",
+		Color:       core.ColorSuccess,
+		Description: "The commands are now registered globally, it may take a while for them to show up everywhere.",
+	}
+
+	if err := core.Respond(c, s, e.Interaction).Edit(&discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	}); err != nil {
+		return err
+	}
+
+	return nil
 }
 
-func HandleRestartCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	var owners = []string{"556132236697665547", "836684190987583576", "610825796285890581"}
+func HandleCommandsDemoteCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	stack := core.Use(c, CommandStackKey)
 
-	var userId string
-	if e.Member != nil {
-		userId = e.Member.User.ID
-	} else {
-		userId = e.User.ID
+	options := core.GetCommandOptions(e.ApplicationCommandData())
+	guildId := core.GetStringDefaultOption(options, "guild-id", e.GuildID)
+	if guildId == "" {
+		return core.ErrNoGuilds
 	}
 
-	if !slices.Contains(owners, userId) {
-		return errors.New("you are not authorized to use this command")
+	if err := stack.Demote(s, guildId); err != nil {
+		return err
 	}
 
-	response := &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags: discordgo.MessageFlagsEphemeral,
-			Embeds: []*discordgo.MessageEmbed{
-				{
-					Title:       "Restarting...",
-					Color:       core.ColorInfo,
-					Description: "The bot is now restarting. Please wait a moment.",
+	log.Info().Msgf("[DebugModule] Commands demoted to guild %s", guildId)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Commands demoted!",
+		Color:       core.ColorWarning,
+		Description: fmt.Sprintf("The commands are now only registered to the guild `%s`.", guildId),
+	}
+
+	if err := core.Respond(c, s, e.Interaction).Edit(&discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var ShardsCommand = core.NewCommandBuilder().
+	SetName("shards").
+	SetDescription("Show the status of the gateway shards.").
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	MustBuild()
+
+var (
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleShardsCommand
+)
+
+func HandleShardsCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	shards := core.Use(c, ShardManagerKey)
+
+	lines := make([]string, 0, len(shards.Ids()))
+	for _, status := range shards.Status() {
+		line := fmt.Sprintf("`#%d` **%s** since <t:%d:R>, %d guilds", status.Id, status.State, status.Since.Unix(), status.Guilds)
+		if status.Latency > 0 {
+			line += fmt.Sprintf(", %dms", status.Latency.Milliseconds())
+		}
+		if status.Id == s.ShardID {
+			line += " (this shard)"
+		}
+
+		lines = append(lines, line)
+	}
+
+	response := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Shards",
+				Color:       core.ColorInfo,
+				Description: strings.Join(lines, "\n"),
+				Footer: &discordgo.MessageEmbedFooter{
+					Text: fmt.Sprintf("Running %d of %d shards", len(lines), shards.Count()),
 				},
 			},
 		},
 	}
 
-	if err := s.InteractionRespond(e.Interaction, response); err != nil {
+	if err := core.Respond(c, s, e.Interaction).Reply(response); err != nil {
 		return err
 	}
 
-	// Log the restart
-	log.Info().Msg("[Debug] Restart command received- Restarting bot...")
+	return nil
+}
 
-	// Remove all commands
-	core.UnregisterAllCommands(s)
+// HandlersPageSize is how many handlers are listed per page by /debug handlers.
+const HandlersPageSize = 6
+
+var DebugCommand = core.NewCommandBuilder().
+	SetName("debug").
+	SetDescription("Inspect the internals of the bot.").
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("handlers").
+			SetDescription("List the registered handlers with their middlewares and features.").
+			AddStringOption(func(s *core.StringOptionBuilder) {
+				s.SetName("module").
+					SetDescription("Only list the handlers of this module.")
+			}).
+			AddBooleanOption(func(b *core.BooleanOptionBuilder) {
+				b.SetName("json").
+					SetDescription("Attach the full list as JSON instead.")
+			})
+	}).
+	MustBuild()
 
-	// Close the session
-	os.Exit(1)
+var (
+	_ core.EventFunc[discordgo.InteractionCreate] = HandleDebugHandlersCommand
+)
 
-	return nil
+func describeHandler(handler core.HandlerInfo) string {
+	module := handler.Module
+	if module == "" {
+		module = "core"
+	}
+
+	target := "`" + handler.Event + "`"
+	if len(handler.Routes) > 0 {
+		target = "`" + strings.Join(handler.Routes, "`, `") + "`"
+	}
+
+	lines := []string{
+		fmt.Sprintf("**%s** %s (%s) on %s", handler.Identifier, handler.Kind, module, target),
+		"↳ `" + strings.Join(handler.Middlewares, "` → `") + "`",
+	}
+	if handler.Feature != "" {
+		lines = append(lines, fmt.Sprintf("↳ Guarded by the `%s` feature", handler.Feature))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func HandleDebugHandlersCommand(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	registry := core.Use(c, HandlerRegistryKey)
+
+	options := core.GetCommandOptions(e.ApplicationCommandData())
+	module := core.GetStringDefaultOption(options, "module", "")
+
+	handlers := registry.Handlers()
+	if module != "" {
+		handlers = slices.DeleteFunc(handlers, func(handler core.HandlerInfo) bool {
+			return handler.Module != module && !(module == "core" && handler.Module == "")
+		})
+	}
+
+	if len(handlers) == 0 {
+		return core.Respond(c, s, e.Interaction).Edit(&discordgo.WebhookEdit{
+			Embeds: &[]*discordgo.MessageEmbed{
+				{
+					Title:       "Handlers",
+					Color:       core.ColorWarning,
+					Description: fmt.Sprintf("No handler is registered by the module `%s`.", module),
+				},
+			},
+		})
+	}
+
+	if core.GetBooleanDefaultOption(options, "json", false) {
+		var dump bytes.Buffer
+		encoder := json.NewEncoder(&dump)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(handlers); err != nil {
+			return err
+		}
+
+		content := fmt.Sprintf("%d handlers are registered.", len(handlers))
+		return core.Respond(c, s, e.Interaction).Edit(&discordgo.WebhookEdit{
+			Content: &content,
+			Files: []*discordgo.File{
+				{Name: "handlers.json", ContentType: "application/json", Reader: &dump},
+			},
+		})
+	}
+
+	pages := core.ChunkPages(handlers, HandlersPageSize, func(page []core.HandlerInfo, index int) *discordgo.MessageEmbed {
+		blocks := make([]string, 0, len(page))
+		for _, handler := range page {
+			blocks = append(blocks, describeHandler(handler))
+		}
+
+		return &discordgo.MessageEmbed{
+			Title:       "Handlers",
+			Color:       core.ColorInfo,
+			Description: strings.Join(blocks, "\n\n"),
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("%d handlers registered", len(handlers)),
+			},
+		}
+	})
+
+	return core.NewPaginator(pages).Send(c, s, e.Interaction)
 }
@@ -30,37 +30,11 @@ func HandlePongEvent(_ context.Context, s *discordgo.Session, e *discordgo.Messa
 func HandleOnReadyEvent(_ context.Context, s *discordgo.Session, e *discordgo.Ready) error {
 	log.Info().Msgf("[DebugModule] Logged in as %s#%s", e.User.Username, e.User.Discriminator)
 
-	// Liste guilds
-	guilds, err := s.UserGuilds(0, "", "", false)
-	if err != nil {
-		log.Warn().Err(err).Msg("[DebugModule] Failed to list guilds")
+	// Every shard gets its own ready event, listing only its guilds
+	if len(e.Guilds) == 0 {
+		log.Warn().Msgf("[DebugModule] Shard %d isn't connected to any guilds", s.ShardID)
 	} else {
-		if len(guilds) == 0 {
-			log.Warn().Msg("[DebugModule] Not connected to any guilds")
-		}
-
-		guildsGreeting := ""
-		for i, guild := range guilds {
-			if i > 0 {
-				guildsGreeting += ", "
-			}
-
-			guildsGreeting += guild.Name
-		}
-
-		log.Info().Msgf("[DebugModule] Connected to guilds: %s", guildsGreeting)
-	}
-
-	// Register commands
-	err = core.ApplyCommands(
-		PingCommand,
-		FeatureCommand,
-		ErrorTestCommand,
-		RestartCommand,
-	).For(s, "")
-	if err != nil {
-		log.Warn().Err(err).Msg("[DebugModule] Failed to register commands")
-		return err
+		log.Info().Msgf("[DebugModule] Shard %d connected to %d guilds", s.ShardID, len(e.Guilds))
 	}
 
 	// Update status to do not disturb
@@ -81,10 +55,7 @@ func HandleOnReadyEvent(_ context.Context, s *discordgo.Session, e *discordgo.Re
 func HandleFeatureSetupEvent(c context.Context, s *discordgo.Session, e *discordgo.Ready) error {
 	log.Info().Msg("[FeatureServiceSetup] Registering features...")
 
-	fs, ok := c.Value(FeatureServiceKey).(FeatureService)
-	if !ok {
-		return ErrFeatureServiceNotFound
-	}
+	fs := core.Use(c, FeatureServiceKey)
 
 	features, err := fs.ListFeatures()
 	if err != nil {
@@ -92,24 +63,19 @@ func HandleFeatureSetupEvent(c context.Context, s *discordgo.Session, e *discord
 		return err
 	}
 
-	guilds, err := s.UserGuilds(0, "", "", false)
-	if err != nil {
-		log.Warn().Err(err).Msg("[FeatureServiceSetup] Failed to list guilds!")
-		return err
-	}
-
-	for _, guild := range guilds {
+	// Each shard sets up the guilds it receives the events of
+	for _, guild := range e.Guilds {
 		for _, feature := range features {
-			_, err := fs.GetFeature(context.Background(), feature.Identifier, guild.ID)
+			_, err := fs.GetFeature(c, feature.Identifier, guild.ID)
 			if err != nil {
 				if err == ErrFeatureNotRegistered {
-					if err := fs.SetFeature(context.Background(), feature.Identifier, guild.ID, feature.DefaultState); err != nil {
-						log.Warn().Err(err).Msgf("[FeatureServiceSetup] Failed to set default \"%s\" feature state for guild \"%s\" (%s)!", feature.Identifier, guild.Name, guild.ID)
+					if err := fs.SetFeature(c, feature.Identifier, guild.ID, feature.DefaultState); err != nil {
+						log.Warn().Err(err).Msgf("[FeatureServiceSetup] Failed to set default \"%s\" feature state for guild %s!", feature.Identifier, guild.ID)
 					}
 
-					log.Info().Msgf("[FeatureServiceSetup] Set default \"%s\" feature state for guild \"%s\" (%s)!", feature.Identifier, guild.Name, guild.ID)
+					log.Info().Msgf("[FeatureServiceSetup] Set default \"%s\" feature state for guild %s!", feature.Identifier, guild.ID)
 				} else {
-					log.Warn().Err(err).Msgf("[FeatureServiceSetup] Failed to get \"%s\" feature state for guild \"%s\" (%s)!", feature.Identifier, guild.Name, guild.ID)
+					log.Warn().Err(err).Msgf("[FeatureServiceSetup] Failed to get \"%s\" feature state for guild %s!", feature.Identifier, guild.ID)
 				}
 			}
 		}
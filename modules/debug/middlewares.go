@@ -25,11 +25,19 @@ func MidwarePerformance[T any](tag *core.Identifier) core.MiddlewareFunc[T] {
 	}
 }
 
+func contactLine() string {
+	if mention := core.OwnerMention(); mention != "" {
+		return fmt.Sprintf("If this keeps happening contact %s.", mention)
+	}
+
+	return "If this keeps happening contact the bot owners."
+}
+
 func CreateErrorEmbed(err error, id xid.ID) *discordgo.MessageEmbed {
 	return &discordgo.MessageEmbed{
 		Color:       core.ColorError,
 		Title:       "Oh no! :(",
-		Description: "Sorry! An unexpected error occurred while executing this event.\nIf this keeps happening contact <@556132236697665547>.",
+		Description: "Sorry! An unexpected error occurred while executing this event.\n" + contactLine(),
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:  "Error Message",
@@ -56,7 +64,7 @@ func CreateFatalErrorEmbed(id xid.ID) *discordgo.MessageEmbed {
 	return &discordgo.MessageEmbed{
 		Color:       core.ColorError,
 		Title:       "Fatal! -w-",
-		Description: "You have encountered a fatal error! This should never happen.\nIf this keeps happening contact <@556132236697665547>.",
+		Description: "You have encountered a fatal error! This should never happen.\n" + contactLine(),
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "Error ID",
@@ -75,7 +83,7 @@ func CreateFatalErrorEmbed(id xid.ID) *discordgo.MessageEmbed {
 	}
 }
 
-func panicWrap(s *discordgo.Session, e *discordgo.InteractionCreate) {
+func panicWrap(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) {
 	rec := recover()
 	if rec == nil {
 		return
@@ -94,37 +102,26 @@ func panicWrap(s *discordgo.Session, e *discordgo.InteractionCreate) {
 	// Generate embed
 	errorEmbed := CreateFatalErrorEmbed(id)
 
-	// Attempt to reply
-	if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{errorEmbed},
-			Flags:  discordgo.MessageFlagsEphemeral,
-		},
-	}); err == nil { // If reply succesful finish.
+	// Autocomplete interactions can't be replied to with a message
+	if e.Type == discordgo.InteractionApplicationCommandAutocomplete {
 		return
 	}
 
-	// Check for reply
-	res, err := s.InteractionResponse(e.Interaction)
-	if err != nil {
+	// Reply, edit the deferred reply or follow up depending on what was sent
+	responder := core.Respond(c, s, e.Interaction)
+	state := responder.State()
+	if err := responder.Reply(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{errorEmbed},
+		Flags:  discordgo.MessageFlagsEphemeral,
+	}); err != nil || state == core.ResponseUnanswered { // If first reply finish.
 		return
 	}
 
-	if res.Flags&discordgo.MessageFlagsLoading > 0 {
-		_, err = s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
-			Embeds: &[]*discordgo.MessageEmbed{errorEmbed},
-		})
-		if err != nil {
-			return
-		}
-	}
-
 	// Create reader
 	reader := bytes.NewReader(stacktrace[:count])
 
-	s.FollowupMessageCreate(e.Interaction, false, &discordgo.WebhookParams{
-		Flags: res.Flags & discordgo.MessageFlagsEphemeral,
+	responder.Followup(&discordgo.WebhookParams{
+		Flags: responder.Flags() & discordgo.MessageFlagsEphemeral,
 		Files: []*discordgo.File{
 			{
 				Name:        fmt.Sprintf("st-%s.txt", id),
@@ -139,7 +136,7 @@ func panicWrap(s *discordgo.Session, e *discordgo.InteractionCreate) {
 func MidwareErrorWrap(tag *core.Identifier) core.MiddlewareFunc[discordgo.InteractionCreate] {
 	return func(next core.EventFunc[discordgo.InteractionCreate]) core.EventFunc[discordgo.InteractionCreate] {
 		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-			defer panicWrap(s, e)
+			defer panicWrap(c, s, e)
 
 			if err := next(c, s, e); err != nil {
 				// Generate ID
@@ -150,37 +147,18 @@ func MidwareErrorWrap(tag *core.Identifier) core.MiddlewareFunc[discordgo.Intera
 
 				log.Warn().Err(err).Msgf("[ErrorWrapMidware] Caught an error while executing interaction \"%s\"!", tag)
 
-				// Attempt to reply
-				if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Embeds: []*discordgo.MessageEmbed{errorEmbed},
-						Flags:  discordgo.MessageFlagsEphemeral,
-					},
-				}); err == nil { // If reply succesful finish.
+				// Autocomplete interactions can't be replied to with a message
+				if e.Type == discordgo.InteractionApplicationCommandAutocomplete {
 					return nil
 				}
 
-				// Check for reply
-				res, err := s.InteractionResponse(e.Interaction)
-				if err != nil {
-					// Failed to respond
+				// Reply, edit the deferred reply or follow up depending on what was sent
+				if err := core.Respond(c, s, e.Interaction).Reply(&discordgo.InteractionResponseData{
+					Embeds: []*discordgo.MessageEmbed{errorEmbed},
+					Flags:  discordgo.MessageFlagsEphemeral,
+				}); err != nil {
 					return fmt.Errorf("failed to respond to interaction \"%s\" with error: \"%s\"", tag, err.Error())
 				}
-
-				// Edit reply where possible
-				if res.Flags&discordgo.MessageFlagsLoading > 0 {
-					_, err = s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
-						Embeds: &[]*discordgo.MessageEmbed{errorEmbed},
-					})
-					return err
-				}
-
-				// If already replied, send followup
-				s.FollowupMessageCreate(e.Interaction, false, &discordgo.WebhookParams{
-					Flags:  res.Flags & discordgo.MessageFlagsEphemeral,
-					Embeds: []*discordgo.MessageEmbed{errorEmbed},
-				})
 			}
 
 			return nil
@@ -188,79 +166,16 @@ func MidwareErrorWrap(tag *core.Identifier) core.MiddlewareFunc[discordgo.Intera
 	}
 }
 
-func MidwareForCommand(command *discordgo.ApplicationCommand) core.MiddlewareFunc[discordgo.InteractionCreate] {
-	// Register command
+func MidwareDeferResponse(flags discordgo.MessageFlags) core.MiddlewareFunc[discordgo.InteractionCreate] {
 	return func(next core.EventFunc[discordgo.InteractionCreate]) core.EventFunc[discordgo.InteractionCreate] {
 		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+			// Only commands can be deferred, autocompletes must be answered directly
 			if e.Type != discordgo.InteractionApplicationCommand {
-				return nil
-			}
-
-			data := e.ApplicationCommandData()
-
-			// Ignore if not the correct command
-			if data.Name != command.Name {
-				return nil
-			}
-
-			return next(c, s, e)
-		}
-	}
-}
-
-func MidwareForAutocomplete(command *discordgo.ApplicationCommand) core.MiddlewareFunc[discordgo.InteractionCreate] {
-	return func(next core.EventFunc[discordgo.InteractionCreate]) core.EventFunc[discordgo.InteractionCreate] {
-		return func(c context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-			if e.Type != discordgo.InteractionApplicationCommandAutocomplete {
-				return nil
+				return next(c, s, e)
 			}
 
-			data := e.ApplicationCommandData()
-
-			// Ignore if not the correct command
-			if data.Name != command.Name {
-				return nil
-			}
-
-			return next(c, s, e)
-		}
-	}
-}
-
-func MidwareContextInject[T interface{}](key *core.Identifier, value any) core.MiddlewareFunc[T] {
-	return func(next core.EventFunc[T]) core.EventFunc[T] {
-		return func(c context.Context, s *discordgo.Session, e *T) error {
-			c = context.WithValue(c, key, value)
-			return next(c, s, e)
-		}
-	}
-}
-
-func GetGuildFromEvent(event interface{}) string {
-	switch e := event.(type) {
-	case *discordgo.InteractionCreate:
-		return e.GuildID
-	case *discordgo.MessageCreate:
-		return e.GuildID
-	default:
-		return ""
-	}
-}
-
-func MidwareFeatureEnabled[T interface{}](identifier *core.Identifier, service FeatureService) core.MiddlewareFunc[T] {
-	return func(next core.EventFunc[T]) core.EventFunc[T] {
-		return func(c context.Context, s *discordgo.Session, e *T) error {
-			guildId := GetGuildFromEvent(e)
-			if guildId == "" {
-				return fmt.Errorf("failed to determine guild id for event %T", e)
-			}
-
-			if enabled, err := service.GetFeature(context.Background(), identifier, guildId); err != nil || !enabled {
-				if err != nil {
-					log.Warn().Err(err).Msg("[FeatureMidware] Failed to check if feature is enabled!")
-				}
-
-				return nil
+			if err := core.Respond(c, s, e.Interaction).Defer(flags); err != nil {
+				return err
 			}
 
 			return next(c, s, e)
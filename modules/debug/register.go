@@ -5,61 +5,138 @@ import (
 	"github.com/downloadablefox/twotto/core"
 )
 
-func RegisterModule(client *discordgo.Session, featureService FeatureService) {
-	// Add handlers
-	onReadyIdent := core.NewIdentifier("debug", "events/setup")
-	onReady := core.ApplyMiddlewares(
-		HandleOnReadyEvent,
-		MidwarePerformance[discordgo.Ready](onReadyIdent),
-	)
-	client.AddHandler(core.HandleEvent(onReady))
-
-	featureSetupEventIdent := core.NewIdentifier("debug", "events/feature-setup")
-	featureSetupEvent := core.ApplyMiddlewares(
-		HandleFeatureSetupEvent,
-		MidwareContextInject[discordgo.Ready](FeatureServiceKey, featureService),
-		MidwarePerformance[discordgo.Ready](featureSetupEventIdent),
-	)
-	client.AddHandler(core.HandleEvent(featureSetupEvent))
+type Module struct {
+	core.BaseModule
+}
 
-	featureCommandIdent := core.NewIdentifier("debug", "commands/feature")
-	featureCommand := core.ApplyMiddlewares(
-		HandleFeatureCommand,
-		MidwareContextInject[discordgo.InteractionCreate](FeatureServiceKey, featureService),
-		MidwareForCommand(FeatureCommand),
-		MidwareErrorWrap(featureCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(featureCommand))
+func NewModule() *Module {
+	return &Module{}
+}
 
-	featureCommandAutoComplete := core.ApplyMiddlewares(
-		HandleFeatureAutocomplete,
-		MidwareContextInject[discordgo.InteractionCreate](FeatureServiceKey, featureService),
-		MidwareForAutocomplete(FeatureCommand),
-	)
-	client.AddHandler(core.HandleEvent(featureCommandAutoComplete))
+func (m *Module) Name() string {
+	return "debug"
+}
 
+func (m *Module) Commands() []*core.Command {
+	featureCommandIdent := core.NewIdentifier("debug", "commands/feature")
 	pingCommandIdent := core.NewIdentifier("debug", "commands/ping")
-	pingCommand := core.ApplyMiddlewares(
-		HandlePingCommand,
-		MidwareForCommand(PingCommand),
-		MidwareErrorWrap(pingCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(pingCommand))
-
 	errorTestCommandIdent := core.NewIdentifier("debug", "commands/error-test")
-	errorTestCommand := core.ApplyMiddlewares(
-		HandleErrorTestCommand,
-		MidwareForCommand(ErrorTestCommand),
-		MidwareErrorWrap(errorTestCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(errorTestCommand))
-
 	restartCommandIdent := core.NewIdentifier("debug", "commands/restart")
-	restartCommand := core.ApplyMiddlewares(
-		HandleRestartCommand,
-		MidwareForCommand(RestartCommand),
-		MidwareErrorWrap(restartCommandIdent),
-	)
+	commandsCommandIdent := core.NewIdentifier("debug", "commands/commands")
+	shardsCommandIdent := core.NewIdentifier("debug", "commands/shards")
+	debugCommandIdent := core.NewIdentifier("debug", "commands/debug")
+
+	return []*core.Command{
+		{
+			Identifier: featureCommandIdent,
+			Definition: FeatureCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"get": HandleFeatureGetCommand,
+				"set": HandleFeatureSetCommand,
+			},
+			Autocomplete: HandleFeatureAutocomplete,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(discordgo.PermissionAdministrator),
+				MidwareErrorWrap(featureCommandIdent),
+			},
+			Requires: []core.Dependency{FeatureServiceKey},
+		},
+		{
+			Identifier: pingCommandIdent,
+			Definition: PingCommand,
+			Handler:    HandlePingCommand,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				MidwareErrorWrap(pingCommandIdent),
+			},
+		},
+		{
+			Identifier: errorTestCommandIdent,
+			Definition: ErrorTestCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"no-reply": HandleErrorTestNoReplyCommand,
+				"reply":    HandleErrorTestReplyCommand,
+				"defered":  HandleErrorTestDeferedCommand,
+				"panic":    HandleErrorTestPanicCommand,
+			},
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareOwnerOnly(),
+				MidwareErrorWrap(errorTestCommandIdent),
+			},
+		},
+		{
+			Identifier: restartCommandIdent,
+			Definition: RestartCommand,
+			Handler:    HandleRestartCommand,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareOwnerOnly(),
+				MidwareErrorWrap(restartCommandIdent),
+			},
+		},
+		{
+			Identifier: commandsCommandIdent,
+			Definition: CommandsCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"sync":    HandleCommandsSyncCommand,
+				"promote": HandleCommandsPromoteCommand,
+				"demote":  HandleCommandsDemoteCommand,
+			},
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareOwnerOnly(),
+				MidwareDeferResponse(discordgo.MessageFlagsEphemeral),
+				MidwareErrorWrap(commandsCommandIdent),
+			},
+			Requires: []core.Dependency{CommandStackKey},
+		},
+		{
+			Identifier: shardsCommandIdent,
+			Definition: ShardsCommand,
+			Handler:    HandleShardsCommand,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareOwnerOnly(),
+				MidwareErrorWrap(shardsCommandIdent),
+			},
+			Requires: []core.Dependency{ShardManagerKey},
+		},
+		{
+			Identifier: debugCommandIdent,
+			Definition: DebugCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"handlers": HandleDebugHandlersCommand,
+			},
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareOwnerOnly(),
+				MidwareDeferResponse(discordgo.MessageFlagsEphemeral),
+				MidwareErrorWrap(debugCommandIdent),
+			},
+			Requires: []core.Dependency{HandlerRegistryKey},
+		},
+	}
+}
+
+func (m *Module) Init(bot *core.Bot) error {
+	core.ProvideTo(bot.Container, CommandStackKey, bot.Commands)
+	core.ProvideTo(bot.Container, ShardManagerKey, bot.Shards)
+	core.ProvideTo(bot.Container, HandlerRegistryKey, bot.Handlers)
 
-	client.AddHandler(core.HandleEvent(restartCommand))
+	// Add handlers
+	onReadyIdent := core.NewIdentifier("debug", "events/setup")
+	if err := core.AddEventHandler(bot, &core.EventHandler[discordgo.Ready]{
+		Identifier: onReadyIdent,
+		Handler:    HandleOnReadyEvent,
+		Middlewares: []core.MiddlewareFunc[discordgo.Ready]{
+			MidwarePerformance[discordgo.Ready](onReadyIdent),
+		},
+	}); err != nil {
+		return err
+	}
+
+	featureSetupEventIdent := core.NewIdentifier("debug", "events/feature-setup")
+	return core.AddEventHandler(bot, &core.EventHandler[discordgo.Ready]{
+		Identifier: featureSetupEventIdent,
+		Handler:    HandleFeatureSetupEvent,
+		Middlewares: []core.MiddlewareFunc[discordgo.Ready]{
+			MidwarePerformance[discordgo.Ready](featureSetupEventIdent),
+		},
+		Requires: []core.Dependency{FeatureServiceKey},
+	})
 }
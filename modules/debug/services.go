@@ -11,9 +11,11 @@ import (
 
 // Feature is a feature of the debug module
 var (
-	FeatureServiceKey         = core.NewIdentifier("debug", "service/features")
-	ErrFeatureServiceNotFound = errors.New("ledger manager not found in context (missing injection)")
-	ErrFeatureNotRegistered   = errors.New("feature not registered for guild")
+	FeatureServiceKey       = core.NewKey[FeatureService]("debug", "service/features")
+	ErrFeatureNotRegistered = errors.New("feature not registered for guild")
+	CommandStackKey         = core.NewKey[*core.CommandStack]("debug", "service/commands")
+	ShardManagerKey         = core.NewKey[*core.ShardManager]("debug", "service/shards")
+	HandlerRegistryKey      = core.NewKey[*core.HandlerRegistry]("debug", "service/handlers")
 )
 
 type Feature struct {
@@ -21,6 +23,15 @@ type Feature struct {
 	DefaultState bool
 }
 
+// FeatureToggledEvent is published when a user enables or disables a feature
+// for a guild.
+type FeatureToggledEvent struct {
+	GuildId string
+	Feature *core.Identifier
+	Enabled bool
+	UserId  string
+}
+
 type FeatureService interface {
 	RegisterFeature(identifier *core.Identifier, defaultValue bool) error
 	ListFeatures() ([]Feature, error)
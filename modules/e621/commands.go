@@ -9,7 +9,6 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
-	"github.com/rs/zerolog/log"
 )
 
 // Now using the new command builder
@@ -33,12 +32,15 @@ var YiffCommand = core.NewCommandBuilder().
 			AddIntegerOption(func(i *core.IntegerOptionBuilder) {
 				i.SetName("limit").
 					SetDescription("The maximum number of images to return").
-					SetRequired(false)
+					SetRequired(false).
+					SetMin(1).
+					SetMax(320)
 			}).
 			AddIntegerOption(func(i *core.IntegerOptionBuilder) {
 				i.SetName("page").
 					SetDescription("The page to return").
-					SetRequired(false)
+					SetRequired(false).
+					SetMin(1)
 			})
 	}).
 	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
@@ -50,7 +52,7 @@ var YiffCommand = core.NewCommandBuilder().
 					SetRequired(true)
 			})
 	}).
-	Build()
+	MustBuild()
 
 func GeneratePostEmbed(post *E621Post) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
@@ -75,37 +77,9 @@ func GeneratePostEmbed(post *E621Post) *discordgo.MessageEmbed {
 	return embed
 }
 
-func HandleYiffCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	data := e.ApplicationCommandData()
-
-	// Defer the response
-	if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	}); err != nil {
-		return err
-	}
-
-	// Handle the subcommands
-	switch core.GetSubcommandOption(data.Options) {
-	case "random":
-		return handleRandom(ctx, s, e)
-	case "search":
-		return handleSearch(ctx, s, e)
-	case "post":
-		return handlePost(ctx, s, e)
-	case "popular":
-		return handlePopular(ctx, s, e)
-	}
-
-	return nil
-}
-
-func handleRandom(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+func HandleYiffRandomCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
 	// Get E621 service from context
-	svc, ok := ctx.Value(E621ServiceKey).(IE621Service)
-	if !ok || svc == nil {
-		return ErrE621ServiceNotFound
-	}
+	svc := core.Use(ctx, E621ServiceKey)
 
 	// Get the post
 	post, err := svc.GetRandomPost()
@@ -133,7 +107,7 @@ func handleRandom(ctx context.Context, s *discordgo.Session, e *discordgo.Intera
 		Reader: res.Body,
 	}
 
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 		Files:  []*discordgo.File{file},
 	}); err != nil {
@@ -143,86 +117,21 @@ func handleRandom(ctx context.Context, s *discordgo.Session, e *discordgo.Intera
 	return nil
 }
 
-func publishThread(s *discordgo.Session, channelID, messageID, tags string, posts []*E621Post) error {
-	// Assume
-	success := true
-
-	// Create a thread to send the posts
-	thr, err := s.MessageThreadStartComplex(channelID, messageID, &discordgo.ThreadStart{
-		Name:                fmt.Sprintf("Posts with tags `%s`", tags),
-		AutoArchiveDuration: 60, // 1 hour
-		Invitable:           true,
-	})
-	if err != nil {
-		return err
-	}
-
-	// Send the posts
-	for _, post := range posts {
-		s.ChannelTyping(thr.ID)
-
-		embed := GeneratePostEmbed(post)
-		req, err := http.NewRequest(http.MethodGet, post.URL, nil)
-		if err != nil {
-			log.Warn().Err(err).Msgf("[E621YiffCommand] Failed to create request for post #%d (source: %s)", post.ID, post.URL)
-			success = false
-			continue
-		}
-
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			log.Warn().Err(err).Msgf("[E621YiffCommand] Failed to download post #%d (source: %s)", post.ID, post.URL)
-			success = false
-			continue
-		}
-		defer res.Body.Close()
-
-		file := &discordgo.File{
-			Name:   fmt.Sprintf("post-%d.%s", post.ID, post.Ext),
-			Reader: res.Body,
-		}
-
-		if _, err := s.ChannelMessageSendComplex(thr.ID, &discordgo.MessageSend{
-			Embed: embed,
-			Files: []*discordgo.File{file},
-		}); err != nil {
-			log.Warn().Err(err).Msgf("[E621YiffCommand] Failed to send post #%d (source: %s)", post.ID, post.URL)
-			success = false
-			continue
-		}
-	}
-
-	if !success {
-		s.ChannelMessageSendComplex(thr.ID, &discordgo.MessageSend{
-			Embeds: []*discordgo.MessageEmbed{{
-				Title:       "Failed to send some posts!",
-				Description: "There was an issue sending some posts. These posts were omitted from the thread!",
-				Color:       core.ColorWarning,
-			}},
-		})
-	}
-
-	return nil
-}
-
-func handleSearch(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	data := e.ApplicationCommandData().Options[0]
-
+func HandleYiffSearchCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
 	// Get E621 service from context
-	svc, ok := ctx.Value(E621ServiceKey).(IE621Service)
-	if !ok || svc == nil {
-		return ErrE621ServiceNotFound
-	}
+	svc := core.Use(ctx, E621ServiceKey)
 
-	// Get tags
-	tags, err := core.GetStringOption(data.Options, "tags")
-	if err != nil {
+	// Get options
+	var options struct {
+		Tags  string `option:"tags,required"`
+		Limit int    `option:"limit,default=10"`
+		Page  int    `option:"page,default=1"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
 		return err
 	}
 
-	limit := core.GetIntegerDefaultOption(data.Options, "limit", 10)
-	page := core.GetIntegerDefaultOption(data.Options, "page", 1)
-
+	tags, limit, page := options.Tags, options.Limit, options.Page
 	if page <= 0 {
 		return errors.New("`page` param should be > 0")
 	}
@@ -230,7 +139,7 @@ func handleSearch(ctx context.Context, s *discordgo.Session, e *discordgo.Intera
 	startTime := time.Now()
 
 	// Send the looking for posts embed
-	msg, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{{
 			Title:       "Looking for posts...",
 			Description: "Searching for posts (this may take a while) ...",
@@ -248,8 +157,7 @@ func handleSearch(ctx context.Context, s *discordgo.Session, e *discordgo.Intera
 			},
 			Color: core.ColorInfo,
 		}},
-	})
-	if err != nil {
+	}); err != nil {
 		return err
 	}
 
@@ -260,7 +168,7 @@ func handleSearch(ctx context.Context, s *discordgo.Session, e *discordgo.Intera
 
 	if len(posts) == 0 {
 		// Update interaction
-		if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+		if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 			Embeds: &[]*discordgo.MessageEmbed{{
 				Title:       "No posts found!",
 				Description: "No posts were found with the given tags.\n**Note:**Some files may be too large to send (25MB limit).",
@@ -285,85 +193,35 @@ func handleSearch(ctx context.Context, s *discordgo.Session, e *discordgo.Intera
 		return nil
 	}
 
-	// Send the posts to a thread
-	if err := publishThread(s, msg.ChannelID, msg.ID, tags, posts); err != nil {
-		// Operation cancelled
-		s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
-			Embeds: &[]*discordgo.MessageEmbed{{
-				Title:       "Operation cancelled! :(",
-				Description: "There was an issue sending the posts to a thread.",
-				Color:       core.ColorError,
-			}},
+	// Page through the posts
+	pages := make(core.SlicePages, 0, len(posts))
+	for _, post := range posts {
+		embed := GeneratePostEmbed(post)
+		embed.Image = &discordgo.MessageEmbedImage{URL: post.URL}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Tags",
+			Value: fmt.Sprintf("`%s`", tags),
 		})
 
-		return err
-	}
-
-	// Update interaction
-	duration := time.Since(startTime)
-	hours := int(duration.Hours())
-	minutes := int(duration.Minutes()) % 60
-	seconds := int(duration.Seconds()) % 60
-
-	durationStr := ""
-	if hours > 0 {
-		durationStr += fmt.Sprintf("%dh ", hours)
-	}
-
-	if minutes > 0 {
-		durationStr += fmt.Sprintf("%dm ", minutes)
-	}
-
-	if seconds > 0 {
-		durationStr += fmt.Sprintf("%ds", seconds)
-	}
-
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
-		Embeds: &[]*discordgo.MessageEmbed{{
-			Title:       "Posts sent!",
-			Description: "Found posts have been sent below! **Note: **Some files might have been ommited as they were too large to send (25MB limit).",
-			Fields: []*discordgo.MessageEmbedField{
-				{
-					Name:   "Tags",
-					Value:  fmt.Sprintf("`%s`", tags),
-					Inline: false,
-				},
-				{
-					Name:   "Count",
-					Value:  fmt.Sprintf("%d", len(posts)),
-					Inline: true,
-				},
-				{
-					Name:   "Elapsed Time",
-					Value:  durationStr,
-					Inline: true,
-				},
-			},
-			Color: core.ColorSuccess,
-		}},
-	}); err != nil {
-		return err
+		pages = append(pages, embed)
 	}
 
-	return nil
+	return core.NewPaginator(pages).Send(ctx, s, e.Interaction)
 }
 
-func handlePost(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	data := e.ApplicationCommandData().Options[0]
-
+func HandleYiffPostCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
 	// Get E621 service from context
-	svc, ok := ctx.Value(E621ServiceKey).(IE621Service)
-	if !ok || svc == nil {
-		return ErrE621ServiceNotFound
-	}
+	svc := core.Use(ctx, E621ServiceKey)
 
 	// Get the post
-	postID, err := core.GetIntegerOption(data.Options, "id")
-	if err != nil {
+	var options struct {
+		Id int `option:"id,required"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
 		return err
 	}
 
-	post, err := svc.GetPostByID(postID)
+	post, err := svc.GetPostByID(options.Id)
 	if err != nil {
 		return err
 	}
@@ -386,7 +244,7 @@ func handlePost(ctx context.Context, s *discordgo.Session, e *discordgo.Interact
 		Reader: res.Body,
 	}
 
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 		Files:  []*discordgo.File{file},
 	}); err != nil {
@@ -396,12 +254,9 @@ func handlePost(ctx context.Context, s *discordgo.Session, e *discordgo.Interact
 	return nil
 }
 
-func handlePopular(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+func HandleYiffPopularCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
 	// Get E621 service from context
-	svc, ok := ctx.Value(E621ServiceKey).(IE621Service)
-	if !ok || svc == nil {
-		return ErrE621ServiceNotFound
-	}
+	svc := core.Use(ctx, E621ServiceKey)
 
 	// Get the post
 	post, err := svc.GetRandomPost()
@@ -429,7 +284,7 @@ func handlePopular(ctx context.Context, s *discordgo.Session, e *discordgo.Inter
 		Reader: res.Body,
 	}
 
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 		Files:  []*discordgo.File{file},
 	}); err != nil {
@@ -1,26 +1,54 @@
 package e621
 
 import (
+	"time"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 	"github.com/downloadablefox/twotto/modules/debug"
 )
 
-func RegisterModule(client *discordgo.Session, e621Service IE621Service) {
-	// Add handlers
-	onReadyIdent := core.NewIdentifier("e621", "events/setup")
-	onReady := core.ApplyMiddlewares(
-		HandleOnReadyEvent,
-		debug.MidwarePerformance[discordgo.Ready](onReadyIdent),
-	)
-	client.AddHandler(core.HandleEvent(onReady))
+type Module struct {
+	core.BaseModule
+}
 
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) Name() string {
+	return "e621"
+}
+
+func (m *Module) Init(_ *core.Bot) error {
+	return nil
+}
+
+func (m *Module) Commands() []*core.Command {
 	yiffCommandIdent := core.NewIdentifier("e621", "commands/yiff")
-	pingCommand := core.ApplyMiddlewares(
-		HandleYiffCommand,
-		debug.MidwareContextInject[discordgo.InteractionCreate](E621ServiceKey, e621Service),
-		debug.MidwareForCommand(YiffCommand),
-		debug.MidwareErrorWrap(yiffCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(pingCommand))
+
+	return []*core.Command{
+		{
+			Identifier: yiffCommandIdent,
+			Definition: YiffCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"random": HandleYiffRandomCommand,
+				"search": HandleYiffSearchCommand,
+				"post":   HandleYiffPostCommand,
+			},
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				// Every subcommand downloads from e621, search up to a whole page
+				core.MidwareCooldown(yiffCommandIdent,
+					core.PerUser(3, time.Minute),
+					core.PerChannel(10, time.Minute),
+					core.PerGuild(30, time.Minute),
+				),
+				debug.MidwareDeferResponse(0),
+				debug.MidwareErrorWrap(yiffCommandIdent),
+			},
+			Requires: []core.Dependency{E621ServiceKey},
+			// Random and post download the file before uploading it
+			Timeout: 2 * time.Minute,
+		},
+	}
 }
@@ -15,8 +15,7 @@ import (
 )
 
 var (
-	E621ServiceKey         = core.NewIdentifier("e621", "services/client")
-	ErrE621ServiceNotFound = errors.New("e621 service not found")
+	E621ServiceKey = core.NewKey[IE621Service]("e621", "services/client")
 )
 
 const MAX_POST_SIZE = 25 * 1024 * 1024
@@ -2,57 +2,53 @@ package extra
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 )
 
-var (
-	SayCommandDMPermission       = false
-	SayCommandPermissions  int64 = discordgo.PermissionAdministrator
-)
-
-var SayCommand = &discordgo.ApplicationCommand{
-	Name:                     "say",
-	Description:              "Say something as the bot!",
-	DMPermission:             &SayCommandDMPermission,
-	DefaultMemberPermissions: &SayCommandPermissions,
-	Options: []*discordgo.ApplicationCommandOption{
-		{
-			Name:        "message",
-			Description: "The message to say.",
-			Type:        discordgo.ApplicationCommandOptionString,
-			Required:    true,
-		},
-	},
-}
-
-func HandleSayCommand(_ context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+var SayCommand = core.NewCommandBuilder().
+	SetName("say").
+	SetDescription("Say something as the bot!").
+	SetDMPermission(false).
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddStringOption(func(s *core.StringOptionBuilder) {
+		s.SetName("message").
+			SetDescription("The message to say.").
+			SetRequired(true).
+			SetMaxLength(2000)
+	}).
+	MustBuild()
+
+func HandleSayCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
 	// Defers the response
-	if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags: discordgo.MessageFlagsEphemeral,
-		},
-	}); err != nil {
+	if err := core.Respond(ctx, s, e.Interaction).Defer(discordgo.MessageFlagsEphemeral); err != nil {
 		return err
 	}
 
-	data := e.ApplicationCommandData()
+	var options struct {
+		Message string `option:"message,required"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
+		return err
+	}
 
-	if _, err := s.ChannelMessageSend(e.ChannelID, data.Options[0].StringValue()); err != nil {
+	if _, err := s.ChannelMessageSend(e.ChannelID, options.Message); err != nil {
 		return err
 	}
 
 	// Responds to the interaction
 	embed := &discordgo.MessageEmbed{
 		Title:       "Message Sent",
-		Description: data.Options[0].StringValue(),
+		Description: options.Message,
 		Color:       core.ColorSuccess,
 	}
 
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -61,39 +57,170 @@ func HandleSayCommand(_ context.Context, s *discordgo.Session, e *discordgo.Inte
 	return nil
 }
 
-var CreateForumCommandDMPermission = false
-var CreateForumCommandPermissions int64 = discordgo.PermissionAdministrator
-
-var CreateForumCommand = &discordgo.ApplicationCommand{
-	Name:                     "create-forum",
-	Description:              "Creates a forum channel.",
-	DMPermission:             &CreateForumCommandDMPermission,
-	DefaultMemberPermissions: &CreateForumCommandPermissions,
-	Options: []*discordgo.ApplicationCommandOption{
-		{
-			Name:        "name",
-			Description: "The name of the forum.",
-			Type:        discordgo.ApplicationCommandOptionString,
-			Required:    true,
+var (
+	ErrInvalidEmbedColor = errors.New("invalid embed color, expected a hex color like #AE00FF")
+	ErrForeignChannel    = errors.New("the channel isn't in this server")
+)
+
+var SayEmbedCommand = core.NewCommandBuilder().
+	SetName("say-embed").
+	SetDescription("Compose an embed and send it as the bot!").
+	SetDMPermission(false).
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddChannelOption(func(c *core.ChannelOptionBuilder) {
+		c.SetName("channel").
+			SetDescription("The channel to send the embed to, defaults to this one.").
+			AddChannelTypes(discordgo.ChannelTypeGuildText)
+	}).
+	MustBuild()
+
+var SayEmbedModalIdent = core.NewIdentifier("extra", "modals/say-embed")
+
+func HandleSayEmbedCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	var options struct {
+		Channel string `option:"channel"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
+		return err
+	}
+
+	if options.Channel == "" {
+		options.Channel = e.ChannelID
+	}
+
+	// Opens the embed composer, the channel travels in the custom id
+	modal := core.NewModalBuilder().
+		SetCustomId(SayEmbedModalIdent, options.Channel).
+		SetTitle("Compose Embed").
+		AddTextInput(func(t *core.TextInputBuilder) {
+			t.SetCustomId("title").
+				SetLabel("Title").
+				SetMaxLength(256)
+		}).
+		AddTextInput(func(t *core.TextInputBuilder) {
+			t.SetCustomId("description").
+				SetLabel("Description").
+				SetStyle(discordgo.TextInputParagraph).
+				SetRequired(true).
+				SetMaxLength(4000)
+		}).
+		AddTextInput(func(t *core.TextInputBuilder) {
+			t.SetCustomId("color").
+				SetLabel("Color").
+				SetPlaceholder("#AE00FF").
+				SetMaxLength(7)
+		}).
+		AddTextInput(func(t *core.TextInputBuilder) {
+			t.SetCustomId("image").
+				SetLabel("Image URL").
+				SetPlaceholder("https://...")
+		}).
+		AddTextInput(func(t *core.TextInputBuilder) {
+			t.SetCustomId("footer").
+				SetLabel("Footer").
+				SetMaxLength(2048)
+		})
+
+	return core.OpenModal(ctx, s, e.Interaction, modal)
+}
+
+func HandleSayEmbedModal(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	channelId, err := core.CustomIdArg(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	// The custom id comes from the client, only send to channels of this guild
+	channel, err := s.State.Channel(channelId)
+	if err != nil {
+		if channel, err = s.Channel(channelId); err != nil {
+			return err
+		}
+	}
+
+	if channel.GuildID != e.GuildID {
+		return ErrForeignChannel
+	}
+
+	var fields struct {
+		Title       string `modal:"title"`
+		Description string `modal:"description,required"`
+		Color       string `modal:"color"`
+		Image       string `modal:"image"`
+		Footer      string `modal:"footer"`
+	}
+	if err := core.BindModal(e.ModalSubmitData(), &fields); err != nil {
+		return err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fields.Title,
+		Description: fields.Description,
+		Color:       core.ColorResult,
+	}
+
+	if fields.Color != "" {
+		color, err := strconv.ParseUint(strings.TrimPrefix(fields.Color, "#"), 16, 24)
+		if err != nil {
+			return ErrInvalidEmbedColor
+		}
+
+		embed.Color = int(color)
+	}
+
+	if fields.Image != "" {
+		embed.Image = &discordgo.MessageEmbedImage{URL: fields.Image}
+	}
+
+	if fields.Footer != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fields.Footer}
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(channelId, embed); err != nil {
+		return err
+	}
+
+	// Responds to the interaction
+	return core.Respond(ctx, s, e.Interaction).Reply(&discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Embed Sent",
+				Description: fmt.Sprintf("Embed sent to <#%s>.", channelId),
+				Color:       core.ColorSuccess,
+			},
 		},
-	},
+		Flags: discordgo.MessageFlagsEphemeral,
+	})
 }
 
-func HandleCreateForumCommand(_ context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+var CreateForumCommand = core.NewCommandBuilder().
+	SetName("create-forum").
+	SetDescription("Creates a forum channel.").
+	SetDMPermission(false).
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddStringOption(func(s *core.StringOptionBuilder) {
+		s.SetName("name").
+			SetDescription("The name of the forum.").
+			SetRequired(true).
+			SetMaxLength(100)
+	}).
+	MustBuild()
+
+func HandleCreateForumCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
 	// Defers the response
-	if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags: discordgo.MessageFlagsEphemeral,
-		},
-	}); err != nil {
+	if err := core.Respond(ctx, s, e.Interaction).Defer(discordgo.MessageFlagsEphemeral); err != nil {
 		return err
 	}
 
-	data := e.ApplicationCommandData()
+	var options struct {
+		Name string `option:"name,required"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
+		return err
+	}
 
 	// Creates the channel
-	channel, err := s.GuildChannelCreate(e.GuildID, data.Options[0].StringValue(), discordgo.ChannelTypeGuildForum)
+	channel, err := s.GuildChannelCreate(e.GuildID, options.Name, discordgo.ChannelTypeGuildForum)
 	if err != nil {
 		return err
 	}
@@ -105,7 +232,7 @@ func HandleCreateForumCommand(_ context.Context, s *discordgo.Session, e *discor
 		Color:       core.ColorSuccess,
 	}
 
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -6,39 +6,86 @@ import (
 	"github.com/downloadablefox/twotto/modules/debug"
 )
 
-func RegisterModule(client *discordgo.Session, featureService debug.FeatureService) {
-	// Add on ready event
-	onReadyIdent := core.NewIdentifier("extra", "events/setup")
-	onReady := core.ApplyMiddlewares(
-		HandleOnReadyEvent,
-		debug.MidwarePerformance[discordgo.Ready](onReadyIdent),
-	)
-	client.AddHandler(core.HandleEvent(onReady))
-
-	// Add say command
+type Module struct {
+	core.BaseModule
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) Name() string {
+	return "extra"
+}
+
+// Features are stored through the debug module's feature service
+func (m *Module) Dependencies() []string {
+	return []string{"debug"}
+}
+
+func (m *Module) Commands() []*core.Command {
 	sayCommandIdent := core.NewIdentifier("extra", "commands/say")
-	sayCommand := core.ApplyMiddlewares(
-		HandleSayCommand,
-		debug.MidwareForCommand(SayCommand),
-		debug.MidwareErrorWrap(sayCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(sayCommand))
-
-	// Add forum create command
+	sayEmbedCommandIdent := core.NewIdentifier("extra", "commands/say-embed")
 	forumCreateCommandIdent := core.NewIdentifier("extra", "commands/create-forum")
-	forumCreateCommand := core.ApplyMiddlewares(
-		HandleCreateForumCommand,
-		debug.MidwareForCommand(CreateForumCommand),
-		debug.MidwareErrorWrap(forumCreateCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(forumCreateCommand))
+
+	return []*core.Command{
+		{
+			Identifier: sayCommandIdent,
+			Definition: SayCommand,
+			Handler:    HandleSayCommand,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(discordgo.PermissionAdministrator),
+				debug.MidwareErrorWrap(sayCommandIdent),
+			},
+		},
+		{
+			Identifier: sayEmbedCommandIdent,
+			Definition: SayEmbedCommand,
+			Handler:    HandleSayEmbedCommand,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(discordgo.PermissionAdministrator),
+				debug.MidwareErrorWrap(sayEmbedCommandIdent),
+			},
+		},
+		{
+			Identifier: forumCreateCommandIdent,
+			Definition: CreateForumCommand,
+			Handler:    HandleCreateForumCommand,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(discordgo.PermissionManageChannels),
+				debug.MidwareErrorWrap(forumCreateCommandIdent),
+			},
+		},
+	}
+}
+
+func (m *Module) Modals() []*core.Modal {
+	return []*core.Modal{
+		{
+			Identifier: SayEmbedModalIdent,
+			Handler:    HandleSayEmbedModal,
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(discordgo.PermissionAdministrator),
+				debug.MidwareErrorWrap(SayEmbedModalIdent),
+			},
+			DeferFlags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+func (m *Module) Init(bot *core.Bot) error {
+	featureService, err := core.Resolve(bot.Container, debug.FeatureServiceKey)
+	if err != nil {
+		return err
+	}
 
 	// Add twitter link command
 	twitterEmbedEventIdent := core.NewIdentifier("extra", "event/twitter-link")
 	featureService.RegisterFeature(twitterEmbedEventIdent, false)
-	twitterEmbedEvent := core.ApplyMiddlewares(
-		HandleTwitterLinkEvent,
-		debug.MidwareFeatureEnabled[discordgo.MessageCreate](twitterEmbedEventIdent, featureService),
-	)
-	client.AddHandler(core.HandleEvent(twitterEmbedEvent))
+
+	return core.AddEventHandler(bot, &core.EventHandler[discordgo.MessageCreate]{
+		Identifier: twitterEmbedEventIdent,
+		Handler:    HandleTwitterLinkEvent,
+		Feature:    twitterEmbedEventIdent,
+	})
 }
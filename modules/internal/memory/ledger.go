@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/downloadablefox/twotto/modules/ledger"
+)
+
+// ErrNotFound is returned for the rows the fakes don't have.
+var ErrNotFound = errors.New("not found")
+
+// Ledger is a ledger repository keeping everything in memory, it mirrors
+// how the Postgres one behaves.
+type Ledger struct {
+	mu       sync.Mutex
+	settings map[string]*ledger.LedgerSettings
+	messages map[string]*ledger.LedgerMessage
+	contents []*ledger.LedgerContent
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		settings: make(map[string]*ledger.LedgerSettings),
+		messages: make(map[string]*ledger.LedgerMessage),
+	}
+}
+
+func (m *Ledger) GetLedgerSettings(_ context.Context, guildId string) (*ledger.LedgerSettings, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	settings, ok := m.settings[guildId]
+	if !ok {
+		settings = &ledger.LedgerSettings{GuildId: guildId}
+		m.settings[guildId] = settings
+	}
+
+	copied := *settings
+	return &copied, nil
+}
+
+func (m *Ledger) GetAllLedgerSettings(_ context.Context, _ int, _ int) ([]*ledger.LedgerSettings, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	all := make([]*ledger.LedgerSettings, 0, len(m.settings))
+	for _, settings := range m.settings {
+		copied := *settings
+		all = append(all, &copied)
+	}
+
+	return all, nil
+}
+
+func (m *Ledger) CreateLedgerSettings(_ context.Context, settings *ledger.LedgerSettings) error {
+	return m.UpdateLedgerSettings(context.Background(), settings)
+}
+
+func (m *Ledger) UpdateLedgerSettings(_ context.Context, settings *ledger.LedgerSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *settings
+	m.settings[settings.GuildId] = &copied
+	return nil
+}
+
+func (m *Ledger) DeleteLedgerSettings(_ context.Context, guildId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.settings, guildId)
+	return nil
+}
+
+func (m *Ledger) GetMessage(_ context.Context, messageId string) (*ledger.LedgerMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	message, ok := m.messages[messageId]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	copied := *message
+	return &copied, nil
+}
+
+func (m *Ledger) GetMessages(_ context.Context, guildId string, _ int, _ int) ([]*ledger.LedgerMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]*ledger.LedgerMessage, 0)
+	for _, message := range m.messages {
+		if message.GuildId == guildId {
+			copied := *message
+			messages = append(messages, &copied)
+		}
+	}
+
+	return messages, nil
+}
+
+func (m *Ledger) CreateMessage(_ context.Context, message *ledger.LedgerMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *message
+	copied.CreatedAt = time.Now()
+	m.messages[message.MessageId] = &copied
+	return nil
+}
+
+func (m *Ledger) UpdateMessage(ctx context.Context, message *ledger.LedgerMessage) error {
+	m.mu.Lock()
+	stored, ok := m.messages[message.MessageId]
+	if ok {
+		stored.IsDeleted = message.IsDeleted
+		stored.IsEdited = message.IsEdited
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return m.CreateMessage(ctx, message)
+	}
+
+	return nil
+}
+
+func (m *Ledger) DeleteMessage(_ context.Context, messageId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.messages, messageId)
+	return nil
+}
+
+func (m *Ledger) GetMessageContent(_ context.Context, contentId int) (*ledger.LedgerContent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, content := range m.contents {
+		if content.Id == contentId {
+			copied := *content
+			return &copied, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *Ledger) GetMessageContents(_ context.Context, messageId string) ([]*ledger.LedgerContent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	contents := make([]*ledger.LedgerContent, 0)
+	for _, content := range m.contents {
+		if content.MessageId == messageId {
+			copied := *content
+			contents = append(contents, &copied)
+		}
+	}
+
+	return contents, nil
+}
+
+func (m *Ledger) CreateMessageContent(_ context.Context, content *ledger.LedgerContent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *content
+	copied.Id = len(m.contents) + 1
+	copied.CreatedAt = time.Now()
+	m.contents = append(m.contents, &copied)
+	return nil
+}
+
+func (m *Ledger) UpdateMessageContent(_ context.Context, content *ledger.LedgerContent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, stored := range m.contents {
+		if stored.Id == content.Id {
+			copied := *content
+			m.contents[i] = &copied
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (m *Ledger) DeleteMessageContent(_ context.Context, contentId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, content := range m.contents {
+		if content.Id == contentId {
+			m.contents = append(m.contents[:i], m.contents[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (m *Ledger) DeleteMessagesOlderThan(_ context.Context, age time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for id, message := range m.messages {
+		if time.Since(message.CreatedAt) > age {
+			delete(m.messages, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
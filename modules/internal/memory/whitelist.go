@@ -0,0 +1,92 @@
+// Package memory has in-memory implementations of the module repositories
+// and managers, for the module tests.
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/downloadablefox/twotto/modules/whitelist"
+)
+
+// Whitelist is a whitelist manager keeping the whitelist of every guild in
+// memory, the other settings keep their defaults.
+type Whitelist struct {
+	mu    sync.Mutex
+	users map[string][]string
+}
+
+func NewWhitelist() *Whitelist {
+	return &Whitelist{users: make(map[string][]string)}
+}
+
+func (m *Whitelist) Whitelist(_ context.Context, guildId string, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if slices.Contains(m.users[guildId], userId) {
+		return whitelist.ErrWhitelisted
+	}
+
+	m.users[guildId] = append(m.users[guildId], userId)
+	return nil
+}
+
+func (m *Whitelist) Unwhitelist(_ context.Context, guildId string, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !slices.Contains(m.users[guildId], userId) {
+		return whitelist.ErrNotWhitelisted
+	}
+
+	m.users[guildId] = slices.DeleteFunc(m.users[guildId], func(id string) bool { return id == userId })
+	return nil
+}
+
+func (m *Whitelist) IsWhitelisted(_ context.Context, guildId string, userId string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Contains(m.users[guildId], userId)
+}
+
+func (m *Whitelist) GetWhitelist(_ context.Context, guildId string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.users[guildId]), nil
+}
+
+func (m *Whitelist) ClearWhitelist(_ context.Context, guildId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.users, guildId)
+	return nil
+}
+
+func (m *Whitelist) GetDefaultRole(_ context.Context, _ string) string {
+	return ""
+}
+
+func (m *Whitelist) SetDefaultRole(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func (m *Whitelist) GetEnabled(_ context.Context, _ string) bool {
+	return true
+}
+
+func (m *Whitelist) SetEnabled(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func (m *Whitelist) GetRemoveOnBan(_ context.Context, _ string) bool {
+	return false
+}
+
+func (m *Whitelist) SetRemoveOnBan(_ context.Context, _ string, _ bool) error {
+	return nil
+}
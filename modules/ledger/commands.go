@@ -10,63 +10,38 @@ import (
 
 var LedgerCommandPermission int64 = discordgo.PermissionAdministrator
 
-var LedgerCommand = &discordgo.ApplicationCommand{
-	Name:        "ledger",
-	Description: "Manage the ledger module",
-	Options: []*discordgo.ApplicationCommandOption{
-		{
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Name:        "enable",
-			Description: "Enable the ledger module",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionChannel,
-					Name:        "channel",
-					Description: "The channel to log messages to",
-					Required:    true,
-				},
-			},
-		},
-		{
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Name:        "disable",
-			Description: "Disable the ledger module",
-		},
-	},
-}
+var LedgerCommand = core.NewCommandBuilder().
+	SetName("ledger").
+	LocalizeDescription(Messages, "command.description").
+	SetDMPermission(false).
+	SetDefaultMemberPermissions(LedgerCommandPermission).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("enable").
+			LocalizeDescription(Messages, "command.enable.description").
+			AddChannelOption(func(c *core.ChannelOptionBuilder) {
+				c.SetName("channel").
+					LocalizeDescription(Messages, "command.enable.channel.description").
+					SetRequired(true).
+					AddChannelTypes(discordgo.ChannelTypeGuildText)
+			})
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("disable").
+			LocalizeDescription(Messages, "command.disable.description")
+	}).
+	MustBuild()
 
-func HandleLedgerCommand(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	// Defer interaction response
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags: discordgo.MessageFlagsEphemeral,
-		},
-	}); err != nil {
-		return err
+func HandleEnableLedgerCommand(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	var options struct {
+		Channel *discordgo.Channel `option:"channel,required"`
 	}
-
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok || lm == nil {
-		return ErrLedgerManagerNotFound
+	if err := core.BindInteractionOptions(s, i.Interaction, &options); err != nil {
+		return err
 	}
+	channel := options.Channel
 
-	switch i.ApplicationCommandData().Options[0].Name {
-	case "enable":
-		return HandleEnableLedgerCommand(ctx, s, i)
-	case "disable":
-		return HandleDisableLedgerCommand(ctx, s, i)
-	}
-	return nil
-}
-
-func HandleEnableLedgerCommand(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	channel := i.ApplicationCommandData().Options[0].Options[0].ChannelValue(s)
-
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok || lm == nil {
-		return ErrLedgerManagerNotFound
-	}
+	lm := core.Use(ctx, LedgerManagerKey)
+	t := Messages.For(core.InteractionLocale(i.Interaction))
 
 	err := lm.SetLogChannel(ctx, i.GuildID, channel.ID)
 	if err != nil {
@@ -82,12 +57,12 @@ func HandleEnableLedgerCommand(ctx context.Context, s *discordgo.Session, i *dis
 
 	// Respond to interaction
 	embed := &discordgo.MessageEmbed{
-		Title:       "Ledger enabled",
-		Description: "The ledger module has been enabled and messages will now be logged to <#" + channel.ID + ">",
+		Title:       t("enable.title"),
+		Description: t("enable.description", core.Vars{"channel": channel.ID}),
 		Color:       core.ColorSuccess,
 	}
 
-	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+	err = core.Respond(ctx, s, i.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	})
 
@@ -95,10 +70,18 @@ func HandleEnableLedgerCommand(ctx context.Context, s *discordgo.Session, i *dis
 }
 
 func HandleDisableLedgerCommand(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok || lm == nil {
-		return ErrLedgerManagerNotFound
-	}
+	t := Messages.For(core.InteractionLocale(i.Interaction))
+
+	return core.Confirm(ctx, s, i.Interaction, &core.ConfirmPrompt{
+		Title:        t("disable.prompt.title"),
+		Description:  t("disable.prompt.description"),
+		ConfirmLabel: t("disable.prompt.confirm"),
+	}, disableLedger)
+}
+
+func disableLedger(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	lm := core.Use(ctx, LedgerManagerKey)
+	t := Messages.For(core.InteractionLocale(i.Interaction))
 
 	err := lm.SetShouldLog(ctx, i.GuildID, false)
 	if err != nil {
@@ -109,12 +92,12 @@ func HandleDisableLedgerCommand(ctx context.Context, s *discordgo.Session, i *di
 
 	// Respond to interaction
 	embed := &discordgo.MessageEmbed{
-		Title:       "Ledger disabled",
-		Description: "The ledger module has been disabled and messages will no longer be logged",
+		Title:       t("disable.title"),
+		Description: t("disable.description"),
 		Color:       core.ColorWarning,
 	}
 
-	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+	err = core.Respond(ctx, s, i.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	})
 
@@ -5,6 +5,8 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
+	"github.com/downloadablefox/twotto/modules/debug"
+	"github.com/downloadablefox/twotto/modules/whitelist"
 )
 
 func HandleOnMessageCreateEvent(ctx context.Context, s *discordgo.Session, e *discordgo.MessageCreate) error {
@@ -14,10 +16,7 @@ func HandleOnMessageCreateEvent(ctx context.Context, s *discordgo.Session, e *di
 	}
 
 	// Ignore messages from whitelisted users
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok {
-		return ErrLedgerManagerNotFound
-	}
+	lm := core.Use(ctx, LedgerManagerKey)
 
 	// Log message
 	return lm.LogMessageCreate(ctx, e.Message)
@@ -30,10 +29,7 @@ func HandleOnMessageEditEvent(ctx context.Context, s *discordgo.Session, e *disc
 	}
 
 	// Ignore messages from whitelisted users
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok {
-		return ErrLedgerManagerNotFound
-	}
+	lm := core.Use(ctx, LedgerManagerKey)
 
 	// Log message
 	return lm.LogMessageEdit(ctx, e)
@@ -46,26 +42,74 @@ func HandleOnMessageDeleteEvent(ctx context.Context, s *discordgo.Session, e *di
 	}
 
 	// Ignore messages from whitelisted users
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok {
-		return ErrLedgerManagerNotFound
-	}
+	lm := core.Use(ctx, LedgerManagerKey)
 
 	// Log message
 	return lm.LogMessageDelete(ctx, e.Message)
 }
 
-func HandleOnReadyEvent(ctx context.Context, s *discordgo.Session, e *discordgo.Ready) error {
-	// Ignore messages from whitelisted users
-	lm, ok := ctx.Value(LedgerManagerKey).(LedgerManager)
-	if !ok || lm == nil {
-		return ErrLedgerManagerNotFound
-	}
+// logDomainEvent logs the embed to the ledger of the guild, if it's enabled.
+func logDomainEvent(ctx context.Context, s *discordgo.Session, guildId string, embed func(t core.Translator) *discordgo.MessageEmbed) error {
+	lm := core.Use(ctx, LedgerManagerKey)
 
-	// Register slash command
-	if err := core.ApplyCommands(LedgerCommand).For(s, ""); err != nil {
+	shouldLog, err := lm.GetShouldLog(ctx, guildId)
+	if err != nil || !shouldLog {
 		return err
 	}
 
-	return nil
+	t := Messages.For(core.GuildLocale(s, guildId))
+	return lm.LogCustomEvent(ctx, guildId, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed(t)},
+	})
+}
+
+func HandleMemberKickedEvent(ctx context.Context, s *discordgo.Session, e *whitelist.MemberKickedEvent) error {
+	return logDomainEvent(ctx, s, e.GuildId, func(t core.Translator) *discordgo.MessageEmbed {
+		return &discordgo.MessageEmbed{
+			Title:       t("log.kicked.title"),
+			Description: t("log.kicked.description", core.Vars{"user": e.User.ID}),
+			Color:       core.ColorWarning,
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:  t("log.author-id"),
+					Value: "`" + e.User.ID + "` / `" + e.User.String() + "`",
+				},
+			},
+		}
+	})
+}
+
+func HandleMemberWhitelistedEvent(ctx context.Context, s *discordgo.Session, e *whitelist.MemberWhitelistedEvent) error {
+	return logDomainEvent(ctx, s, e.GuildId, func(t core.Translator) *discordgo.MessageEmbed {
+		return &discordgo.MessageEmbed{
+			Title:       t("log.whitelisted.title"),
+			Description: t("log.whitelisted.description", core.Vars{"user": e.UserId, "moderator": e.ModeratorId}),
+			Color:       core.ColorSuccess,
+		}
+	})
+}
+
+func HandleMemberUnwhitelistedEvent(ctx context.Context, s *discordgo.Session, e *whitelist.MemberUnwhitelistedEvent) error {
+	return logDomainEvent(ctx, s, e.GuildId, func(t core.Translator) *discordgo.MessageEmbed {
+		return &discordgo.MessageEmbed{
+			Title:       t("log.unwhitelisted.title"),
+			Description: t("log.unwhitelisted.description", core.Vars{"user": e.UserId, "moderator": e.ModeratorId}),
+			Color:       core.ColorWarning,
+		}
+	})
+}
+
+func HandleFeatureToggledEvent(ctx context.Context, s *discordgo.Session, e *debug.FeatureToggledEvent) error {
+	return logDomainEvent(ctx, s, e.GuildId, func(t core.Translator) *discordgo.MessageEmbed {
+		key := "log.feature.disabled"
+		if e.Enabled {
+			key = "log.feature.enabled"
+		}
+
+		return &discordgo.MessageEmbed{
+			Title:       t("log.feature.title"),
+			Description: t(key, core.Vars{"feature": e.Feature.String(), "user": e.UserId}),
+			Color:       core.ColorInfo,
+		}
+	})
 }
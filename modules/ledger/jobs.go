@@ -0,0 +1,25 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
+	"github.com/rs/zerolog/log"
+)
+
+// HandleRetentionJob purges the logged messages older than the retention.
+func HandleRetentionJob(retention time.Duration) core.EventFunc[core.Job] {
+	return func(ctx context.Context, _ *discordgo.Session, _ *core.Job) error {
+		ledgerManager := core.Use(ctx, LedgerManagerKey)
+
+		purged, err := ledgerManager.PurgeMessages(ctx, retention)
+		if err != nil {
+			return err
+		}
+
+		log.Info().Msgf("[Ledger] Purged %d messages older than %s", purged, retention)
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package ledger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
+	"github.com/downloadablefox/twotto/core/discordtest"
+	"github.com/downloadablefox/twotto/modules/internal/memory"
+	"github.com/downloadablefox/twotto/modules/ledger"
+)
+
+func TestLedgerLogsDeletedMessages(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	// Handle events in order, so the message is logged before its deletion
+	session := server.Session()
+	session.SyncEvents = true
+
+	repo := memory.NewLedger()
+	discordtest.LoadModules(t, session, func(bot *core.Bot) {
+		core.ProvideTo(bot.Container, ledger.LedgerManagerKey, ledger.NewRepoLedgerManager(repo, session))
+	}, ledger.NewModule(0))
+
+	guild := server.AddGuild(&discordgo.Guild{Name: "Den"})
+	channel := server.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "general", Type: discordgo.ChannelTypeGuildText})
+	logs := server.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "logs", Type: discordgo.ChannelTypeGuildText})
+	author := server.AddUser(&discordgo.User{Username: "fox"})
+
+	err := repo.UpdateLedgerSettings(context.Background(), &ledger.LedgerSettings{GuildId: guild.ID, Enabled: true, LogChannelId: logs.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	created := server.MessageCreate(&discordgo.Message{GuildID: guild.ID, ChannelID: channel.ID, Author: author, Content: "hello there"})
+	if err := server.Dispatch(created); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Dispatch(server.MessageDelete(channel.ID, created.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	call, err := server.WaitFor("POST", "/channels/"+logs.ID+"/messages", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent discordgo.MessageSend
+	if err := call.Decode(&sent); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent.Embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(sent.Embeds))
+	}
+
+	embed := sent.Embeds[0]
+	if embed.Title != "Message Deleted" {
+		t.Errorf("got title %q, want Message Deleted", embed.Title)
+	}
+	if len(embed.Fields) < 3 || embed.Fields[0].Value != "hello there" || embed.Fields[2].Value != author.Mention() {
+		t.Errorf("got fields %+v, want the content and the author of the message", embed.Fields)
+	}
+
+	if message, err := repo.GetMessage(context.Background(), created.ID); err != nil || !message.IsDeleted {
+		t.Errorf("got stored message %+v (%v), want it marked as deleted", message, err)
+	}
+}
+
+func TestLedgerIgnoresDisabledGuilds(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	session := server.Session()
+	repo := memory.NewLedger()
+	manager := ledger.NewRepoLedgerManager(repo, session)
+
+	guild := server.AddGuild(&discordgo.Guild{Name: "Den"})
+	channel := server.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "general", Type: discordgo.ChannelTypeGuildText})
+
+	created := server.MessageCreate(&discordgo.Message{GuildID: guild.ID, ChannelID: channel.ID, Content: "hello there"})
+	if err := manager.LogMessageCreate(context.Background(), created.Message); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := server.MessageDelete(channel.ID, created.ID)
+	if err := manager.LogMessageDelete(context.Background(), deleted.Message); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.GetMessage(context.Background(), created.ID); !errors.Is(err, memory.ErrNotFound) {
+		t.Errorf("got %v, want the message not stored", err)
+	}
+	if calls := server.Calls(); len(calls) != 0 {
+		t.Errorf("got calls %+v, want none", calls)
+	}
+}
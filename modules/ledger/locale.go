@@ -0,0 +1,12 @@
+package ledger
+
+import (
+	"embed"
+
+	"github.com/downloadablefox/twotto/core"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var Messages = core.MustLoadCatalog(locales, "locales/*.json")
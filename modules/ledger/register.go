@@ -1,44 +1,119 @@
 package ledger
 
 import (
+	"time"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 	"github.com/downloadablefox/twotto/modules/debug"
+	"github.com/downloadablefox/twotto/modules/whitelist"
 )
 
-func RegisterModule(client *discordgo.Session, ledger LedgerManager) {
-	createMessage := core.ApplyMiddlewares(
-		HandleOnMessageCreateEvent,
-		debug.MidwareContextInject[discordgo.MessageCreate](LedgerManagerKey, ledger),
-	)
-	client.AddHandler(core.HandleEvent(createMessage))
-
-	editMessage := core.ApplyMiddlewares(
-		HandleOnMessageEditEvent,
-		debug.MidwareContextInject[discordgo.MessageUpdate](LedgerManagerKey, ledger),
-	)
-	client.AddHandler(core.HandleEvent(editMessage))
-
-	deleteMessage := core.ApplyMiddlewares(
-		HandleOnMessageDeleteEvent,
-		debug.MidwareContextInject[discordgo.MessageDelete](LedgerManagerKey, ledger),
-	)
-	client.AddHandler(core.HandleEvent(deleteMessage))
-
-	onReadyIdent := core.NewIdentifier("ledger", "event/setup")
-	onReady := core.ApplyMiddlewares(
-		HandleOnReadyEvent,
-		debug.MidwareContextInject[discordgo.Ready](LedgerManagerKey, ledger),
-		debug.MidwarePerformance[discordgo.Ready](onReadyIdent),
-	)
-	client.AddHandler(core.HandleEvent(onReady))
+type Module struct {
+	core.BaseModule
+	retention time.Duration
+}
 
+// NewModule returns the ledger module, logged messages older than the
+// retention get purged every day. They are kept forever when it's zero.
+func NewModule(retention time.Duration) *Module {
+	return &Module{retention: retention}
+}
+
+func (m *Module) Name() string {
+	return "ledger"
+}
+
+func (m *Module) Commands() []*core.Command {
 	ledgerCommandIdent := core.NewIdentifier("ledger", "commands/ledger")
-	ledgerCommand := core.ApplyMiddlewares(
-		HandleLedgerCommand,
-		debug.MidwareContextInject[discordgo.InteractionCreate](LedgerManagerKey, ledger),
-		debug.MidwareForCommand(LedgerCommand),
-		debug.MidwareErrorWrap(ledgerCommandIdent),
-	)
-	client.AddHandler(core.HandleEvent(ledgerCommand))
+
+	return []*core.Command{
+		{
+			Identifier: ledgerCommandIdent,
+			Definition: LedgerCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"enable":  HandleEnableLedgerCommand,
+				"disable": HandleDisableLedgerCommand,
+			},
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(LedgerCommandPermission),
+				debug.MidwareDeferResponse(discordgo.MessageFlagsEphemeral),
+				debug.MidwareErrorWrap(ledgerCommandIdent),
+			},
+			Requires: []core.Dependency{LedgerManagerKey},
+		},
+	}
+}
+
+func (m *Module) Jobs() []*core.JobHandler {
+	if m.retention <= 0 {
+		return nil
+	}
+
+	return []*core.JobHandler{
+		{
+			Identifier: core.NewIdentifier("ledger", "jobs/retention"),
+			Handler:    HandleRetentionJob(m.retention),
+			Requires:   []core.Dependency{LedgerManagerKey},
+			Timeout:    5 * time.Minute,
+			Cron:       "0 4 * * *",
+		},
+	}
+}
+
+func (m *Module) Init(bot *core.Bot) error {
+	if err := core.AddEventHandler(bot, &core.EventHandler[discordgo.MessageCreate]{
+		Identifier: core.NewIdentifier("ledger", "events/message-create"),
+		Handler:    HandleOnMessageCreateEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	if err := core.AddEventHandler(bot, &core.EventHandler[discordgo.MessageUpdate]{
+		Identifier: core.NewIdentifier("ledger", "events/message-edit"),
+		Handler:    HandleOnMessageEditEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	if err := core.AddEventHandler(bot, &core.EventHandler[discordgo.MessageDelete]{
+		Identifier: core.NewIdentifier("ledger", "events/message-delete"),
+		Handler:    HandleOnMessageDeleteEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	// Events published by other modules
+	if err := core.Subscribe(bot, &core.EventHandler[whitelist.MemberKickedEvent]{
+		Identifier: core.NewIdentifier("ledger", "events/member-kicked"),
+		Handler:    HandleMemberKickedEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	if err := core.Subscribe(bot, &core.EventHandler[whitelist.MemberWhitelistedEvent]{
+		Identifier: core.NewIdentifier("ledger", "events/member-whitelisted"),
+		Handler:    HandleMemberWhitelistedEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	if err := core.Subscribe(bot, &core.EventHandler[whitelist.MemberUnwhitelistedEvent]{
+		Identifier: core.NewIdentifier("ledger", "events/member-unwhitelisted"),
+		Handler:    HandleMemberUnwhitelistedEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	return core.Subscribe(bot, &core.EventHandler[debug.FeatureToggledEvent]{
+		Identifier: core.NewIdentifier("ledger", "events/feature-toggled"),
+		Handler:    HandleFeatureToggledEvent,
+		Requires:   []core.Dependency{LedgerManagerKey},
+	})
 }
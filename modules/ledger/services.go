@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
@@ -13,8 +14,7 @@ import (
 )
 
 var (
-	LedgerManagerKey         = core.NewIdentifier("ledger", "service/manager")
-	ErrLedgerManagerNotFound = errors.New("ledger manager not found in context (missing injection)")
+	LedgerManagerKey = core.NewKey[LedgerManager]("ledger", "service/manager")
 )
 
 type LedgerRepository interface {
@@ -33,6 +33,7 @@ type LedgerRepository interface {
 	CreateMessageContent(ctx context.Context, content *LedgerContent) error
 	UpdateMessageContent(ctx context.Context, content *LedgerContent) error
 	DeleteMessageContent(ctx context.Context, contentId int) error
+	DeleteMessagesOlderThan(ctx context.Context, age time.Duration) (int64, error)
 }
 
 type LedgerPostgresRepository struct {
@@ -274,6 +275,37 @@ func (r *LedgerPostgresRepository) DeleteMessageContent(ctx context.Context, con
 	return err
 }
 
+// DeleteMessagesOlderThan deletes the messages logged more than age ago along
+// with their contents, it returns how many messages were deleted.
+func (r *LedgerPostgresRepository) DeleteMessagesOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM ledger_contents
+		WHERE message_id IN (
+			SELECT message_id
+			FROM ledger_messages
+			WHERE created_at < current_timestamp - make_interval(secs => $1)
+		)
+	`, age.Seconds()); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM ledger_messages
+		WHERE created_at < current_timestamp - make_interval(secs => $1)
+	`, age.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), tx.Commit(ctx)
+}
+
 type LedgerManager interface {
 	GetShouldLog(ctx context.Context, guildId string) (bool, error)
 	SetShouldLog(ctx context.Context, guildId string, shouldLog bool) error
@@ -283,6 +315,7 @@ type LedgerManager interface {
 	LogMessageDelete(ctx context.Context, message *discordgo.Message) error
 	LogMessageEdit(ctx context.Context, message *discordgo.MessageUpdate) error
 	LogCustomEvent(ctx context.Context, guildId string, data *discordgo.MessageSend) error
+	PurgeMessages(ctx context.Context, olderThan time.Duration) (int64, error)
 }
 
 type RepoLedgerManager struct {
@@ -335,6 +368,10 @@ func (m *RepoLedgerManager) SetLogChannel(ctx context.Context, guildId string, c
 	return m.repo.UpdateLedgerSettings(ctx, settings)
 }
 
+func (m *RepoLedgerManager) PurgeMessages(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return m.repo.DeleteMessagesOlderThan(ctx, olderThan)
+}
+
 func (m *RepoLedgerManager) LogCustomEvent(ctx context.Context, guildId string, data *discordgo.MessageSend) error {
 	// Get log channel
 	channelId, err := m.GetLogChannel(ctx, guildId)
@@ -428,7 +465,9 @@ func (m *RepoLedgerManager) LogMessageDelete(ctx context.Context, message *disco
 		return err
 	}
 
-	messageContent := "`no content saved in database`"
+	t := Messages.For(core.GuildLocale(m.session, messageData.GuildId))
+
+	messageContent := t("log.no-content")
 	if len(content) > 0 {
 		messageContent = content[len(content)-1].Content
 	}
@@ -446,29 +485,29 @@ func (m *RepoLedgerManager) LogMessageDelete(ctx context.Context, message *disco
 
 	// Send message to log channel
 	embed := &discordgo.MessageEmbed{
-		Title: "Message Deleted",
+		Title: t("log.deleted.title"),
 		Color: core.ColorError,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:  "Content",
+				Name:  t("log.content"),
 				Value: messageContent,
 			},
 			{
-				Name:  "URL",
+				Name:  t("log.url"),
 				Value: fmt.Sprintf("https://discord.com/channels/%s/%s/%s", messageData.GuildId, messageData.ChannelId, messageData.UserId),
 			},
 			{
-				Name:   "Author Mention",
+				Name:   t("log.author-mention"),
 				Value:  author.Mention(),
 				Inline: true,
 			},
 			{
-				Name:   "Author ID/Tag",
+				Name:   t("log.author-id"),
 				Value:  fmt.Sprintf("`%s` /\n `%s`", author.ID, author.String()),
 				Inline: true,
 			},
 			{
-				Name:   "Channel",
+				Name:   t("log.channel"),
 				Value:  "<#" + messageData.ChannelId + ">",
 				Inline: true,
 			},
@@ -507,7 +546,9 @@ func (m *RepoLedgerManager) LogMessageEdit(ctx context.Context, message *discord
 		return err
 	}
 
-	previous := "`no content saved in database`"
+	t := Messages.For(core.GuildLocale(m.session, message.GuildID))
+
+	previous := t("log.no-content")
 	if len(contents) > 0 {
 		previous = contents[len(contents)-1].Content
 	}
@@ -544,33 +585,33 @@ func (m *RepoLedgerManager) LogMessageEdit(ctx context.Context, message *discord
 
 	// Send message to log channel
 	embed := &discordgo.MessageEmbed{
-		Title: "Message Edited",
+		Title: t("log.edited.title"),
 		Color: core.ColorInfo,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:  "Content",
+				Name:  t("log.content"),
 				Value: message.Content,
 			},
 			{
-				Name:  "Previous Content",
+				Name:  t("log.previous-content"),
 				Value: previous,
 			},
 			{
-				Name:  "URL",
+				Name:  t("log.url"),
 				Value: fmt.Sprintf("https://discord.com/channels/%s/%s/%s", message.GuildID, message.ChannelID, message.ID),
 			},
 			{
-				Name:   "Author Mention",
+				Name:   t("log.author-mention"),
 				Value:  message.Author.Mention(),
 				Inline: true,
 			},
 			{
-				Name:   "Author ID/Tag",
+				Name:   t("log.author-id"),
 				Value:  fmt.Sprintf("`%s` /\n `%s`", message.Author.ID, message.Author.String()),
 				Inline: true,
 			},
 			{
-				Name:   "Channel",
+				Name:   t("log.channel"),
 				Value:  "<#" + message.ChannelID + ">",
 				Inline: true,
 			},
@@ -4,15 +4,12 @@ import (
 	"context"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/gofiber/fiber/v2"
+	"github.com/downloadablefox/twotto/core"
 	"github.com/rs/zerolog/log"
 )
 
 func HandleOnReadyEvent(ctx context.Context, s *discordgo.Session, e *discordgo.Ready) error {
-	fiber, ok := ctx.Value(FiberServerKey).(*fiber.App)
-	if !ok {
-		return ErrFiberNotFound
-	}
+	fiber := core.Use(ctx, FiberServerKey)
 
 	// start fiber server
 	go func() {
@@ -1,26 +1,58 @@
 package remote
 
 import (
+	"context"
+	"sync"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 	"github.com/downloadablefox/twotto/modules/debug"
 	"github.com/gofiber/fiber/v2"
 )
 
-func RegisterModule(client *discordgo.Session, web *fiber.App) error {
+type Module struct {
+	core.BaseModule
+	web   *fiber.App
+	ready core.EventFunc[discordgo.Ready]
+	start sync.Once
+}
+
+func NewModule(web *fiber.App) *Module {
+	return &Module{web: web}
+}
+
+func (m *Module) Name() string {
+	return "remote"
+}
+
+func (m *Module) Init(bot *core.Bot) error {
 	// Register the remote module
-	remote := web.Group("/remote/v1")
+	remote := m.web.Group("/remote/v1")
 	remote.Get("/heartbeat", HandleHeartbeat)
-	remote.Get("/activity", HandleGetActivity(client))
+	remote.Get("/activity", HandleGetActivity(bot.SessionFor))
+
+	core.ProvideTo(bot.Container, FiberServerKey, m.web)
 
-	// Register the events
 	onReadyIdent := core.NewIdentifier("remote", "event/setup")
-	onReady := core.ApplyMiddlewares(
+	m.ready = core.ApplyMiddlewares(
 		HandleOnReadyEvent,
-		debug.MidwareContextInject[discordgo.Ready](FiberServerKey, web),
 		debug.MidwarePerformance[discordgo.Ready](onReadyIdent),
 	)
-	client.AddHandler(core.HandleEvent(onReady))
 
 	return nil
 }
+
+// Ready starts the web server, reconnecting to the gateway doesn't start it
+// again.
+func (m *Module) Ready(ctx context.Context, s *discordgo.Session, e *discordgo.Ready) error {
+	var err error
+	m.start.Do(func() {
+		err = m.ready(ctx, s, e)
+	})
+
+	return err
+}
+
+func (m *Module) Shutdown(ctx context.Context) error {
+	return m.web.ShutdownWithContext(ctx)
+}
@@ -1,8 +1,6 @@
 package remote
 
 import (
-	"errors"
-
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 	"github.com/gofiber/fiber/v2"
@@ -10,8 +8,7 @@ import (
 )
 
 var (
-	FiberServerKey   = core.NewIdentifier("remote", "service/fiber")
-	ErrFiberNotFound = errors.New("fiber service not found in context (missing injection)")
+	FiberServerKey = core.NewKey[*fiber.App]("remote", "service/fiber")
 )
 
 func WebMidwareLogger(c *fiber.Ctx) error {
@@ -30,10 +27,12 @@ var (
 	ActivityMemberID = "556132236697665547"
 )
 
-func HandleGetActivity(s *discordgo.Session) func(c *fiber.Ctx) error {
+// HandleGetActivity reads the presence from the state of the shard the guild
+// is on, sessionFor returns its session.
+func HandleGetActivity(sessionFor func(guildId string) *discordgo.Session) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		// Get presence of the user in the guild
-		presence, err := s.State.Presence(ActivityGuildID, ActivityMemberID)
+		presence, err := sessionFor(ActivityGuildID).State.Presence(ActivityGuildID, ActivityMemberID)
 		if err != nil {
 			log.Error().Err(err).Msg("[RemoteActivity]Failed to get presence from user!")
 			return fiber.NewError(fiber.StatusInternalServerError, "Failed to get presence from user!")
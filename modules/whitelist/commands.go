@@ -2,174 +2,125 @@ package whitelist
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 )
 
-var WhitelistCommandPermissions int64 = discordgo.PermissionAdministrator
-
-var WhitelistCommand = &discordgo.ApplicationCommand{
-	Name:                     "whitelist",
-	Description:              "Manage the whitelist for the bot.",
-	DefaultMemberPermissions: &WhitelistCommandPermissions,
-	Options: []*discordgo.ApplicationCommandOption{
-		{
-			Name:        "add",
-			Description: "Add a user to the whitelist.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "user-id",
-					Description: "The id of the user to add to the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-				},
-			},
-		},
-		{
-			Name:        "remove",
-			Description: "Remove a user from the whitelist.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "user-id",
-					Description: "The id of the user to remove from the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionString,
-					Required:    true,
-				},
-			},
-		},
-		{
-			Name:        "list",
-			Description: "List all users on the whitelist.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-		},
-		{
-			Name:        "clear",
-			Description: "Clear the whitelist.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-		},
-		{
-			Name:        "add-all",
-			Description: "Adds all the users in the guild to the whitelist.",
-			Type:        discordgo.ApplicationCommandOptionSubCommand,
-		},
-		{
-			Name:        "config",
-			Description: "Configure the whitelist.",
-			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Name:        "enable",
-					Description: "Enable the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-				},
-				{
-					Name:        "disable",
-					Description: "Disable the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-				},
-				{
-					Name:        "status",
-					Description: "Check the status of the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-				},
-				{
-					Name:        "set-role",
-					Description: "Set the default role for the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-					Options: []*discordgo.ApplicationCommandOption{
-						{
-							Name:        "role",
-							Description: "The role to set as the default role.",
-							Type:        discordgo.ApplicationCommandOptionRole,
-							Required:    true,
-						},
-					},
-				},
-				{
-					Name:        "clear-role",
-					Description: "Clear the default role for the whitelist.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-				},
-				{
-					Name:        "set-remove-on-ban",
-					Description: "Remove users from the whitelist when they are banned.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-					Options: []*discordgo.ApplicationCommandOption{
-						{
-							Name:        "enabled",
-							Description: "Whether or not to remove users from the whitelist when they are banned.",
-							Type:        discordgo.ApplicationCommandOptionBoolean,
-							Required:    true,
-						},
-					},
-				},
-			},
-		},
-	},
-}
-
-func HandleWhitelistCommand(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	// Defers the response
-	if err := s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags: discordgo.MessageFlagsEphemeral,
-		},
-	}); err != nil {
-		return err
-	}
-
-	if ws, ok := ctx.Value(WhitelistManagerKey).(WhitelistManager); !ok || ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
-
-	// Handle the subcommand
-	switch e.ApplicationCommandData().Options[0].Name {
-	case "add":
-		return HandleWhitelistCommandAdd(ctx, s, e)
-	case "remove":
-		return HandleWhitelistCommandRemove(ctx, s, e)
-	case "list":
-		return HandleWhitelistCommandList(ctx, s, e)
-	case "clear":
-		return HandleWhitelistCommandClear(ctx, s, e)
-	case "add-all":
-		return HandleWhitelistCommandAddAll(ctx, s, e)
-	case "config":
-		return HandleWhitelistCommandConfig(ctx, s, e)
-	default:
-		return errors.New("subcommand not yet implemented")
-	}
-}
+var WhitelistCommand = core.NewCommandBuilder().
+	SetName("whitelist").
+	LocalizeDescription(Messages, "command.description").
+	SetDefaultMemberPermissions(discordgo.PermissionAdministrator).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("add").
+			LocalizeDescription(Messages, "command.add.description").
+			AddStringOption(func(s *core.StringOptionBuilder) {
+				s.SetName("user-id").
+					LocalizeDescription(Messages, "command.add.user-id.description").
+					SetRequired(true).
+					SetMinLength(17).
+					SetMaxLength(20)
+			})
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("remove").
+			LocalizeDescription(Messages, "command.remove.description").
+			AddStringOption(func(s *core.StringOptionBuilder) {
+				s.SetName("user-id").
+					LocalizeDescription(Messages, "command.remove.user-id.description").
+					SetRequired(true).
+					SetMinLength(17).
+					SetMaxLength(20)
+			})
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("list").
+			LocalizeDescription(Messages, "command.list.description")
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("clear").
+			LocalizeDescription(Messages, "command.clear.description")
+	}).
+	AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+		subcommand.SetName("add-all").
+			LocalizeDescription(Messages, "command.add-all.description")
+	}).
+	AddSubCommandGroup(func(group *core.SubCommandGroupBuilder) {
+		group.SetName("config").
+			LocalizeDescription(Messages, "command.config.description").
+			AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+				subcommand.SetName("enable").
+					LocalizeDescription(Messages, "command.config.enable.description")
+			}).
+			AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+				subcommand.SetName("disable").
+					LocalizeDescription(Messages, "command.config.disable.description")
+			}).
+			AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+				subcommand.SetName("status").
+					LocalizeDescription(Messages, "command.config.status.description")
+			}).
+			AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+				subcommand.SetName("set-role").
+					LocalizeDescription(Messages, "command.config.set-role.description").
+					AddRoleOption(func(r *core.RoleOptionBuilder) {
+						r.SetName("role").
+							LocalizeDescription(Messages, "command.config.set-role.role.description").
+							SetRequired(true)
+					})
+			}).
+			AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+				subcommand.SetName("clear-role").
+					LocalizeDescription(Messages, "command.config.clear-role.description")
+			}).
+			AddSubCommand(func(subcommand *core.SubCommandBuilder) {
+				subcommand.SetName("set-remove-on-ban").
+					LocalizeDescription(Messages, "command.config.set-remove-on-ban.description").
+					AddBooleanOption(func(b *core.BooleanOptionBuilder) {
+						b.SetName("enabled").
+							LocalizeDescription(Messages, "command.config.set-remove-on-ban.enabled.description").
+							SetRequired(true)
+					})
+			})
+	}).
+	MustBuild()
+
+const WhitelistPageSize = 20
 
 func HandleWhitelistCommandAdd(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the user to add
-	userId := e.ApplicationCommandData().Options[0].Options[0].StringValue()
+	var options struct {
+		UserId string `option:"user-id,required"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
+		return err
+	}
 
 	// Add the user to the whitelist
-	if err := ws.Whitelist(ctx, e.GuildID, userId); err != nil {
+	if err := ws.Whitelist(ctx, e.GuildID, options.UserId); err != nil {
 		return err
 	}
 
+	core.Publish(ctx, &MemberWhitelistedEvent{
+		GuildId:     e.GuildID,
+		UserId:      options.UserId,
+		ModeratorId: core.InteractionUserId(e.Interaction),
+	})
+
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "User Whitelisted",
-		Description: fmt.Sprintf("The user <@%s> has been added to the whitelist.", userId),
+		Title:       t("add.title"),
+		Description: t("add.description", core.Vars{"user": options.UserId}),
 		Color:       core.ColorSuccess,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -179,28 +130,37 @@ func HandleWhitelistCommandAdd(ctx context.Context, s *discordgo.Session, e *dis
 }
 
 func HandleWhitelistCommandRemove(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the user to remove
-	userId := e.ApplicationCommandData().Options[0].Options[0].StringValue()
+	var options struct {
+		UserId string `option:"user-id,required"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
+		return err
+	}
 
 	// Remove the user from the whitelist
-	if err := ws.Unwhitelist(ctx, e.GuildID, userId); err != nil {
+	if err := ws.Unwhitelist(ctx, e.GuildID, options.UserId); err != nil {
 		return err
 	}
 
+	core.Publish(ctx, &MemberUnwhitelistedEvent{
+		GuildId:     e.GuildID,
+		UserId:      options.UserId,
+		ModeratorId: core.InteractionUserId(e.Interaction),
+	})
+
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "User Removed from Whitelist",
-		Description: fmt.Sprintf("The user <@%s> has been removed from the whitelist.", userId),
+		Title:       t("remove.title"),
+		Description: t("remove.description", core.Vars{"user": options.UserId}),
 		Color:       core.ColorWarning,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -210,10 +170,8 @@ func HandleWhitelistCommandRemove(ctx context.Context, s *discordgo.Session, e *
 }
 
 func HandleWhitelistCommandList(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the whitelist
 	whitelist, err := ws.GetWhitelist(ctx, e.GuildID)
@@ -221,28 +179,53 @@ func HandleWhitelistCommandList(ctx context.Context, s *discordgo.Session, e *di
 		return err
 	}
 
-	// Respond to the interaction (deferred)
-	embed := &discordgo.MessageEmbed{
-		Title:       "Whitelist",
-		Description: fmt.Sprintf("There are %d users on the whitelist.", len(whitelist)),
-		Color:       core.ColorInfo,
-	}
+	if len(whitelist) == 0 {
+		embed := &discordgo.MessageEmbed{
+			Title:       t("list.title"),
+			Description: t("list.description", core.Vars{"count": 0}),
+			Color:       core.ColorInfo,
+		}
 
-	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
-		Embeds: &[]*discordgo.MessageEmbed{embed},
-	}); err != nil {
-		return err
+		// Edit the response
+		if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
+			Embeds: &[]*discordgo.MessageEmbed{embed},
+		}); err != nil {
+			return err
+		}
+
+		return nil
 	}
 
-	return nil
+	// Page through the whitelisted users
+	pages := core.ChunkPages(whitelist, WhitelistPageSize, func(users []string, index int) *discordgo.MessageEmbed {
+		lines := make([]string, 0, len(users))
+		for i, userId := range users {
+			lines = append(lines, fmt.Sprintf("%d. <@%s>", index*WhitelistPageSize+i+1, userId))
+		}
+
+		return &discordgo.MessageEmbed{
+			Title:       t("list.title"),
+			Description: t("list.description", core.Vars{"count": len(whitelist)}) + "\n\n" + strings.Join(lines, "\n"),
+			Color:       core.ColorInfo,
+		}
+	})
+
+	return core.NewPaginator(pages).Send(ctx, s, e.Interaction)
 }
 
 func HandleWhitelistCommandClear(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	t := Messages.For(core.InteractionLocale(e.Interaction))
+
+	return core.Confirm(ctx, s, e.Interaction, &core.ConfirmPrompt{
+		Title:        t("clear.prompt.title"),
+		Description:  t("clear.prompt.description"),
+		ConfirmLabel: t("clear.prompt.confirm"),
+	}, clearWhitelist)
+}
+
+func clearWhitelist(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Clear the whitelist
 	if err := ws.ClearWhitelist(ctx, e.GuildID); err != nil {
@@ -251,13 +234,13 @@ func HandleWhitelistCommandClear(ctx context.Context, s *discordgo.Session, e *d
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Whitelist Cleared",
-		Description: "The whitelist has been cleared.",
+		Title:       t("clear.title"),
+		Description: t("clear.description"),
 		Color:       core.ColorSuccess,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -267,10 +250,18 @@ func HandleWhitelistCommandClear(ctx context.Context, s *discordgo.Session, e *d
 }
 
 func HandleWhitelistCommandAddAll(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	t := Messages.For(core.InteractionLocale(e.Interaction))
+
+	return core.Confirm(ctx, s, e.Interaction, &core.ConfirmPrompt{
+		Title:        t("add-all.prompt.title"),
+		Description:  t("add-all.prompt.description"),
+		ConfirmLabel: t("add-all.prompt.confirm"),
+	}, whitelistAllMembers)
+}
+
+func whitelistAllMembers(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the members
 	members, err := s.GuildMembers(e.GuildID, "", 1000)
@@ -291,13 +282,13 @@ func HandleWhitelistCommandAddAll(ctx context.Context, s *discordgo.Session, e *
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "All Users Whitelisted",
-		Description: fmt.Sprintf("All %d users in the guild have been added to the whitelist.", len(members)),
+		Title:       t("add-all.title"),
+		Description: t("add-all.description", core.Vars{"count": len(members)}),
 		Color:       core.ColorSuccess,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -306,36 +297,9 @@ func HandleWhitelistCommandAddAll(ctx context.Context, s *discordgo.Session, e *
 	return nil
 }
 
-func HandleWhitelistCommandConfig(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
-
-	// Handle the subcommand
-	switch e.ApplicationCommandData().Options[0].Options[0].Name {
-	case "enable":
-		return HandleWhitelistCommandConfigEnable(ctx, s, e)
-	case "disable":
-		return HandleWhitelistCommandConfigDisable(ctx, s, e)
-	case "status":
-		return HandleWhitelistCommandConfigStatus(ctx, s, e)
-	case "set-role":
-		return HandleWhitelistCommandConfigSetRole(ctx, s, e)
-	case "clear-role":
-		return HandleWhitelistCommandConfigClearRole(ctx, s, e)
-	case "set-remove-on-ban":
-		return HandleWhitelistCommandConfigSetRemoveOnBan(ctx, s, e)
-	default:
-		return errors.New("subcommand not yet implemented")
-	}
-}
-
 func HandleWhitelistCommandConfigEnable(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Enable the whitelist
 	if err := ws.SetEnabled(ctx, e.GuildID, true); err != nil {
@@ -344,13 +308,13 @@ func HandleWhitelistCommandConfigEnable(ctx context.Context, s *discordgo.Sessio
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Whitelist Enabled",
-		Description: "The whitelist has been enabled.",
+		Title:       t("enable.title"),
+		Description: t("enable.description"),
 		Color:       core.ColorSuccess,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -360,10 +324,8 @@ func HandleWhitelistCommandConfigEnable(ctx context.Context, s *discordgo.Sessio
 }
 
 func HandleWhitelistCommandConfigDisable(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Disable the whitelist
 	if err := ws.SetEnabled(ctx, e.GuildID, false); err != nil {
@@ -372,13 +334,13 @@ func HandleWhitelistCommandConfigDisable(ctx context.Context, s *discordgo.Sessi
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Whitelist Disabled",
-		Description: "The whitelist has been disabled.",
+		Title:       t("disable.title"),
+		Description: t("disable.description"),
 		Color:       core.ColorWarning,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -388,23 +350,21 @@ func HandleWhitelistCommandConfigDisable(ctx context.Context, s *discordgo.Sessi
 }
 
 func HandleWhitelistCommandConfigStatus(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the status
 	enabled := ws.GetEnabled(ctx, e.GuildID)
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Whitelist Status",
-		Description: fmt.Sprintf("The whitelist is currently %s.", map[bool]string{true: "enabled", false: "disabled"}[enabled]),
+		Title:       t("status.title"),
+		Description: t(map[bool]string{true: "status.enabled", false: "status.disabled"}[enabled]),
 		Color:       core.ColorInfo,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -414,28 +374,31 @@ func HandleWhitelistCommandConfigStatus(ctx context.Context, s *discordgo.Sessio
 }
 
 func HandleWhitelistCommandConfigSetRole(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the role to set
-	role := e.ApplicationCommandData().Options[0].Options[0].Options[0].RoleValue(s, e.GuildID)
+	var options struct {
+		Role *discordgo.Role `option:"role,required"`
+	}
+	if err := core.BindInteractionOptions(s, e.Interaction, &options); err != nil {
+		return err
+	}
 
 	// Set the default role
-	if err := ws.SetDefaultRole(ctx, e.GuildID, role.ID); err != nil {
+	if err := ws.SetDefaultRole(ctx, e.GuildID, options.Role.ID); err != nil {
 		return err
 	}
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Default Role Set",
-		Description: fmt.Sprintf("The default role has been set to <@&%s>.", role.ID),
+		Title:       t("set-role.title"),
+		Description: t("set-role.description", core.Vars{"role": options.Role.ID}),
 		Color:       core.ColorSuccess,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -445,10 +408,8 @@ func HandleWhitelistCommandConfigSetRole(ctx context.Context, s *discordgo.Sessi
 }
 
 func HandleWhitelistCommandConfigClearRole(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Clear the default role
 	if err := ws.SetDefaultRole(ctx, e.GuildID, ""); err != nil {
@@ -457,13 +418,13 @@ func HandleWhitelistCommandConfigClearRole(ctx context.Context, s *discordgo.Ses
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Default Role Cleared",
-		Description: "The default role has been cleared.",
+		Title:       t("clear-role.title"),
+		Description: t("clear-role.description"),
 		Color:       core.ColorWarning,
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
@@ -473,28 +434,31 @@ func HandleWhitelistCommandConfigClearRole(ctx context.Context, s *discordgo.Ses
 }
 
 func HandleWhitelistCommandConfigSetRemoveOnBan(ctx context.Context, s *discordgo.Session, e *discordgo.InteractionCreate) error {
-	ws := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if ws == nil {
-		return ErrWhitelistManagerNotFound
-	}
+	ws := core.Use(ctx, WhitelistManagerKey)
+	t := Messages.For(core.InteractionLocale(e.Interaction))
 
 	// Get the enabled value
-	enabled := e.ApplicationCommandData().Options[0].Options[0].Options[0].BoolValue()
+	var options struct {
+		Enabled bool `option:"enabled,required"`
+	}
+	if err := core.BindOptions(e.ApplicationCommandData(), &options); err != nil {
+		return err
+	}
 
 	// Set the remove on ban
-	if err := ws.SetRemoveOnBan(ctx, e.GuildID, enabled); err != nil {
+	if err := ws.SetRemoveOnBan(ctx, e.GuildID, options.Enabled); err != nil {
 		return err
 	}
 
 	// Respond to the interaction (deferred)
 	embed := &discordgo.MessageEmbed{
-		Title:       "Remove on Ban Set",
-		Description: fmt.Sprintf("Users will now %s be removed from the whitelist when they are banned.", map[bool]string{true: "", false: "not"}[enabled]),
-		Color:       map[bool]int{true: core.ColorSuccess, false: core.ColorWarning}[enabled],
+		Title:       t("remove-on-ban.title"),
+		Description: t(map[bool]string{true: "remove-on-ban.enabled", false: "remove-on-ban.disabled"}[options.Enabled]),
+		Color:       map[bool]int{true: core.ColorSuccess, false: core.ColorWarning}[options.Enabled],
 	}
 
 	// Edit the response
-	if _, err := s.InteractionResponseEdit(e.Interaction, &discordgo.WebhookEdit{
+	if err := core.Respond(ctx, s, e.Interaction).Edit(&discordgo.WebhookEdit{
 		Embeds: &[]*discordgo.MessageEmbed{embed},
 	}); err != nil {
 		return err
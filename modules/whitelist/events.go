@@ -3,13 +3,35 @@ package whitelist
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/downloadablefox/twotto/core"
 	"github.com/rs/zerolog/log"
 )
 
+// MemberWhitelistedEvent is published when a moderator adds a user to the
+// whitelist of a guild.
+type MemberWhitelistedEvent struct {
+	GuildId     string
+	UserId      string
+	ModeratorId string
+}
+
+// MemberUnwhitelistedEvent is published when a moderator removes a user from
+// the whitelist of a guild.
+type MemberUnwhitelistedEvent struct {
+	GuildId     string
+	UserId      string
+	ModeratorId string
+}
+
+// MemberKickedEvent is published when a member who isn't whitelisted gets
+// kicked as they join.
+type MemberKickedEvent struct {
+	GuildId string
+	User    *discordgo.User
+}
+
 func CreateKickInfoEmbed(session *discordgo.Session, userId string, guildId string) (*discordgo.MessageEmbed, error) {
 	user, err := session.User(userId)
 	if err != nil {
@@ -21,14 +43,21 @@ func CreateKickInfoEmbed(session *discordgo.Session, userId string, guildId stri
 		return nil, errors.New("failed to create kick info embed, guild not found")
 	}
 
+	t := Messages.For(discordgo.Locale(guild.PreferredLocale))
+
+	contact := core.OwnerMention()
+	if contact == "" {
+		contact = t("kick.contact.fallback")
+	}
+
 	return &discordgo.MessageEmbed{
-		Title:       "Sorry! :(",
-		Description: fmt.Sprintf("You have been kicked from the server `%s` because you are not whitelisted.\nIf this is an error please contact <@556132236697665547> and give her your user ID.", guild.Name),
+		Title:       t("kick.title"),
+		Description: t("kick.description", core.Vars{"guild": guild.Name, "contact": contact}),
 		Color:       core.ColorWarning,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:  "User ID",
-				Value: fmt.Sprintf("Your user ID is: `%s`", user.ID),
+				Name:  t("kick.user-id.name"),
+				Value: t("kick.user-id.value", core.Vars{"user": user.ID}),
 			},
 		},
 	}, nil
@@ -37,10 +66,7 @@ func CreateKickInfoEmbed(session *discordgo.Session, userId string, guildId stri
 func HandleOnJoinEvent(ctx context.Context, s *discordgo.Session, e *discordgo.GuildMemberAdd) error {
 	log.Info().Msgf("[WhitelistModule] User %s (%s) joined guild %s", e.User, e.User.ID, e.GuildID)
 
-	wm, ok := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if !ok {
-		return ErrWhitelistManagerNotFound
-	}
+	wm := core.Use(ctx, WhitelistManagerKey)
 
 	// Ignore bots
 	if e.User.Bot {
@@ -65,6 +91,11 @@ func HandleOnJoinEvent(ctx context.Context, s *discordgo.Session, e *discordgo.G
 			return err
 		}
 
+		core.Publish(ctx, &MemberKickedEvent{
+			GuildId: e.GuildID,
+			User:    e.User,
+		})
+
 		return nil
 	}
 
@@ -78,10 +109,7 @@ func HandleOnJoinEvent(ctx context.Context, s *discordgo.Session, e *discordgo.G
 }
 
 func HandleOnBanEvent(ctx context.Context, s *discordgo.Session, e *discordgo.GuildBanAdd) error {
-	wm, ok := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if !ok {
-		return ErrWhitelistManagerNotFound
-	}
+	wm := core.Use(ctx, WhitelistManagerKey)
 
 	// Ignore bots
 	if e.User.Bot {
@@ -98,21 +126,3 @@ func HandleOnBanEvent(ctx context.Context, s *discordgo.Session, e *discordgo.Gu
 
 	return nil
 }
-
-func HandleOnReadyEvent(ctx context.Context, s *discordgo.Session, e *discordgo.Ready) error {
-	_, ok := ctx.Value(WhitelistManagerKey).(WhitelistManager)
-	if !ok {
-		log.Warn().Msg("[WhitelistModule] Whitelist manager not found in context (missing injection), failed to register commands")
-		return ErrWhitelistManagerNotFound
-	}
-
-	// Register
-	if err := core.ApplyCommands(
-		WhitelistCommand,
-	).For(s, ""); err != nil {
-		log.Warn().Msg("[WhitelistModule] Failed to register commands")
-		return err
-	}
-
-	return nil
-}
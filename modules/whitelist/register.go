@@ -6,33 +6,60 @@ import (
 	"github.com/downloadablefox/twotto/modules/debug"
 )
 
-func RegisterModule(client *discordgo.Session, whitelist WhitelistManager) {
-	onReadyIdent := core.NewIdentifier("whitelist", "events/setup")
-	onReady := core.ApplyMiddlewares(
-		HandleOnReadyEvent,
-		debug.MidwareContextInject[discordgo.Ready](WhitelistManagerKey, whitelist),
-		debug.MidwarePerformance[discordgo.Ready](onReadyIdent),
-	)
-	client.AddHandler(core.HandleEvent(onReady))
-
-	onJoin := core.ApplyMiddlewares(
-		HandleOnJoinEvent,
-		debug.MidwareContextInject[discordgo.GuildMemberAdd](WhitelistManagerKey, whitelist),
-	)
-	client.AddHandler(core.HandleEvent(onJoin))
-
-	onBan := core.ApplyMiddlewares(
-		HandleOnBanEvent,
-		debug.MidwareContextInject[discordgo.GuildBanAdd](WhitelistManagerKey, whitelist),
-	)
-	client.AddHandler(core.HandleEvent(onBan))
-
-	whitelistCommandIndent := core.NewIdentifier("whitelist", "commands/whitelist")
-	whitelistCommand := core.ApplyMiddlewares(
-		HandleWhitelistCommand,
-		debug.MidwareContextInject[discordgo.InteractionCreate](WhitelistManagerKey, whitelist),
-		debug.MidwareForCommand(WhitelistCommand),
-		debug.MidwareErrorWrap(whitelistCommandIndent),
-	)
-	client.AddHandler(core.HandleEvent(whitelistCommand))
+type Module struct {
+	core.BaseModule
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) Name() string {
+	return "whitelist"
+}
+
+func (m *Module) Commands() []*core.Command {
+	whitelistCommandIdent := core.NewIdentifier("whitelist", "commands/whitelist")
+
+	return []*core.Command{
+		{
+			Identifier: whitelistCommandIdent,
+			Definition: WhitelistCommand,
+			Subcommands: map[string]core.EventFunc[discordgo.InteractionCreate]{
+				"add":                      HandleWhitelistCommandAdd,
+				"remove":                   HandleWhitelistCommandRemove,
+				"list":                     HandleWhitelistCommandList,
+				"clear":                    HandleWhitelistCommandClear,
+				"add-all":                  HandleWhitelistCommandAddAll,
+				"config enable":            HandleWhitelistCommandConfigEnable,
+				"config disable":           HandleWhitelistCommandConfigDisable,
+				"config status":            HandleWhitelistCommandConfigStatus,
+				"config set-role":          HandleWhitelistCommandConfigSetRole,
+				"config clear-role":        HandleWhitelistCommandConfigClearRole,
+				"config set-remove-on-ban": HandleWhitelistCommandConfigSetRemoveOnBan,
+			},
+			Middlewares: []core.MiddlewareFunc[discordgo.InteractionCreate]{
+				core.MidwareRequirePermissions(discordgo.PermissionAdministrator),
+				debug.MidwareDeferResponse(discordgo.MessageFlagsEphemeral),
+				debug.MidwareErrorWrap(whitelistCommandIdent),
+			},
+			Requires: []core.Dependency{WhitelistManagerKey},
+		},
+	}
+}
+
+func (m *Module) Init(bot *core.Bot) error {
+	if err := core.AddEventHandler(bot, &core.EventHandler[discordgo.GuildMemberAdd]{
+		Identifier: core.NewIdentifier("whitelist", "events/member-join"),
+		Handler:    HandleOnJoinEvent,
+		Requires:   []core.Dependency{WhitelistManagerKey},
+	}); err != nil {
+		return err
+	}
+
+	return core.AddEventHandler(bot, &core.EventHandler[discordgo.GuildBanAdd]{
+		Identifier: core.NewIdentifier("whitelist", "events/member-ban"),
+		Handler:    HandleOnBanEvent,
+		Requires:   []core.Dependency{WhitelistManagerKey},
+	})
 }
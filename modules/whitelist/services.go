@@ -9,12 +9,11 @@ import (
 )
 
 var (
-	WhitelistManagerKey         = core.NewIdentifier("whitelist", "service/manager")
-	ErrWhitelisted              = errors.New("user already whitelisted")
-	ErrNotWhitelisted           = errors.New("user not whitelisted")
-	ErrNotInGuild               = errors.New("user not in guild")
-	ErrDefaultRoleNotFound      = errors.New("default role not set")
-	ErrWhitelistManagerNotFound = errors.New("whitelist manager not found in context (missing injection)")
+	WhitelistManagerKey    = core.NewKey[WhitelistManager]("whitelist", "service/manager")
+	ErrWhitelisted         = errors.New("user already whitelisted")
+	ErrNotWhitelisted      = errors.New("user not whitelisted")
+	ErrNotInGuild          = errors.New("user not in guild")
+	ErrDefaultRoleNotFound = errors.New("default role not set")
 )
 
 type WhitelistManager interface {
@@ -0,0 +1,141 @@
+package whitelist_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/downloadablefox/twotto/core"
+	"github.com/downloadablefox/twotto/core/discordtest"
+	"github.com/downloadablefox/twotto/modules/internal/memory"
+	"github.com/downloadablefox/twotto/modules/whitelist"
+)
+
+// setup loads the whitelist module on a bot connected to the server, with a
+// guild administrated by the returned member.
+func setup(t *testing.T, server *discordtest.Server, manager whitelist.WhitelistManager) (*core.Bot, *discordgo.Guild, *discordgo.Member) {
+	t.Helper()
+
+	session := server.Session()
+	bot := discordtest.LoadModules(t, session, func(bot *core.Bot) {
+		core.ProvideTo(bot.Container, whitelist.WhitelistManagerKey, manager)
+	}, whitelist.NewModule())
+
+	guild := server.AddGuild(&discordgo.Guild{Name: "Den"})
+	admin := server.AddMember(guild.ID, &discordgo.Member{
+		User:        &discordgo.User{Username: "admin"},
+		Permissions: discordgo.PermissionAdministrator,
+	})
+
+	if err := session.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		session.Close()
+	})
+
+	return bot, guild, admin
+}
+
+func TestWhitelistAddCommand(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	manager := memory.NewWhitelist()
+	bot, guild, admin := setup(t, server, manager)
+
+	published := make(chan *whitelist.MemberWhitelistedEvent, 1)
+	err := core.Subscribe(bot, &core.EventHandler[whitelist.MemberWhitelistedEvent]{
+		Identifier: core.NewIdentifier("test", "events/member-whitelisted"),
+		Handler: func(_ context.Context, _ *discordgo.Session, e *whitelist.MemberWhitelistedEvent) error {
+			published <- e
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const userId = "123456789012345678"
+	e := server.Command("whitelist", []*discordgo.ApplicationCommandInteractionDataOption{
+		discordtest.Subcommand("add", discordtest.StringOption("user-id", userId)),
+	}, discordtest.InGuild(guild.ID, admin))
+	if err := server.Dispatch(e); err != nil {
+		t.Fatal(err)
+	}
+
+	edit, err := server.WaitFor("PATCH", "/webhooks/{app}/"+e.Token+"/messages/@original", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The response is deferred as ephemeral, then edited with the result
+	callback, err := server.WaitFor("POST", "/interactions/"+e.ID+"/{token}/callback", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response discordgo.InteractionResponse
+	if err := callback.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || response.Data == nil || response.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("got callback %+v, want an ephemeral deferred response", response)
+	}
+
+	var body discordgo.WebhookEdit
+	if err := edit.Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Embeds == nil || len(*body.Embeds) != 1 || (*body.Embeds)[0].Title != "User Whitelisted" {
+		t.Errorf("got edit %+v, want the whitelisted embed", body)
+	}
+
+	if !manager.IsWhitelisted(context.Background(), guild.ID, userId) {
+		t.Error("the user wasn't whitelisted")
+	}
+
+	select {
+	case event := <-published:
+		if event.GuildId != guild.ID || event.UserId != userId || event.ModeratorId != admin.User.ID {
+			t.Errorf("got event %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("the whitelisted event wasn't published")
+	}
+}
+
+func TestWhitelistCommandRequiresAdministrator(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	manager := memory.NewWhitelist()
+	_, guild, _ := setup(t, server, manager)
+
+	member := server.AddMember(guild.ID, &discordgo.Member{
+		User:        &discordgo.User{Username: "member"},
+		Permissions: discordgo.PermissionSendMessages,
+	})
+
+	e := server.Command("whitelist", []*discordgo.ApplicationCommandInteractionDataOption{
+		discordtest.Subcommand("add", discordtest.StringOption("user-id", "123456789012345678")),
+	}, discordtest.InGuild(guild.ID, member))
+	if err := server.Dispatch(e); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := server.WaitFor("POST", "/interactions/"+e.ID+"/{token}/callback", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if callbacks := server.Callbacks(e.Interaction); len(callbacks) != 1 || callbacks[0] != discordgo.InteractionResponseChannelMessageWithSource {
+		t.Errorf("got callbacks %v, want the denial as the reply", callbacks)
+	}
+	if original := server.Original(e.Interaction); original == nil || original.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Errorf("got original response %+v, want an ephemeral denial", original)
+	}
+	if users, _ := manager.GetWhitelist(context.Background(), guild.ID); len(users) != 0 {
+		t.Errorf("got whitelist %q, want it untouched", users)
+	}
+}